@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/configs"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// create the first admin account, or promote an existing user to admin
+//
+//	go run ./cmd/createadmin -email admin@mail.com -password 'Admin@testing123'
+//
+// the password can also be given in the ADMIN_PASSWORD env variable, it is only needed for a new account
+func main() {
+	email := flag.String("email", "", "email of the admin account")
+	password := flag.String("password", os.Getenv("ADMIN_PASSWORD"), "password of a new admin account")
+	flag.Parse()
+
+	if err := utils.IsValidEmail(*email); err != nil {
+		log.Fatal("Invalid email: ", err)
+	}
+
+	db, err := configs.InitDB()
+	if err != nil {
+		log.Fatal("DB init failed:", err)
+	}
+	defer db.Close()
+
+	rdb, err := configs.InitRedis()
+	if err != nil {
+		log.Println("RDB init failed:", err)
+	}
+	if rdb != nil {
+		defer rdb.Close()
+	}
+
+	ctx := context.Background()
+	userRepo := repositories.NewUserRepository(db)
+	adminRepo := repositories.NewAdminRepository(db, time.Duration(configs.GetEnvInt("SCHEDULE_CLEANING_MINUTES", 15))*time.Minute)
+	revocations := repositories.NewTokenRevocationRepository(db, rdb)
+
+	user, err := userRepo.GetUserByEmail(ctx, *email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if err := utils.IsValidPassword(*password); err != nil {
+			log.Fatal("Invalid password: ", err)
+		}
+
+		hashedPass, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+		if err != nil {
+			log.Fatal("Hash password failed: ", err)
+		}
+		va, err := utils.GenerateVirtualAccount()
+		if err != nil {
+			log.Fatal("Generate virtual account failed: ", err)
+		}
+
+		user = &models.User{
+			Email:          *email,
+			Password:       string(hashedPass),
+			Role:           models.RoleAdmin,
+			VirtualAccount: va,
+		}
+		if err := userRepo.RegisterUser(ctx, user); err != nil {
+			log.Fatal("Create admin failed: ", err)
+		}
+		if _, err := userRepo.VerifyEmail(ctx, user.ID, user.Email); err != nil {
+			log.Fatal("Verify admin email failed: ", err)
+		}
+
+		log.Printf("Admin %s created with id %d", user.Email, user.ID)
+		return
+	}
+	if err != nil {
+		log.Fatal("Get user failed: ", err)
+	}
+
+	if user.Role == models.RoleAdmin && user.SuspendedAt == nil {
+		log.Printf("User %s is already an admin", user.Email)
+		return
+	}
+
+	if _, err := adminRepo.UpdateUserRole(ctx, user.ID, models.RoleAdmin, nil); err != nil {
+		log.Fatal("Promote user failed: ", err)
+	}
+	if _, err := userRepo.VerifyEmail(ctx, user.ID, user.Email); err != nil {
+		log.Fatal("Verify admin email failed: ", err)
+	}
+	if _, err := revocations.ReactivateUser(ctx, user.ID); err != nil {
+		log.Fatal("Reactivate user failed: ", err)
+	}
+	// tokens still carry the old role
+	if err := revocations.RevokeAllUserTokens(ctx, user.ID, models.SessionRevokedRoleChange); err != nil {
+		log.Fatal("Revoke user tokens failed: ", err)
+	}
+
+	log.Printf("User %s promoted to admin", user.Email)
+}
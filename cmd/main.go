@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/configs"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/routers"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/workers"
 )
 
 // @title Tickitz Booking API
@@ -33,6 +37,20 @@ func main() {
 		defer rdb.Close()
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// expire unpaid orders and free their seats
+	paymentWindow := time.Duration(configs.GetEnvInt("PAYMENT_EXPIRY_MINUTES", 15)) * time.Minute
+	expiryInterval := time.Duration(configs.GetEnvInt("ORDER_EXPIRY_INTERVAL_SECONDS", 60)) * time.Second
+	orderExpiryWorker := workers.NewOrderExpiryWorker(repositories.NewOrdersRepository(db), rdb, paymentWindow, expiryInterval)
+	go orderExpiryWorker.Start(ctx)
+
+	// finish the cancellations whose refund was made but whose order was not updated
+	refundInterval := time.Duration(configs.GetEnvInt("REFUND_RECONCILE_INTERVAL_SECONDS", 60)) * time.Second
+	refundReconcileWorker := workers.NewRefundReconcileWorker(repositories.NewOrdersRepository(db), rdb, refundInterval)
+	go refundReconcileWorker.Start(ctx)
+
 	r := routers.MainRouter(db, rdb)
 
 	r.Run(":8080")
@@ -0,0 +1 @@
+DROP TABLE public.auditoriums;
\ No newline at end of file
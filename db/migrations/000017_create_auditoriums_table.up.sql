@@ -0,0 +1,30 @@
+-- public.auditoriums definition
+-- Drop table
+-- DROP TABLE public.auditoriums;
+CREATE TABLE
+    public.auditoriums (
+        id serial4 NOT NULL,
+        cinemas_id int4 NOT NULL,
+        "name" varchar(50) NOT NULL,
+        seat_rows int4 NOT NULL,
+        seat_columns int4 NOT NULL,
+        aisles int4[] DEFAULT '{}' NOT NULL,
+        created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+        updated_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+        CONSTRAINT auditoriums_pkey PRIMARY KEY (id),
+        CONSTRAINT auditoriums_cinemas_id_name_key UNIQUE (cinemas_id, "name"),
+        CONSTRAINT auditoriums_seat_rows_check CHECK (seat_rows BETWEEN 1 AND 26),
+        CONSTRAINT auditoriums_seat_columns_check CHECK (seat_columns BETWEEN 1 AND 50),
+        CONSTRAINT auditoriums_cinemas_id_fkey FOREIGN KEY (cinemas_id) REFERENCES public.cinemas (id) ON DELETE CASCADE
+    );
+
+-- default studio for every existing cinema, sized from the current seat list
+INSERT INTO
+    public.auditoriums (cinemas_id, "name", seat_rows, seat_columns)
+SELECT
+    c.id,
+    'Studio 1',
+    GREATEST((SELECT COUNT(DISTINCT substring(seat_number FROM '^[A-Z]+')) FROM public.seats), 1),
+    GREATEST((SELECT MAX(substring(seat_number FROM '[0-9]+$')::int) FROM public.seats), 1)
+FROM
+    public.cinemas c;
@@ -0,0 +1,14 @@
+ALTER TABLE public.seats DROP CONSTRAINT seats_auditorium_id_fkey;
+ALTER TABLE public.seats DROP CONSTRAINT seats_seat_type_check;
+ALTER TABLE public.seats DROP CONSTRAINT seats_auditorium_id_seat_number_key;
+
+DELETE FROM public.seats
+WHERE
+    auditorium_id <> (SELECT MIN(id) FROM public.auditoriums);
+
+ALTER TABLE public.seats
+    DROP COLUMN auditorium_id,
+    DROP COLUMN seat_row,
+    DROP COLUMN seat_column,
+    ALTER COLUMN seat_type DROP NOT NULL,
+    ALTER COLUMN seat_type DROP DEFAULT;
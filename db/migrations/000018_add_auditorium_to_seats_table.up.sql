@@ -0,0 +1,49 @@
+-- seats now belong to an auditorium layout
+ALTER TABLE public.seats
+    ADD COLUMN auditorium_id int4 NULL,
+    ADD COLUMN seat_row varchar(2) NULL,
+    ADD COLUMN seat_column int4 NULL;
+
+UPDATE public.seats
+SET
+    seat_row = substring(seat_number FROM '^[A-Z]+'),
+    seat_column = substring(seat_number FROM '[0-9]+$')::int,
+    seat_type = CASE lower(COALESCE(seat_type, 'regular'))
+        WHEN 'love nest' THEN 'loveseat'
+        ELSE lower(COALESCE(seat_type, 'regular'))
+    END;
+
+-- the old global seat list becomes the layout of the first auditorium,
+-- every other auditorium gets its own copy
+UPDATE public.seats
+SET
+    auditorium_id = (SELECT MIN(id) FROM public.auditoriums);
+
+INSERT INTO
+    public.seats (seat_number, seat_type, seat_row, seat_column, auditorium_id)
+SELECT
+    s.seat_number,
+    s.seat_type,
+    s.seat_row,
+    s.seat_column,
+    a.id
+FROM
+    public.seats s
+    CROSS JOIN public.auditoriums a
+WHERE
+    s.auditorium_id = (SELECT MIN(id) FROM public.auditoriums)
+    AND a.id <> s.auditorium_id;
+
+ALTER TABLE public.seats
+    ALTER COLUMN auditorium_id SET NOT NULL,
+    ALTER COLUMN seat_row SET NOT NULL,
+    ALTER COLUMN seat_column SET NOT NULL,
+    ALTER COLUMN seat_type SET DEFAULT 'regular',
+    ALTER COLUMN seat_type SET NOT NULL;
+
+ALTER TABLE public.seats ADD CONSTRAINT seats_auditorium_id_seat_number_key UNIQUE (auditorium_id, seat_number);
+
+ALTER TABLE public.seats ADD CONSTRAINT seats_seat_type_check CHECK (((seat_type)::text = ANY ((ARRAY['regular'::character varying, 'vip'::character varying, 'loveseat'::character varying])::text[])));
+
+-- public.seats foreign keys
+ALTER TABLE public.seats ADD CONSTRAINT seats_auditorium_id_fkey FOREIGN KEY (auditorium_id) REFERENCES public.auditoriums (id) ON DELETE CASCADE;
@@ -0,0 +1,15 @@
+ALTER TABLE public.cinemas_schedules DROP CONSTRAINT cinemas_schedules_auditorium_id_fkey;
+
+UPDATE public.orders_seats os
+SET
+    seat_id = target.id
+FROM
+    public.seats src,
+    public.seats target
+WHERE
+    os.seat_id = src.id
+    AND target.seat_number = src.seat_number
+    AND target.auditorium_id = (SELECT MIN(id) FROM public.auditoriums)
+    AND src.auditorium_id <> target.auditorium_id;
+
+ALTER TABLE public.cinemas_schedules DROP COLUMN auditorium_id;
@@ -0,0 +1,30 @@
+-- every screening runs in a specific auditorium of the cinema
+ALTER TABLE public.cinemas_schedules ADD COLUMN auditorium_id int4 NULL;
+
+UPDATE public.cinemas_schedules cs
+SET
+    auditorium_id = (
+        SELECT MIN(a.id)
+        FROM public.auditoriums a
+        WHERE a.cinemas_id = cs.cinemas_id
+    );
+
+-- point booked seats at the copy of the seat inside the screening's auditorium
+UPDATE public.orders_seats os
+SET
+    seat_id = target.id
+FROM
+    public.orders o,
+    public.cinemas_schedules cs,
+    public.seats src,
+    public.seats target
+WHERE
+    os.order_id = o.id
+    AND o.cinemas_schedule_id = cs.id
+    AND os.seat_id = src.id
+    AND target.auditorium_id = cs.auditorium_id
+    AND target.seat_number = src.seat_number
+    AND src.auditorium_id <> cs.auditorium_id;
+
+-- public.cinemas_schedules foreign keys
+ALTER TABLE public.cinemas_schedules ADD CONSTRAINT cinemas_schedules_auditorium_id_fkey FOREIGN KEY (auditorium_id) REFERENCES public.auditoriums (id) ON DELETE CASCADE;
@@ -0,0 +1 @@
+DROP TABLE public.seat_holds;
\ No newline at end of file
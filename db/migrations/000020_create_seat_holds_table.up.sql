@@ -0,0 +1,19 @@
+-- public.seat_holds definition
+-- Drop table
+-- DROP TABLE public.seat_holds;
+CREATE TABLE
+    public.seat_holds (
+        id serial4 NOT NULL,
+        cinemas_schedule_id int4 NOT NULL,
+        seat_id int4 NOT NULL,
+        user_id int4 NOT NULL,
+        expires_at timestamp NOT NULL,
+        created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+        CONSTRAINT seat_holds_pkey PRIMARY KEY (id),
+        CONSTRAINT seat_holds_cinemas_schedule_id_seat_id_key UNIQUE (cinemas_schedule_id, seat_id),
+        CONSTRAINT seat_holds_cinemas_schedule_id_fkey FOREIGN KEY (cinemas_schedule_id) REFERENCES public.cinemas_schedules (id) ON DELETE CASCADE,
+        CONSTRAINT seat_holds_seat_id_fkey FOREIGN KEY (seat_id) REFERENCES public.seats (id) ON DELETE CASCADE,
+        CONSTRAINT seat_holds_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX seat_holds_expires_at_idx ON public.seat_holds (expires_at);
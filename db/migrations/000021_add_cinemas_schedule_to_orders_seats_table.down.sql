@@ -0,0 +1,2 @@
+DROP INDEX public.orders_seats_schedule_seat_booked_key;
+ALTER TABLE public.orders_seats DROP COLUMN cinemas_schedule_id;
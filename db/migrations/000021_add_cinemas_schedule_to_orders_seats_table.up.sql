@@ -0,0 +1,33 @@
+-- a seat can only be booked once per screening
+ALTER TABLE public.orders_seats ADD COLUMN cinemas_schedule_id int4 NULL;
+
+UPDATE public.orders_seats os
+SET
+    cinemas_schedule_id = o.cinemas_schedule_id
+FROM
+    public.orders o
+WHERE
+    os.order_id = o.id;
+
+-- release double bookings made before the constraint existed, the oldest booking wins
+UPDATE public.orders_seats
+SET
+    status = 'available',
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id IN (
+        SELECT id
+        FROM (
+            SELECT
+                id,
+                ROW_NUMBER() OVER (PARTITION BY cinemas_schedule_id, seat_id ORDER BY id) AS rn
+            FROM public.orders_seats
+            WHERE status = 'booked'
+        ) duplicated
+        WHERE rn > 1
+    );
+
+CREATE UNIQUE INDEX orders_seats_schedule_seat_booked_key ON public.orders_seats (cinemas_schedule_id, seat_id) WHERE status = 'booked';
+
+-- public.orders_seats foreign keys
+ALTER TABLE public.orders_seats ADD CONSTRAINT orders_seats_cinemas_schedule_id_fkey FOREIGN KEY (cinemas_schedule_id) REFERENCES public.cinemas_schedules (id) ON DELETE CASCADE;
@@ -0,0 +1,4 @@
+ALTER TABLE public.orders DROP COLUMN price_breakdown;
+ALTER TABLE public.payment_methods DROP COLUMN fee;
+DROP TABLE public.price_rules;
+DROP TABLE public.seat_type_prices;
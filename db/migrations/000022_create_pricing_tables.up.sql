@@ -0,0 +1,42 @@
+-- public.seat_type_prices definition
+-- Drop table
+-- DROP TABLE public.seat_type_prices;
+CREATE TABLE
+    public.seat_type_prices (
+        seat_type varchar(20) NOT NULL,
+        surcharge numeric(10, 2) DEFAULT 0 NOT NULL,
+        CONSTRAINT seat_type_prices_pkey PRIMARY KEY (seat_type)
+    );
+
+INSERT INTO
+    public.seat_type_prices (seat_type, surcharge)
+VALUES
+    ('regular', 0),
+    ('vip', 15000),
+    ('loveseat', 25000);
+
+-- public.price_rules definition
+-- adjustment is added to every ticket of a screening matching the day of week (0 = sunday) and start time window
+-- Drop table
+-- DROP TABLE public.price_rules;
+CREATE TABLE
+    public.price_rules (
+        id serial4 NOT NULL,
+        "name" varchar(100) NOT NULL,
+        days_of_week int4[] NULL,
+        start_time time NULL,
+        end_time time NULL,
+        adjustment numeric(10, 2) NOT NULL,
+        is_active bool DEFAULT true NOT NULL,
+        CONSTRAINT price_rules_pkey PRIMARY KEY (id)
+    );
+
+INSERT INTO
+    public.price_rules ("name", days_of_week, start_time, end_time, adjustment)
+VALUES
+    ('Weekend', '{0,6}', NULL, NULL, 10000),
+    ('Matinee', '{1,2,3,4,5}', NULL, '12:00', -5000);
+
+ALTER TABLE public.payment_methods ADD COLUMN fee numeric(10, 2) DEFAULT 0 NOT NULL;
+
+ALTER TABLE public.orders ADD COLUMN price_breakdown jsonb NULL;
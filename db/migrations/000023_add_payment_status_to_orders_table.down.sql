@@ -0,0 +1,7 @@
+DROP TABLE public.payment_events;
+DROP INDEX public.orders_payment_reference_key;
+ALTER TABLE public.orders DROP CONSTRAINT orders_payment_status_check;
+ALTER TABLE public.orders
+    DROP COLUMN payment_status,
+    DROP COLUMN payment_reference,
+    DROP COLUMN paid_at;
@@ -0,0 +1,32 @@
+-- payment lifecycle: pending -> paid -> refunded, pending -> failed / expired
+ALTER TABLE public.orders
+    ADD COLUMN payment_status varchar(20) DEFAULT 'pending' NOT NULL,
+    ADD COLUMN payment_reference varchar(100) NULL,
+    ADD COLUMN paid_at timestamp NULL;
+
+UPDATE public.orders
+SET
+    payment_status = CASE WHEN ispaid THEN 'paid' ELSE 'pending' END,
+    paid_at = CASE WHEN ispaid THEN updated_at ELSE NULL END;
+
+ALTER TABLE public.orders ADD CONSTRAINT orders_payment_status_check CHECK (((payment_status)::text = ANY ((ARRAY['pending'::character varying, 'paid'::character varying, 'failed'::character varying, 'expired'::character varying, 'refunded'::character varying])::text[])));
+
+CREATE UNIQUE INDEX orders_payment_reference_key ON public.orders (payment_reference) WHERE payment_reference IS NOT NULL;
+
+-- public.payment_events definition
+-- every provider callback is stored once, a repeated event id is ignored
+-- Drop table
+-- DROP TABLE public.payment_events;
+CREATE TABLE
+    public.payment_events (
+        id serial4 NOT NULL,
+        order_id int4 NULL,
+        provider varchar(50) NOT NULL,
+        event_id varchar(100) NOT NULL,
+        status varchar(20) NOT NULL,
+        payload jsonb NULL,
+        created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+        CONSTRAINT payment_events_pkey PRIMARY KEY (id),
+        CONSTRAINT payment_events_provider_event_id_key UNIQUE (provider, event_id),
+        CONSTRAINT payment_events_order_id_fkey FOREIGN KEY (order_id) REFERENCES public.orders (id) ON DELETE SET NULL
+    );
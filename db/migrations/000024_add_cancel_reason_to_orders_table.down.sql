@@ -0,0 +1,4 @@
+DROP INDEX public.orders_pending_created_at_idx;
+ALTER TABLE public.orders
+    DROP COLUMN cancel_reason,
+    DROP COLUMN cancelled_at;
@@ -0,0 +1,7 @@
+-- why and when an order stopped being valid (payment expired, payment failed, ...)
+ALTER TABLE public.orders
+    ADD COLUMN cancel_reason varchar(100) NULL,
+    ADD COLUMN cancelled_at timestamp NULL;
+
+-- used by the expiry worker to find unpaid orders
+CREATE INDEX orders_pending_created_at_idx ON public.orders (created_at) WHERE payment_status = 'pending';
@@ -0,0 +1,6 @@
+ALTER TABLE public.orders
+    DROP COLUMN refund_amount,
+    DROP COLUMN points_earned;
+UPDATE public.orders SET payment_status = 'expired' WHERE payment_status = 'cancelled';
+ALTER TABLE public.orders DROP CONSTRAINT orders_payment_status_check;
+ALTER TABLE public.orders ADD CONSTRAINT orders_payment_status_check CHECK (((payment_status)::text = ANY ((ARRAY['pending'::character varying, 'paid'::character varying, 'failed'::character varying, 'expired'::character varying, 'refunded'::character varying])::text[])));
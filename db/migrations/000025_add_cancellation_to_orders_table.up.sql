@@ -0,0 +1,8 @@
+-- unpaid orders can be cancelled by the user, paid orders are refunded
+ALTER TABLE public.orders DROP CONSTRAINT orders_payment_status_check;
+ALTER TABLE public.orders ADD CONSTRAINT orders_payment_status_check CHECK (((payment_status)::text = ANY ((ARRAY['pending'::character varying, 'paid'::character varying, 'failed'::character varying, 'expired'::character varying, 'refunded'::character varying, 'cancelled'::character varying])::text[])));
+
+-- points_earned is reversed when the order is refunded
+ALTER TABLE public.orders
+    ADD COLUMN refund_amount numeric(12, 2) NULL,
+    ADD COLUMN points_earned int4 DEFAULT 0 NOT NULL;
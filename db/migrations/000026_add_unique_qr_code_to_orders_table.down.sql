@@ -0,0 +1 @@
+ALTER TABLE public.orders DROP CONSTRAINT orders_qr_code_key;
@@ -0,0 +1,8 @@
+-- old random codes could collide, keep the first order and suffix the others with their id
+UPDATE public.orders o
+SET qr_code = o.qr_code || '-' || o.id
+WHERE EXISTS (
+    SELECT 1 FROM public.orders d WHERE d.qr_code = o.qr_code AND d.id < o.id
+);
+
+ALTER TABLE public.orders ADD CONSTRAINT orders_qr_code_key UNIQUE (qr_code);
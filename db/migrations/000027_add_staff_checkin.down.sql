@@ -0,0 +1,9 @@
+ALTER TABLE public.orders DROP CONSTRAINT orders_checked_in_by_fkey;
+ALTER TABLE public.orders
+    DROP COLUMN checked_in_at,
+    DROP COLUMN checked_in_by;
+ALTER TABLE public.users DROP CONSTRAINT users_cinema_id_fkey;
+ALTER TABLE public.users DROP COLUMN cinema_id;
+UPDATE public.users SET role = 'user' WHERE role = 'staff';
+ALTER TABLE public.users DROP CONSTRAINT users_role_check;
+ALTER TABLE public.users ADD CONSTRAINT users_role_check CHECK (((role)::text = ANY ((ARRAY['user'::character varying, 'admin'::character varying])::text[])));
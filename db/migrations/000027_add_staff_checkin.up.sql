@@ -0,0 +1,12 @@
+-- staff scan tickets at the gate of the cinema they are assigned to
+ALTER TABLE public.users DROP CONSTRAINT users_role_check;
+ALTER TABLE public.users ADD CONSTRAINT users_role_check CHECK (((role)::text = ANY ((ARRAY['user'::character varying, 'admin'::character varying, 'staff'::character varying])::text[])));
+
+ALTER TABLE public.users ADD COLUMN cinema_id int4 NULL;
+ALTER TABLE public.users ADD CONSTRAINT users_cinema_id_fkey FOREIGN KEY (cinema_id) REFERENCES public.cinemas (id) ON DELETE SET NULL;
+
+-- a ticket is used once, checked_in_at is set by the first successful scan
+ALTER TABLE public.orders
+    ADD COLUMN checked_in_at timestamp NULL,
+    ADD COLUMN checked_in_by int4 NULL;
+ALTER TABLE public.orders ADD CONSTRAINT orders_checked_in_by_fkey FOREIGN KEY (checked_in_by) REFERENCES public.users (id) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+DROP TABLE public.refresh_tokens;
+DROP TABLE public.user_sessions;
@@ -0,0 +1,37 @@
+-- public.user_sessions definition
+-- one row per logged-in device, the refresh tokens of a session form one rotation family
+-- Drop table
+-- DROP TABLE public.user_sessions;
+CREATE TABLE
+    public.user_sessions (
+        id serial4 NOT NULL,
+        user_id int4 NOT NULL,
+        user_agent varchar(255) NULL,
+        ip_address varchar(64) NULL,
+        created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+        last_used_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+        expires_at timestamp NOT NULL,
+        revoked_at timestamp NULL,
+        revoked_reason varchar(50) NULL,
+        CONSTRAINT user_sessions_pkey PRIMARY KEY (id),
+        CONSTRAINT user_sessions_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX user_sessions_user_id_idx ON public.user_sessions (user_id);
+
+-- public.refresh_tokens definition
+-- only the sha256 hash of the token is stored, used_at is set when the token is rotated
+-- Drop table
+-- DROP TABLE public.refresh_tokens;
+CREATE TABLE
+    public.refresh_tokens (
+        id serial4 NOT NULL,
+        session_id int4 NOT NULL,
+        token_hash varchar(64) NOT NULL,
+        created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+        expires_at timestamp NOT NULL,
+        used_at timestamp NULL,
+        CONSTRAINT refresh_tokens_pkey PRIMARY KEY (id),
+        CONSTRAINT refresh_tokens_token_hash_key UNIQUE (token_hash),
+        CONSTRAINT refresh_tokens_session_id_fkey FOREIGN KEY (session_id) REFERENCES public.user_sessions (id) ON DELETE CASCADE
+    );
@@ -0,0 +1,2 @@
+DROP TABLE public.revoked_tokens;
+ALTER TABLE public.users DROP COLUMN tokens_valid_after;
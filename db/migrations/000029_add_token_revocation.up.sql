@@ -0,0 +1,18 @@
+-- access tokens issued before this time are rejected (password change, log out everywhere)
+ALTER TABLE public.users ADD COLUMN tokens_valid_after timestamptz NULL;
+
+-- public.revoked_tokens definition
+-- revoked access tokens by jti, kept until the token would have expired
+-- Drop table
+-- DROP TABLE public.revoked_tokens;
+CREATE TABLE
+    public.revoked_tokens (
+        jti varchar(64) NOT NULL,
+        user_id int4 NULL,
+        expires_at timestamptz NOT NULL,
+        created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+        CONSTRAINT revoked_tokens_pkey PRIMARY KEY (jti),
+        CONSTRAINT revoked_tokens_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX revoked_tokens_expires_at_idx ON public.revoked_tokens (expires_at);
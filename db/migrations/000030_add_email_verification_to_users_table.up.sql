@@ -0,0 +1,7 @@
+-- accounts must verify their email before ordering
+ALTER TABLE public.users
+    ADD COLUMN email_verified_at timestamp NULL,
+    ADD COLUMN verification_sent_at timestamp NULL;
+
+-- existing accounts are trusted
+UPDATE public.users SET email_verified_at = COALESCE(created_at, NOW());
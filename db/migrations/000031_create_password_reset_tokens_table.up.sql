@@ -0,0 +1,18 @@
+-- public.password_reset_tokens definition
+-- only the sha256 hash of the token is stored, used_at is set when the password is reset
+-- Drop table
+-- DROP TABLE public.password_reset_tokens;
+CREATE TABLE
+    public.password_reset_tokens (
+        id serial4 NOT NULL,
+        user_id int4 NOT NULL,
+        token_hash varchar(64) NOT NULL,
+        created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+        expires_at timestamp NOT NULL,
+        used_at timestamp NULL,
+        CONSTRAINT password_reset_tokens_pkey PRIMARY KEY (id),
+        CONSTRAINT password_reset_tokens_token_hash_key UNIQUE (token_hash),
+        CONSTRAINT password_reset_tokens_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX password_reset_tokens_user_id_idx ON public.password_reset_tokens (user_id);
@@ -0,0 +1,3 @@
+ALTER TABLE public.users
+    DROP COLUMN suspended_at,
+    DROP COLUMN suspended_reason;
@@ -0,0 +1,4 @@
+-- suspended accounts can not login and their tokens are rejected
+ALTER TABLE public.users
+    ADD COLUMN suspended_at timestamptz NULL,
+    ADD COLUMN suspended_reason varchar(255) NULL;
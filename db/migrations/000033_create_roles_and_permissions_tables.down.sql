@@ -0,0 +1,7 @@
+ALTER TABLE public.users DROP CONSTRAINT users_role_fkey;
+UPDATE public.users SET role = 'user', cinema_id = NULL WHERE role NOT IN ('user', 'admin', 'staff');
+ALTER TABLE public.users ADD CONSTRAINT users_role_check CHECK (((role)::text = ANY ((ARRAY['user'::character varying, 'admin'::character varying, 'staff'::character varying])::text[])));
+
+DROP TABLE public.role_permissions;
+DROP TABLE public.permissions;
+DROP TABLE public.roles;
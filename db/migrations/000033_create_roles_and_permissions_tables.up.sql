@@ -0,0 +1,63 @@
+-- public.roles definition
+-- users of a cinema scoped role only act on the cinema set in users.cinema_id
+-- Drop table
+-- DROP TABLE public.roles;
+CREATE TABLE
+    public.roles (
+        id serial4 NOT NULL,
+        "name" varchar(20) NOT NULL,
+        description varchar(255) NULL,
+        cinema_scoped bool DEFAULT false NOT NULL,
+        CONSTRAINT roles_pkey PRIMARY KEY (id),
+        CONSTRAINT roles_name_key UNIQUE (name)
+    );
+
+-- public.permissions definition
+-- Drop table
+-- DROP TABLE public.permissions;
+CREATE TABLE
+    public.permissions (
+        id serial4 NOT NULL,
+        "name" varchar(50) NOT NULL,
+        description varchar(255) NULL,
+        CONSTRAINT permissions_pkey PRIMARY KEY (id),
+        CONSTRAINT permissions_name_key UNIQUE (name)
+    );
+
+-- public.role_permissions definition
+-- Drop table
+-- DROP TABLE public.role_permissions;
+CREATE TABLE
+    public.role_permissions (
+        role_id int4 NOT NULL,
+        permission_id int4 NOT NULL,
+        CONSTRAINT role_permissions_pkey PRIMARY KEY (role_id, permission_id),
+        CONSTRAINT role_permissions_role_id_fkey FOREIGN KEY (role_id) REFERENCES public.roles (id) ON DELETE CASCADE,
+        CONSTRAINT role_permissions_permission_id_fkey FOREIGN KEY (permission_id) REFERENCES public.permissions (id) ON DELETE CASCADE
+    );
+
+INSERT INTO public.roles ("name", description, cinema_scoped) VALUES
+    ('user', 'Customer booking tickets', false),
+    ('staff', 'Gate staff of a cinema', true),
+    ('cinema_manager', 'Manager of a cinema', true),
+    ('admin', 'Administrator of every cinema', false);
+
+INSERT INTO public.permissions ("name", description) VALUES
+    ('movies:write', 'Add, edit and delete movies'),
+    ('schedules:write', 'Add cinema schedules'),
+    ('auditoriums:write', 'Add and view auditoriums'),
+    ('orders:refund', 'Cancel and refund any order'),
+    ('checkin:scan', 'Check in tickets at the gate'),
+    ('users:manage', 'Manage user accounts and roles');
+
+INSERT INTO public.role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM public.roles r
+JOIN public.permissions p ON
+    r.name = 'admin'
+    OR (r.name = 'staff' AND p.name = 'checkin:scan')
+    OR (r.name = 'cinema_manager' AND p.name IN ('schedules:write', 'auditoriums:write', 'orders:refund', 'checkin:scan'));
+
+-- the role of a user must exist in roles
+ALTER TABLE public.users DROP CONSTRAINT users_role_check;
+ALTER TABLE public.users ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES public.roles (name) ON UPDATE CASCADE;
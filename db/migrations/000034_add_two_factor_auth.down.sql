@@ -0,0 +1,6 @@
+DROP TABLE public.user_recovery_codes;
+ALTER TABLE public.user_sessions DROP COLUMN mfa;
+ALTER TABLE public.users
+    DROP COLUMN totp_secret,
+    DROP COLUMN totp_enabled_at,
+    DROP COLUMN totp_last_step;
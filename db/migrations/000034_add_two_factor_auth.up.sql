@@ -0,0 +1,25 @@
+-- totp_secret is encrypted, 2FA is on once totp_enabled_at is set. totp_last_step rejects a code used twice
+ALTER TABLE public.users
+    ADD COLUMN totp_secret text NULL,
+    ADD COLUMN totp_enabled_at timestamptz NULL,
+    ADD COLUMN totp_last_step int8 NULL;
+
+-- sessions started with a second factor
+ALTER TABLE public.user_sessions ADD COLUMN mfa bool DEFAULT false NOT NULL;
+
+-- public.user_recovery_codes definition
+-- only the sha256 hash of the code is stored, a code is used once
+-- Drop table
+-- DROP TABLE public.user_recovery_codes;
+CREATE TABLE
+    public.user_recovery_codes (
+        id serial4 NOT NULL,
+        user_id int4 NOT NULL,
+        code_hash varchar(64) NOT NULL,
+        created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+        used_at timestamp NULL,
+        CONSTRAINT user_recovery_codes_pkey PRIMARY KEY (id),
+        CONSTRAINT user_recovery_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX user_recovery_codes_user_id_idx ON public.user_recovery_codes (user_id);
@@ -0,0 +1,2 @@
+DROP TABLE public.oidc_login_states;
+DROP TABLE public.user_identities;
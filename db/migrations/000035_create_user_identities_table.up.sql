@@ -0,0 +1,33 @@
+-- public.user_identities definition
+-- accounts of OpenID Connect providers linked to a user, subject is the "sub" claim of the provider
+-- Drop table
+-- DROP TABLE public.user_identities;
+CREATE TABLE
+    public.user_identities (
+        id serial4 NOT NULL,
+        user_id int4 NOT NULL,
+        provider varchar(50) NOT NULL,
+        subject varchar(255) NOT NULL,
+        email varchar(100) NULL,
+        created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+        last_login_at timestamp NULL,
+        CONSTRAINT user_identities_pkey PRIMARY KEY (id),
+        CONSTRAINT user_identities_provider_subject_key UNIQUE (provider, subject),
+        CONSTRAINT user_identities_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX user_identities_user_id_idx ON public.user_identities (user_id);
+
+-- public.oidc_login_states definition
+-- pending provider logins, only the sha256 hash of the state is stored and a state is used once
+-- Drop table
+-- DROP TABLE public.oidc_login_states;
+CREATE TABLE
+    public.oidc_login_states (
+        state_hash varchar(64) NOT NULL,
+        provider varchar(50) NOT NULL,
+        code_verifier varchar(128) NOT NULL,
+        nonce varchar(64) NOT NULL,
+        expires_at timestamptz NOT NULL,
+        CONSTRAINT oidc_login_states_pkey PRIMARY KEY (state_hash)
+    );
@@ -0,0 +1,2 @@
+-- deleted accounts are anonymized and kept, their orders are financial records
+ALTER TABLE public.users ADD COLUMN deleted_at timestamp NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE public.profiles ALTER COLUMN points DROP NOT NULL;
+ALTER TABLE public.orders DROP COLUMN points_redeemed;
+DROP TABLE public.loyalty_transactions;
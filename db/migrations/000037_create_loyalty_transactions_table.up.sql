@@ -0,0 +1,33 @@
+-- public.loyalty_transactions definition
+-- ledger of the loyalty points, profiles.points is the balance after the last transaction
+-- Drop table
+-- DROP TABLE public.loyalty_transactions;
+CREATE TABLE
+    public.loyalty_transactions (
+        id serial4 NOT NULL,
+        user_id int4 NOT NULL,
+        order_id int4 NULL,
+        "type" varchar(20) NOT NULL,
+        points int4 NOT NULL,
+        balance_after int4 NOT NULL,
+        description varchar(255) NULL,
+        created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+        CONSTRAINT loyalty_transactions_pkey PRIMARY KEY (id),
+        CONSTRAINT loyalty_transactions_type_check CHECK (((type)::text = ANY ((ARRAY['opening'::character varying, 'earn'::character varying, 'redeem'::character varying, 'reverse'::character varying, 'restore'::character varying, 'forfeit'::character varying])::text[]))),
+        CONSTRAINT loyalty_transactions_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users (id) ON DELETE CASCADE,
+        CONSTRAINT loyalty_transactions_order_id_fkey FOREIGN KEY (order_id) REFERENCES public.orders (id) ON DELETE SET NULL
+    );
+
+CREATE INDEX loyalty_transactions_user_id_idx ON public.loyalty_transactions (user_id, id);
+
+-- points spent as a discount on the order, given back when the order is not paid or refunded
+ALTER TABLE public.orders ADD COLUMN points_redeemed int4 DEFAULT 0 NOT NULL;
+
+-- the balance collected until now is the opening transaction
+UPDATE public.profiles SET points = 0 WHERE points IS NULL OR points < 0;
+ALTER TABLE public.profiles ALTER COLUMN points SET NOT NULL;
+
+INSERT INTO public.loyalty_transactions (user_id, "type", points, balance_after, description)
+SELECT user_id, 'opening', points, points, 'Opening balance'
+FROM public.profiles
+WHERE points > 0 AND user_id IS NOT NULL;
@@ -0,0 +1,4 @@
+DELETE FROM public.permissions WHERE "name" = 'promos:manage';
+ALTER TABLE public.orders DROP COLUMN promo_code_id;
+DROP TABLE public.promo_redemptions;
+DROP TABLE public.promo_codes;
@@ -0,0 +1,64 @@
+-- public.promo_codes definition
+-- the discount applies to the ticket subtotal, empty id arrays are not restricted
+-- used_count is the number of redemptions not released, it never exceeds usage_limit
+-- Drop table
+-- DROP TABLE public.promo_codes;
+CREATE TABLE
+    public.promo_codes (
+        id serial4 NOT NULL,
+        code varchar(30) NOT NULL,
+        description varchar(255) NULL,
+        discount_type varchar(20) NOT NULL,
+        discount_value numeric(10, 2) NOT NULL,
+        max_discount numeric(10, 2) NULL,
+        min_spend numeric(10, 2) DEFAULT 0 NOT NULL,
+        usage_limit int4 NULL,
+        per_user_limit int4 NULL,
+        used_count int4 DEFAULT 0 NOT NULL,
+        valid_from timestamp NULL,
+        valid_until timestamp NULL,
+        movie_ids int4[] DEFAULT '{}' NOT NULL,
+        cinema_ids int4[] DEFAULT '{}' NOT NULL,
+        payment_method_ids int4[] DEFAULT '{}' NOT NULL,
+        is_active bool DEFAULT true NOT NULL,
+        created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+        updated_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+        CONSTRAINT promo_codes_pkey PRIMARY KEY (id),
+        CONSTRAINT promo_codes_code_key UNIQUE (code),
+        CONSTRAINT promo_codes_discount_type_check CHECK (((discount_type)::text = ANY ((ARRAY['percentage'::character varying, 'fixed'::character varying])::text[]))),
+        CONSTRAINT promo_codes_used_count_check CHECK (used_count >= 0 AND (usage_limit IS NULL OR used_count <= usage_limit))
+    );
+
+-- public.promo_redemptions definition
+-- released when the order is not paid or refunded, the code can be used again
+-- Drop table
+-- DROP TABLE public.promo_redemptions;
+CREATE TABLE
+    public.promo_redemptions (
+        id serial4 NOT NULL,
+        promo_code_id int4 NOT NULL,
+        user_id int4 NOT NULL,
+        order_id int4 NOT NULL,
+        discount numeric(10, 2) NOT NULL,
+        created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+        released_at timestamp NULL,
+        CONSTRAINT promo_redemptions_pkey PRIMARY KEY (id),
+        CONSTRAINT promo_redemptions_order_id_key UNIQUE (order_id),
+        CONSTRAINT promo_redemptions_promo_code_id_fkey FOREIGN KEY (promo_code_id) REFERENCES public.promo_codes (id) ON DELETE CASCADE,
+        CONSTRAINT promo_redemptions_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users (id) ON DELETE CASCADE,
+        CONSTRAINT promo_redemptions_order_id_fkey FOREIGN KEY (order_id) REFERENCES public.orders (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX promo_redemptions_promo_code_user_idx ON public.promo_redemptions (promo_code_id, user_id) WHERE released_at IS NULL;
+
+ALTER TABLE public.orders ADD COLUMN promo_code_id int4 NULL;
+ALTER TABLE public.orders ADD CONSTRAINT orders_promo_code_id_fkey FOREIGN KEY (promo_code_id) REFERENCES public.promo_codes (id) ON DELETE SET NULL;
+
+INSERT INTO public.permissions ("name", description) VALUES
+    ('promos:manage', 'Create and edit promo codes');
+
+INSERT INTO public.role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM public.roles r
+JOIN public.permissions p ON p.name = 'promos:manage'
+WHERE r.name = 'admin';
@@ -0,0 +1,7 @@
+DELETE FROM public.permissions WHERE "name" IN ('cinemas:write', 'payments:manage');
+ALTER TABLE public.payment_methods DROP COLUMN is_active;
+ALTER TABLE public.locations DROP CONSTRAINT locations_coordinates_check;
+ALTER TABLE public.locations
+    DROP COLUMN address,
+    DROP COLUMN latitude,
+    DROP COLUMN longitude;
@@ -0,0 +1,22 @@
+-- locations.name is the city of the location
+ALTER TABLE public.locations
+    ADD COLUMN address varchar(255) NULL,
+    ADD COLUMN latitude numeric(9, 6) NULL,
+    ADD COLUMN longitude numeric(9, 6) NULL,
+    ADD CONSTRAINT locations_coordinates_check CHECK (
+        (latitude IS NULL AND longitude IS NULL)
+        OR (latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180)
+    );
+
+-- disabled payment methods can not be used by new orders
+ALTER TABLE public.payment_methods ADD COLUMN is_active bool DEFAULT true NOT NULL;
+
+INSERT INTO public.permissions ("name", description) VALUES
+    ('cinemas:write', 'Add, edit and delete cinemas and locations'),
+    ('payments:manage', 'Add, edit and disable payment methods');
+
+INSERT INTO public.role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM public.roles r
+JOIN public.permissions p ON p.name IN ('cinemas:write', 'payments:manage')
+WHERE r.name = 'admin';
@@ -0,0 +1,7 @@
+DROP INDEX public.genres_name_key;
+ALTER TABLE public.directors
+    DROP COLUMN photo_path,
+    DROP COLUMN bio;
+ALTER TABLE public.casts
+    DROP COLUMN photo_path,
+    DROP COLUMN bio;
@@ -0,0 +1,11 @@
+-- photo and biography of the people credited on movies
+ALTER TABLE public.casts
+    ADD COLUMN photo_path text NULL,
+    ADD COLUMN bio text NULL;
+
+ALTER TABLE public.directors
+    ADD COLUMN photo_path text NULL,
+    ADD COLUMN bio text NULL;
+
+-- a genre name is used once, duplicates are merged
+CREATE UNIQUE INDEX genres_name_key ON public.genres (LOWER("name"));
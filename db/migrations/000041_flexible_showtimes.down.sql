@@ -0,0 +1,7 @@
+DROP INDEX public.cinemas_schedules_auditorium_id_idx;
+
+-- fails while a screening starts outside of the fixed slots
+CREATE TYPE public.show_time AS ENUM ('10:00', '13:00', '16:00', '19:00');
+
+ALTER TABLE public.schedules
+    ALTER COLUMN "time" TYPE public.show_time USING to_char("time", 'HH24:MI')::public.show_time;
@@ -0,0 +1,8 @@
+-- screenings start at any time, not only on the fixed show_time slots
+ALTER TABLE public.schedules
+    ALTER COLUMN "time" TYPE time USING "time"::text::time;
+
+DROP TYPE public.show_time;
+
+-- overlapping screenings are looked up by auditorium
+CREATE INDEX cinemas_schedules_auditorium_id_idx ON public.cinemas_schedules (auditorium_id);
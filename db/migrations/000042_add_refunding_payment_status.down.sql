@@ -0,0 +1,3 @@
+UPDATE public.orders SET payment_status = 'paid' WHERE payment_status = 'refunding';
+ALTER TABLE public.orders DROP CONSTRAINT orders_payment_status_check;
+ALTER TABLE public.orders ADD CONSTRAINT orders_payment_status_check CHECK (((payment_status)::text = ANY ((ARRAY['pending'::character varying, 'paid'::character varying, 'failed'::character varying, 'expired'::character varying, 'refunded'::character varying, 'cancelled'::character varying])::text[])));
@@ -0,0 +1,3 @@
+-- a paid order is moved to refunding while the provider refund runs, so it is refunded only once
+ALTER TABLE public.orders DROP CONSTRAINT orders_payment_status_check;
+ALTER TABLE public.orders ADD CONSTRAINT orders_payment_status_check CHECK (((payment_status)::text = ANY ((ARRAY['pending'::character varying, 'paid'::character varying, 'refunding'::character varying, 'failed'::character varying, 'expired'::character varying, 'refunded'::character varying, 'cancelled'::character varying])::text[])));
@@ -0,0 +1 @@
+ALTER TABLE public.orders DROP COLUMN refund_sent_at;
@@ -0,0 +1,2 @@
+-- set once the provider accepted the refund, a refunding order with it is finished without refunding again
+ALTER TABLE public.orders ADD COLUMN refund_sent_at timestamp NULL;
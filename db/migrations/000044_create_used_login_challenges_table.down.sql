@@ -0,0 +1 @@
+DROP TABLE public.used_login_challenges;
@@ -0,0 +1,13 @@
+-- challenges of /auth/login/2fa that finished a login, only the sha256 hash of the nonce is stored and a
+-- challenge is accepted once. Rows are kept until the challenge would have expired
+CREATE TABLE
+    public.used_login_challenges (
+        nonce_hash varchar(64) NOT NULL,
+        user_id int4 NOT NULL,
+        expires_at timestamp NOT NULL,
+        created_at timestamp DEFAULT CURRENT_TIMESTAMP NULL,
+        CONSTRAINT used_login_challenges_pkey PRIMARY KEY (nonce_hash),
+        CONSTRAINT used_login_challenges_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users (id) ON DELETE CASCADE
+    );
+
+CREATE INDEX used_login_challenges_expires_at_idx ON public.used_login_challenges (expires_at);
@@ -9,4 +9,12 @@ VALUES
 		'Hiflix Bandung',
 		60000.00,
 		'/cinemas/hiflix.jpg'
-	);
\ No newline at end of file
+	);
+
+INSERT INTO
+	public.auditoriums (id, cinemas_id, "name", seat_rows, seat_columns, aisles)
+VALUES
+	(1, 1, 'Studio 1', 7, 14, '{7}'),
+	(2, 2, 'Studio 1', 7, 14, '{7}'),
+	(3, 3, 'Studio 1', 7, 14, '{7}'),
+	(4, 4, 'Studio 1', 7, 14, '{7}');
@@ -1,8 +1,8 @@
-INSERT INTO public.cinemas_schedules (id,cinemas_id,schedules_id,locations_id) VALUES
-	 (1,1,1,1),
-	 (4,2,4,2),
-	 (5,3,5,1),
-	 (2,1,2,1),
-	 (3,2,3,2),
-	 (6,1,69,1),
-	 (7,1,10,1);
+INSERT INTO public.cinemas_schedules (id,cinemas_id,schedules_id,locations_id,auditorium_id) VALUES
+	 (1,1,1,1,1),
+	 (4,2,4,2,2),
+	 (5,3,5,1,3),
+	 (2,1,2,1,1),
+	 (3,2,3,2,2),
+	 (6,1,69,1,1),
+	 (7,1,10,1,1);
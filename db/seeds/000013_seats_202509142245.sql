@@ -1,785 +1,3932 @@
 INSERT INTO
 	public.seats (
 		id,
+		auditorium_id,
 		seat_number,
+		seat_row,
+		seat_column,
 		seat_type,
 		created_at,
 		updated_at
 	)
 VALUES
 	(
+		1,
 		1,
 		'A1',
-		'Regular',
+		'A',
+		1,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		2,
+		1,
 		'A2',
-		'Regular',
+		'A',
+		2,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		3,
+		1,
 		'A3',
-		'Regular',
+		'A',
+		3,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		4,
+		1,
 		'A4',
-		'Regular',
+		'A',
+		4,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		5,
+		1,
 		'A5',
-		'Regular',
+		'A',
+		5,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		6,
+		1,
 		'A6',
-		'Regular',
+		'A',
+		6,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		7,
+		1,
 		'A7',
-		'Regular',
+		'A',
+		7,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		8,
+		1,
 		'A8',
-		'Regular',
+		'A',
+		8,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		9,
+		1,
 		'A9',
-		'Regular',
+		'A',
+		9,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		10,
+		1,
 		'A10',
-		'Regular',
+		'A',
+		10,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
-	);
-
-INSERT INTO
-	public.seats (
-		id,
-		seat_number,
-		seat_type,
-		created_at,
-		updated_at
-	)
-VALUES
+	),
 	(
 		11,
+		1,
 		'A11',
-		'Regular',
+		'A',
+		11,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		12,
+		1,
 		'A12',
-		'Regular',
+		'A',
+		12,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		13,
+		1,
 		'A13',
-		'Regular',
+		'A',
+		13,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		14,
+		1,
 		'A14',
-		'Regular',
+		'A',
+		14,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		15,
+		1,
 		'B1',
-		'Regular',
+		'B',
+		1,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		16,
+		1,
 		'B2',
-		'Regular',
+		'B',
+		2,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		17,
+		1,
 		'B3',
-		'Regular',
+		'B',
+		3,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		18,
+		1,
 		'B4',
-		'Regular',
+		'B',
+		4,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		19,
+		1,
 		'B5',
-		'Regular',
+		'B',
+		5,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		20,
+		1,
 		'B6',
-		'Regular',
+		'B',
+		6,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
-	);
-
-INSERT INTO
-	public.seats (
-		id,
-		seat_number,
-		seat_type,
-		created_at,
-		updated_at
-	)
-VALUES
+	),
 	(
 		21,
+		1,
 		'B7',
-		'Regular',
+		'B',
+		7,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		22,
+		1,
 		'B8',
-		'Regular',
+		'B',
+		8,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		23,
+		1,
 		'B9',
-		'Regular',
+		'B',
+		9,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		24,
+		1,
 		'B10',
-		'Regular',
+		'B',
+		10,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		25,
+		1,
 		'B11',
-		'Regular',
+		'B',
+		11,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		26,
+		1,
 		'B12',
-		'Regular',
+		'B',
+		12,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		27,
+		1,
 		'B13',
-		'Regular',
+		'B',
+		13,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		28,
+		1,
 		'B14',
-		'Regular',
+		'B',
+		14,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		29,
+		1,
 		'C1',
-		'Regular',
+		'C',
+		1,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		30,
+		1,
 		'C2',
-		'Regular',
+		'C',
+		2,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
-	);
-
-INSERT INTO
-	public.seats (
-		id,
-		seat_number,
-		seat_type,
-		created_at,
-		updated_at
-	)
-VALUES
+	),
 	(
 		31,
+		1,
 		'C3',
-		'Regular',
+		'C',
+		3,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		32,
+		1,
 		'C4',
-		'Regular',
+		'C',
+		4,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		33,
+		1,
 		'C5',
-		'Regular',
+		'C',
+		5,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		34,
+		1,
 		'C6',
-		'Regular',
+		'C',
+		6,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		35,
+		1,
 		'C7',
-		'Regular',
+		'C',
+		7,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		36,
+		1,
 		'C8',
-		'Regular',
+		'C',
+		8,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		37,
+		1,
 		'C9',
-		'Regular',
+		'C',
+		9,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		38,
+		1,
 		'C10',
-		'Regular',
+		'C',
+		10,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		39,
+		1,
 		'C11',
-		'Regular',
+		'C',
+		11,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		40,
+		1,
 		'C12',
-		'Regular',
+		'C',
+		12,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
-	);
-
-INSERT INTO
-	public.seats (
-		id,
-		seat_number,
-		seat_type,
-		created_at,
-		updated_at
-	)
-VALUES
+	),
 	(
 		41,
+		1,
 		'C13',
-		'Regular',
+		'C',
+		13,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		42,
+		1,
 		'C14',
-		'Regular',
+		'C',
+		14,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		43,
+		1,
 		'D1',
-		'Regular',
+		'D',
+		1,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		44,
+		1,
 		'D2',
-		'Regular',
+		'D',
+		2,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		45,
+		1,
 		'D3',
-		'Regular',
+		'D',
+		3,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		46,
+		1,
 		'D4',
-		'Regular',
+		'D',
+		4,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		47,
+		1,
 		'D5',
-		'Regular',
+		'D',
+		5,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		48,
+		1,
 		'D6',
-		'Regular',
+		'D',
+		6,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		49,
+		1,
 		'D7',
-		'Regular',
+		'D',
+		7,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		50,
+		1,
 		'D8',
-		'Regular',
+		'D',
+		8,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
-	);
-
-INSERT INTO
-	public.seats (
-		id,
-		seat_number,
-		seat_type,
-		created_at,
-		updated_at
-	)
-VALUES
+	),
 	(
 		51,
+		1,
 		'D9',
-		'Regular',
+		'D',
+		9,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		52,
+		1,
 		'D10',
-		'Regular',
+		'D',
+		10,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		53,
+		1,
 		'D11',
-		'Regular',
+		'D',
+		11,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		54,
+		1,
 		'D12',
-		'Regular',
+		'D',
+		12,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		55,
+		1,
 		'D13',
-		'Regular',
+		'D',
+		13,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		56,
+		1,
 		'D14',
-		'Regular',
+		'D',
+		14,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		57,
+		1,
 		'E1',
-		'Regular',
+		'E',
+		1,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		58,
+		1,
 		'E2',
-		'Regular',
+		'E',
+		2,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		59,
+		1,
 		'E3',
-		'Regular',
+		'E',
+		3,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		60,
+		1,
 		'E4',
-		'Regular',
+		'E',
+		4,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
-	);
-
-INSERT INTO
-	public.seats (
-		id,
-		seat_number,
-		seat_type,
-		created_at,
-		updated_at
-	)
-VALUES
+	),
 	(
 		61,
+		1,
 		'E5',
-		'Regular',
+		'E',
+		5,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		62,
+		1,
 		'E6',
-		'Regular',
+		'E',
+		6,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		63,
+		1,
 		'E7',
-		'Regular',
+		'E',
+		7,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		64,
+		1,
 		'E8',
-		'Regular',
+		'E',
+		8,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		65,
+		1,
 		'E9',
-		'Regular',
+		'E',
+		9,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		66,
+		1,
 		'E10',
-		'Regular',
+		'E',
+		10,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		67,
+		1,
 		'E11',
-		'Regular',
+		'E',
+		11,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		68,
+		1,
 		'E12',
-		'Regular',
+		'E',
+		12,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		69,
+		1,
 		'E13',
-		'Regular',
+		'E',
+		13,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		70,
+		1,
 		'E14',
-		'Regular',
+		'E',
+		14,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
-	);
-
-INSERT INTO
-	public.seats (
-		id,
-		seat_number,
-		seat_type,
-		created_at,
-		updated_at
-	)
-VALUES
+	),
 	(
 		71,
+		1,
 		'F1',
-		'Regular',
+		'F',
+		1,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		72,
+		1,
 		'F2',
-		'Regular',
+		'F',
+		2,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		73,
+		1,
 		'F3',
-		'Regular',
+		'F',
+		3,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		74,
+		1,
 		'F4',
-		'Regular',
+		'F',
+		4,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		75,
+		1,
 		'F5',
-		'Regular',
+		'F',
+		5,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		76,
+		1,
 		'F6',
-		'Regular',
+		'F',
+		6,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		77,
+		1,
 		'F7',
-		'Regular',
+		'F',
+		7,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		78,
+		1,
 		'F8',
-		'Regular',
+		'F',
+		8,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		79,
+		1,
 		'F9',
-		'Regular',
+		'F',
+		9,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		80,
+		1,
 		'F10',
-		'Regular',
+		'F',
+		10,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
-	);
-
-INSERT INTO
-	public.seats (
-		id,
-		seat_number,
-		seat_type,
-		created_at,
-		updated_at
-	)
-VALUES
+	),
 	(
 		81,
+		1,
 		'F11',
-		'Regular',
+		'F',
+		11,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		82,
+		1,
 		'F12',
-		'Regular',
+		'F',
+		12,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		83,
+		1,
 		'F13',
-		'Regular',
+		'F',
+		13,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		84,
+		1,
 		'F14',
-		'Regular',
+		'F',
+		14,
+		'regular',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		85,
+		1,
 		'G1',
-		'Love Nest',
+		'G',
+		1,
+		'loveseat',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		86,
+		1,
 		'G2',
-		'Love Nest',
+		'G',
+		2,
+		'loveseat',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		87,
+		1,
 		'G3',
-		'Love Nest',
+		'G',
+		3,
+		'loveseat',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		88,
+		1,
 		'G4',
-		'Love Nest',
+		'G',
+		4,
+		'loveseat',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		89,
+		1,
 		'G5',
-		'Love Nest',
+		'G',
+		5,
+		'loveseat',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		90,
+		1,
 		'G6',
-		'Love Nest',
+		'G',
+		6,
+		'loveseat',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
-	);
-
-INSERT INTO
-	public.seats (
-		id,
-		seat_number,
-		seat_type,
-		created_at,
-		updated_at
-	)
-VALUES
+	),
 	(
 		91,
+		1,
 		'G7',
-		'Love Nest',
+		'G',
+		7,
+		'loveseat',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		92,
+		1,
 		'G8',
-		'Love Nest',
+		'G',
+		8,
+		'loveseat',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		93,
+		1,
 		'G9',
-		'Love Nest',
+		'G',
+		9,
+		'loveseat',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		94,
+		1,
 		'G10',
-		'Love Nest',
+		'G',
+		10,
+		'loveseat',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		95,
+		1,
 		'G11',
-		'Love Nest',
+		'G',
+		11,
+		'loveseat',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		96,
+		1,
 		'G12',
-		'Love Nest',
+		'G',
+		12,
+		'loveseat',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		97,
+		1,
 		'G13',
-		'Love Nest',
+		'G',
+		13,
+		'loveseat',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	),
 	(
 		98,
+		1,
 		'G14',
-		'Love Nest',
+		'G',
+		14,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		99,
+		2,
+		'A1',
+		'A',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		100,
+		2,
+		'A2',
+		'A',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		101,
+		2,
+		'A3',
+		'A',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		102,
+		2,
+		'A4',
+		'A',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		103,
+		2,
+		'A5',
+		'A',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		104,
+		2,
+		'A6',
+		'A',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		105,
+		2,
+		'A7',
+		'A',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		106,
+		2,
+		'A8',
+		'A',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		107,
+		2,
+		'A9',
+		'A',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		108,
+		2,
+		'A10',
+		'A',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		109,
+		2,
+		'A11',
+		'A',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		110,
+		2,
+		'A12',
+		'A',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		111,
+		2,
+		'A13',
+		'A',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		112,
+		2,
+		'A14',
+		'A',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		113,
+		2,
+		'B1',
+		'B',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		114,
+		2,
+		'B2',
+		'B',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		115,
+		2,
+		'B3',
+		'B',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		116,
+		2,
+		'B4',
+		'B',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		117,
+		2,
+		'B5',
+		'B',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		118,
+		2,
+		'B6',
+		'B',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		119,
+		2,
+		'B7',
+		'B',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		120,
+		2,
+		'B8',
+		'B',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		121,
+		2,
+		'B9',
+		'B',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		122,
+		2,
+		'B10',
+		'B',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		123,
+		2,
+		'B11',
+		'B',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		124,
+		2,
+		'B12',
+		'B',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		125,
+		2,
+		'B13',
+		'B',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		126,
+		2,
+		'B14',
+		'B',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		127,
+		2,
+		'C1',
+		'C',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		128,
+		2,
+		'C2',
+		'C',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		129,
+		2,
+		'C3',
+		'C',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		130,
+		2,
+		'C4',
+		'C',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		131,
+		2,
+		'C5',
+		'C',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		132,
+		2,
+		'C6',
+		'C',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		133,
+		2,
+		'C7',
+		'C',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		134,
+		2,
+		'C8',
+		'C',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		135,
+		2,
+		'C9',
+		'C',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		136,
+		2,
+		'C10',
+		'C',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		137,
+		2,
+		'C11',
+		'C',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		138,
+		2,
+		'C12',
+		'C',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		139,
+		2,
+		'C13',
+		'C',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		140,
+		2,
+		'C14',
+		'C',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		141,
+		2,
+		'D1',
+		'D',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		142,
+		2,
+		'D2',
+		'D',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		143,
+		2,
+		'D3',
+		'D',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		144,
+		2,
+		'D4',
+		'D',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		145,
+		2,
+		'D5',
+		'D',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		146,
+		2,
+		'D6',
+		'D',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		147,
+		2,
+		'D7',
+		'D',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		148,
+		2,
+		'D8',
+		'D',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		149,
+		2,
+		'D9',
+		'D',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		150,
+		2,
+		'D10',
+		'D',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		151,
+		2,
+		'D11',
+		'D',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		152,
+		2,
+		'D12',
+		'D',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		153,
+		2,
+		'D13',
+		'D',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		154,
+		2,
+		'D14',
+		'D',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		155,
+		2,
+		'E1',
+		'E',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		156,
+		2,
+		'E2',
+		'E',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		157,
+		2,
+		'E3',
+		'E',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		158,
+		2,
+		'E4',
+		'E',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		159,
+		2,
+		'E5',
+		'E',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		160,
+		2,
+		'E6',
+		'E',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		161,
+		2,
+		'E7',
+		'E',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		162,
+		2,
+		'E8',
+		'E',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		163,
+		2,
+		'E9',
+		'E',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		164,
+		2,
+		'E10',
+		'E',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		165,
+		2,
+		'E11',
+		'E',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		166,
+		2,
+		'E12',
+		'E',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		167,
+		2,
+		'E13',
+		'E',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		168,
+		2,
+		'E14',
+		'E',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		169,
+		2,
+		'F1',
+		'F',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		170,
+		2,
+		'F2',
+		'F',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		171,
+		2,
+		'F3',
+		'F',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		172,
+		2,
+		'F4',
+		'F',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		173,
+		2,
+		'F5',
+		'F',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		174,
+		2,
+		'F6',
+		'F',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		175,
+		2,
+		'F7',
+		'F',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		176,
+		2,
+		'F8',
+		'F',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		177,
+		2,
+		'F9',
+		'F',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		178,
+		2,
+		'F10',
+		'F',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		179,
+		2,
+		'F11',
+		'F',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		180,
+		2,
+		'F12',
+		'F',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		181,
+		2,
+		'F13',
+		'F',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		182,
+		2,
+		'F14',
+		'F',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		183,
+		2,
+		'G1',
+		'G',
+		1,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		184,
+		2,
+		'G2',
+		'G',
+		2,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		185,
+		2,
+		'G3',
+		'G',
+		3,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		186,
+		2,
+		'G4',
+		'G',
+		4,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		187,
+		2,
+		'G5',
+		'G',
+		5,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		188,
+		2,
+		'G6',
+		'G',
+		6,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		189,
+		2,
+		'G7',
+		'G',
+		7,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		190,
+		2,
+		'G8',
+		'G',
+		8,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		191,
+		2,
+		'G9',
+		'G',
+		9,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		192,
+		2,
+		'G10',
+		'G',
+		10,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		193,
+		2,
+		'G11',
+		'G',
+		11,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		194,
+		2,
+		'G12',
+		'G',
+		12,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		195,
+		2,
+		'G13',
+		'G',
+		13,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		196,
+		2,
+		'G14',
+		'G',
+		14,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		197,
+		3,
+		'A1',
+		'A',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		198,
+		3,
+		'A2',
+		'A',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		199,
+		3,
+		'A3',
+		'A',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		200,
+		3,
+		'A4',
+		'A',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		201,
+		3,
+		'A5',
+		'A',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		202,
+		3,
+		'A6',
+		'A',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		203,
+		3,
+		'A7',
+		'A',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		204,
+		3,
+		'A8',
+		'A',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		205,
+		3,
+		'A9',
+		'A',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		206,
+		3,
+		'A10',
+		'A',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		207,
+		3,
+		'A11',
+		'A',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		208,
+		3,
+		'A12',
+		'A',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		209,
+		3,
+		'A13',
+		'A',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		210,
+		3,
+		'A14',
+		'A',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		211,
+		3,
+		'B1',
+		'B',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		212,
+		3,
+		'B2',
+		'B',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		213,
+		3,
+		'B3',
+		'B',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		214,
+		3,
+		'B4',
+		'B',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		215,
+		3,
+		'B5',
+		'B',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		216,
+		3,
+		'B6',
+		'B',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		217,
+		3,
+		'B7',
+		'B',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		218,
+		3,
+		'B8',
+		'B',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		219,
+		3,
+		'B9',
+		'B',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		220,
+		3,
+		'B10',
+		'B',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		221,
+		3,
+		'B11',
+		'B',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		222,
+		3,
+		'B12',
+		'B',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		223,
+		3,
+		'B13',
+		'B',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		224,
+		3,
+		'B14',
+		'B',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		225,
+		3,
+		'C1',
+		'C',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		226,
+		3,
+		'C2',
+		'C',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		227,
+		3,
+		'C3',
+		'C',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		228,
+		3,
+		'C4',
+		'C',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		229,
+		3,
+		'C5',
+		'C',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		230,
+		3,
+		'C6',
+		'C',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		231,
+		3,
+		'C7',
+		'C',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		232,
+		3,
+		'C8',
+		'C',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		233,
+		3,
+		'C9',
+		'C',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		234,
+		3,
+		'C10',
+		'C',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		235,
+		3,
+		'C11',
+		'C',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		236,
+		3,
+		'C12',
+		'C',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		237,
+		3,
+		'C13',
+		'C',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		238,
+		3,
+		'C14',
+		'C',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		239,
+		3,
+		'D1',
+		'D',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		240,
+		3,
+		'D2',
+		'D',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		241,
+		3,
+		'D3',
+		'D',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		242,
+		3,
+		'D4',
+		'D',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		243,
+		3,
+		'D5',
+		'D',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		244,
+		3,
+		'D6',
+		'D',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		245,
+		3,
+		'D7',
+		'D',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		246,
+		3,
+		'D8',
+		'D',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		247,
+		3,
+		'D9',
+		'D',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		248,
+		3,
+		'D10',
+		'D',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		249,
+		3,
+		'D11',
+		'D',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		250,
+		3,
+		'D12',
+		'D',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		251,
+		3,
+		'D13',
+		'D',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		252,
+		3,
+		'D14',
+		'D',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		253,
+		3,
+		'E1',
+		'E',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		254,
+		3,
+		'E2',
+		'E',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		255,
+		3,
+		'E3',
+		'E',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		256,
+		3,
+		'E4',
+		'E',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		257,
+		3,
+		'E5',
+		'E',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		258,
+		3,
+		'E6',
+		'E',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		259,
+		3,
+		'E7',
+		'E',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		260,
+		3,
+		'E8',
+		'E',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		261,
+		3,
+		'E9',
+		'E',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		262,
+		3,
+		'E10',
+		'E',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		263,
+		3,
+		'E11',
+		'E',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		264,
+		3,
+		'E12',
+		'E',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		265,
+		3,
+		'E13',
+		'E',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		266,
+		3,
+		'E14',
+		'E',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		267,
+		3,
+		'F1',
+		'F',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		268,
+		3,
+		'F2',
+		'F',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		269,
+		3,
+		'F3',
+		'F',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		270,
+		3,
+		'F4',
+		'F',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		271,
+		3,
+		'F5',
+		'F',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		272,
+		3,
+		'F6',
+		'F',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		273,
+		3,
+		'F7',
+		'F',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		274,
+		3,
+		'F8',
+		'F',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		275,
+		3,
+		'F9',
+		'F',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		276,
+		3,
+		'F10',
+		'F',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		277,
+		3,
+		'F11',
+		'F',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		278,
+		3,
+		'F12',
+		'F',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		279,
+		3,
+		'F13',
+		'F',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		280,
+		3,
+		'F14',
+		'F',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		281,
+		3,
+		'G1',
+		'G',
+		1,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		282,
+		3,
+		'G2',
+		'G',
+		2,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		283,
+		3,
+		'G3',
+		'G',
+		3,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		284,
+		3,
+		'G4',
+		'G',
+		4,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		285,
+		3,
+		'G5',
+		'G',
+		5,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		286,
+		3,
+		'G6',
+		'G',
+		6,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		287,
+		3,
+		'G7',
+		'G',
+		7,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		288,
+		3,
+		'G8',
+		'G',
+		8,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		289,
+		3,
+		'G9',
+		'G',
+		9,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		290,
+		3,
+		'G10',
+		'G',
+		10,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		291,
+		3,
+		'G11',
+		'G',
+		11,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		292,
+		3,
+		'G12',
+		'G',
+		12,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		293,
+		3,
+		'G13',
+		'G',
+		13,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		294,
+		3,
+		'G14',
+		'G',
+		14,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		295,
+		4,
+		'A1',
+		'A',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		296,
+		4,
+		'A2',
+		'A',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		297,
+		4,
+		'A3',
+		'A',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		298,
+		4,
+		'A4',
+		'A',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		299,
+		4,
+		'A5',
+		'A',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		300,
+		4,
+		'A6',
+		'A',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		301,
+		4,
+		'A7',
+		'A',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		302,
+		4,
+		'A8',
+		'A',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		303,
+		4,
+		'A9',
+		'A',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		304,
+		4,
+		'A10',
+		'A',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		305,
+		4,
+		'A11',
+		'A',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		306,
+		4,
+		'A12',
+		'A',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		307,
+		4,
+		'A13',
+		'A',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		308,
+		4,
+		'A14',
+		'A',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		309,
+		4,
+		'B1',
+		'B',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		310,
+		4,
+		'B2',
+		'B',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		311,
+		4,
+		'B3',
+		'B',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		312,
+		4,
+		'B4',
+		'B',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		313,
+		4,
+		'B5',
+		'B',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		314,
+		4,
+		'B6',
+		'B',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		315,
+		4,
+		'B7',
+		'B',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		316,
+		4,
+		'B8',
+		'B',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		317,
+		4,
+		'B9',
+		'B',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		318,
+		4,
+		'B10',
+		'B',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		319,
+		4,
+		'B11',
+		'B',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		320,
+		4,
+		'B12',
+		'B',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		321,
+		4,
+		'B13',
+		'B',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		322,
+		4,
+		'B14',
+		'B',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		323,
+		4,
+		'C1',
+		'C',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		324,
+		4,
+		'C2',
+		'C',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		325,
+		4,
+		'C3',
+		'C',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		326,
+		4,
+		'C4',
+		'C',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		327,
+		4,
+		'C5',
+		'C',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		328,
+		4,
+		'C6',
+		'C',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		329,
+		4,
+		'C7',
+		'C',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		330,
+		4,
+		'C8',
+		'C',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		331,
+		4,
+		'C9',
+		'C',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		332,
+		4,
+		'C10',
+		'C',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		333,
+		4,
+		'C11',
+		'C',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		334,
+		4,
+		'C12',
+		'C',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		335,
+		4,
+		'C13',
+		'C',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		336,
+		4,
+		'C14',
+		'C',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		337,
+		4,
+		'D1',
+		'D',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		338,
+		4,
+		'D2',
+		'D',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		339,
+		4,
+		'D3',
+		'D',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		340,
+		4,
+		'D4',
+		'D',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		341,
+		4,
+		'D5',
+		'D',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		342,
+		4,
+		'D6',
+		'D',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		343,
+		4,
+		'D7',
+		'D',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		344,
+		4,
+		'D8',
+		'D',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		345,
+		4,
+		'D9',
+		'D',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		346,
+		4,
+		'D10',
+		'D',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		347,
+		4,
+		'D11',
+		'D',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		348,
+		4,
+		'D12',
+		'D',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		349,
+		4,
+		'D13',
+		'D',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		350,
+		4,
+		'D14',
+		'D',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		351,
+		4,
+		'E1',
+		'E',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		352,
+		4,
+		'E2',
+		'E',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		353,
+		4,
+		'E3',
+		'E',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		354,
+		4,
+		'E4',
+		'E',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		355,
+		4,
+		'E5',
+		'E',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		356,
+		4,
+		'E6',
+		'E',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		357,
+		4,
+		'E7',
+		'E',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		358,
+		4,
+		'E8',
+		'E',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		359,
+		4,
+		'E9',
+		'E',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		360,
+		4,
+		'E10',
+		'E',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		361,
+		4,
+		'E11',
+		'E',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		362,
+		4,
+		'E12',
+		'E',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		363,
+		4,
+		'E13',
+		'E',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		364,
+		4,
+		'E14',
+		'E',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		365,
+		4,
+		'F1',
+		'F',
+		1,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		366,
+		4,
+		'F2',
+		'F',
+		2,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		367,
+		4,
+		'F3',
+		'F',
+		3,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		368,
+		4,
+		'F4',
+		'F',
+		4,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		369,
+		4,
+		'F5',
+		'F',
+		5,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		370,
+		4,
+		'F6',
+		'F',
+		6,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		371,
+		4,
+		'F7',
+		'F',
+		7,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		372,
+		4,
+		'F8',
+		'F',
+		8,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		373,
+		4,
+		'F9',
+		'F',
+		9,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		374,
+		4,
+		'F10',
+		'F',
+		10,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		375,
+		4,
+		'F11',
+		'F',
+		11,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		376,
+		4,
+		'F12',
+		'F',
+		12,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		377,
+		4,
+		'F13',
+		'F',
+		13,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		378,
+		4,
+		'F14',
+		'F',
+		14,
+		'regular',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		379,
+		4,
+		'G1',
+		'G',
+		1,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		380,
+		4,
+		'G2',
+		'G',
+		2,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		381,
+		4,
+		'G3',
+		'G',
+		3,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		382,
+		4,
+		'G4',
+		'G',
+		4,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		383,
+		4,
+		'G5',
+		'G',
+		5,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		384,
+		4,
+		'G6',
+		'G',
+		6,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		385,
+		4,
+		'G7',
+		'G',
+		7,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		386,
+		4,
+		'G8',
+		'G',
+		8,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		387,
+		4,
+		'G9',
+		'G',
+		9,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		388,
+		4,
+		'G10',
+		'G',
+		10,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		389,
+		4,
+		'G11',
+		'G',
+		11,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		390,
+		4,
+		'G12',
+		'G',
+		12,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		391,
+		4,
+		'G13',
+		'G',
+		13,
+		'loveseat',
+		'2025-09-07 03:13:39.840655',
+		'2025-09-07 03:13:39.840655'
+	),
+	(
+		392,
+		4,
+		'G14',
+		'G',
+		14,
+		'loveseat',
 		'2025-09-07 03:13:39.840655',
 		'2025-09-07 03:13:39.840655'
 	);
\ No newline at end of file
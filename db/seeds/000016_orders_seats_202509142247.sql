@@ -1,2 +1,2 @@
-INSERT INTO public.orders_seats (id,status,order_id,seat_id,created_at,updated_at) VALUES
-	 (3,'booked',3,1,'2025-09-13 09:18:00.019442','2025-09-13 09:18:00.019442');
+INSERT INTO public.orders_seats (id,status,order_id,seat_id,cinemas_schedule_id,created_at,updated_at) VALUES
+	 (3,'booked',3,1,1,'2025-09-13 09:18:00.019442','2025-09-13 09:18:00.019442');
\ No newline at end of file
@@ -0,0 +1,43 @@
+package configs
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// read integer env variable, use fallback when empty or invalid
+func GetEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid %s env variable, using default %d", key, fallback)
+		return fallback
+	}
+
+	return number
+}
+
+// read string env variable, use fallback when empty
+func GetEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// read comma separated env variable, empty items are skipped
+func GetEnvList(key string) []string {
+	list := []string{}
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
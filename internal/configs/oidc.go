@@ -0,0 +1,38 @@
+package configs
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+)
+
+// read the OpenID Connect providers listed in OIDC_PROVIDERS, each one is configured with OIDC_<NAME>_* env variables.
+// The provider redirects to ${APP_URL}/auth/oidc/<name>/callback unless OIDC_<NAME>_REDIRECT_URL is set
+func GetOIDCProviders(appURL string) []models.OIDCProviderConfig {
+	providers := []models.OIDCProviderConfig{}
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		config := models.OIDCProviderConfig{
+			Name:         name,
+			DisplayName:  GetEnv(prefix+"DISPLAY_NAME", name),
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  GetEnv(prefix+"REDIRECT_URL", strings.TrimSuffix(appURL, "/")+"/auth/oidc/"+name+"/callback"),
+			Scopes:       strings.Fields(GetEnv(prefix+"SCOPES", "email profile")),
+		}
+		if config.Issuer == "" || config.ClientID == "" {
+			log.Printf("%sISSUER or %sCLIENT_ID env variable not set, login with %s disabled", prefix, prefix, name)
+			continue
+		}
+		providers = append(providers, config)
+	}
+	return providers
+}
@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
@@ -16,14 +18,22 @@ import (
 )
 
 type AdminHandler struct {
-	repo *repositories.AdminRepository
-	rdb  *redis.Client
+	repo          *repositories.AdminRepository
+	revocations   *repositories.TokenRevocationRepository
+	permissions   *repositories.PermissionRepository
+	loginAttempts *repositories.LoginAttemptRepository
+	gateway       *utils.PaymentGateway
+	rdb           *redis.Client
 }
 
-func NewAdminHandler(repo *repositories.AdminRepository, rdb *redis.Client) *AdminHandler {
+func NewAdminHandler(repo *repositories.AdminRepository, revocations *repositories.TokenRevocationRepository, permissions *repositories.PermissionRepository, loginAttempts *repositories.LoginAttemptRepository, gateway *utils.PaymentGateway, rdb *redis.Client) *AdminHandler {
 	return &AdminHandler{
-		repo: repo,
-		rdb:  rdb,
+		repo:          repo,
+		revocations:   revocations,
+		permissions:   permissions,
+		loginAttempts: loginAttempts,
+		gateway:       gateway,
+		rdb:           rdb,
 	}
 }
 
@@ -242,6 +252,13 @@ func (h *AdminHandler) AddMovies(ctx *gin.Context) {
 			})
 			return
 		}
+		if err := utils.NormalizeSchedules(schedules); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
 		movie.Schedules = schedules
 	}
 
@@ -319,6 +336,7 @@ func (h *AdminHandler) GetMovieSchedule(ctx *gin.Context) {
 // UpdateMovie godoc
 // @Summary      Update movie with file upload
 // @Description  Update movie data by ID, allow uploading poster and backdrop.
+// @Description  Schedule times are HH:MM, screenings overlapping another one in the same auditorium are refused.
 // @Tags         Admin
 // @Security     BearerAuth
 // @Accept       multipart/form-data
@@ -339,6 +357,7 @@ func (h *AdminHandler) GetMovieSchedule(ctx *gin.Context) {
 // @Success      200           {object}  models.SuccessResponse
 // @Failure      400           {object}  models.ErrorResponse
 // @Failure      401  		   {object}  models.ErrorResponse
+// @Failure      409           {object}  models.ErrorResponse
 // @Failure      500           {object}  models.ErrorResponse
 // @Router       /admin/movies/edit/{id} [patch]
 func (h *AdminHandler) UpdateMovies(ctx *gin.Context) {
@@ -435,6 +454,13 @@ func (h *AdminHandler) UpdateMovies(ctx *gin.Context) {
 			})
 			return
 		}
+		if err := utils.NormalizeSchedules(schedules); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
 		update.Schedules = &schedules
 	}
 
@@ -448,11 +474,29 @@ func (h *AdminHandler) UpdateMovies(ctx *gin.Context) {
 			})
 			return
 		}
+		for i := range cinemaSchedules {
+			showTime, err := utils.ParseShowTime(cinemaSchedules[i].Time)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"success": false,
+					"error":   err.Error(),
+				})
+				return
+			}
+			cinemaSchedules[i].Time = showTime
+		}
 		update.CinemaSchedules = &cinemaSchedules
 	}
 
 	if err := h.repo.UpdateMovies(ctx, MovieID, update); err != nil {
 		log.Printf("%s", err)
+		if errors.Is(err, repositories.ErrScreeningOverlap) {
+			ctx.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   err.Error(),
@@ -472,7 +516,8 @@ func (h *AdminHandler) UpdateMovies(ctx *gin.Context) {
 
 // GetAllMovies godoc
 // @Summary      Add Cinemas Schedule
-// @Description  Add cinemas schedule
+// @Description  Add cinemas schedule, the auditorium is blocked from the start time for the movie duration plus the cleaning buffer.
+// @Description  Screenings overlapping another one in the same auditorium are refused.
 // @Tags         Admin
 // @Security     BearerAuth
 // @Produce      json
@@ -480,6 +525,7 @@ func (h *AdminHandler) UpdateMovies(ctx *gin.Context) {
 // @Success      200  {object}  models.SuccessResponse
 // @Failure      400  {object}  models.ErrorResponse
 // @Failure      401  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
 // @Failure      500  {object}  models.ErrorResponse
 // @Router       /admin/movies/cinemaschedule/add [post]
 func (h *AdminHandler) AddCinemaSchedule(ctx *gin.Context) {
@@ -494,6 +540,14 @@ func (h *AdminHandler) AddCinemaSchedule(ctx *gin.Context) {
 	}
 
 	for _, cs := range CinemaSchedules {
+		if !utils.CinemaInScope(ctx, int(cs.CinemaID)) {
+			ctx.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "You can only add schedules for your own cinema",
+			})
+			return
+		}
+
 		exist, err := h.repo.IsScheduleExists(ctx, cs.ScheduleID)
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{
@@ -514,6 +568,20 @@ func (h *AdminHandler) AddCinemaSchedule(ctx *gin.Context) {
 
 	err := h.repo.AddCinemaSchedule(ctx, CinemaSchedules)
 	if err != nil {
+		if errors.Is(err, repositories.ErrAuditoriumNotFound) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, repositories.ErrScreeningOverlap) {
+			ctx.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   err.Error(),
@@ -580,3 +648,1134 @@ func (h *AdminHandler) DeleteMovies(ctx *gin.Context) {
 		"message": "movie deleted successfully",
 	})
 }
+
+// GetAuditoriums godoc
+// @Summary      Get cinema auditoriums
+// @Description  Retrieve the auditoriums (studios) of a cinema with their seat layout size
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path      int  true  "Cinema ID"
+// @Success      200  {object}  models.SuccessResponse{data=[]models.Auditorium}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/cinemas/{id}/auditoriums [get]
+func (h *AdminHandler) GetAuditoriums(ctx *gin.Context) {
+	cinemaID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || cinemaID < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid cinema ID",
+		})
+		return
+	}
+	if !utils.CinemaInScope(ctx, cinemaID) {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "You can only manage your own cinema",
+		})
+		return
+	}
+
+	auditoriums, err := h.repo.GetAuditoriums(ctx, cinemaID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if len(auditoriums) == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Auditoriums not found",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    auditoriums,
+	})
+}
+
+// AddAuditorium godoc
+// @Summary      Add auditorium
+// @Description  Add an auditorium (studio) to a cinema and generate its seat layout.
+// @Description  Rows are labelled A-Z, aisles are the column numbers followed by an aisle gap.
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id          path  int                   true  "Cinema ID"
+// @Param        auditorium  body  models.AddAuditorium  true  "Auditorium layout"
+// @Success      200  {object}  models.SuccessResponse{data=models.Auditorium}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/cinemas/{id}/auditoriums/add [post]
+func (h *AdminHandler) AddAuditorium(ctx *gin.Context) {
+	cinemaID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || cinemaID < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid cinema ID",
+		})
+		return
+	}
+	if !utils.CinemaInScope(ctx, cinemaID) {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "You can only manage your own cinema",
+		})
+		return
+	}
+
+	var req models.AddAuditorium
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	for _, aisle := range req.Aisles {
+		if aisle < 1 || aisle >= req.Columns {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "aisles must be column numbers between 1 and columns - 1",
+			})
+			return
+		}
+	}
+
+	for _, row := range append(append([]string{}, req.VIPRows...), req.LoveseatRows...) {
+		row = strings.ToUpper(row)
+		if len(row) != 1 || row[0] < 'A' || int(row[0]-'A') >= req.Rows {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   fmt.Sprintf("invalid seat row %q", row),
+			})
+			return
+		}
+	}
+
+	exist, err := h.repo.IsCinemaExists(ctx, cinemaID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if !exist {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Cinema ID not found",
+		})
+		return
+	}
+
+	auditorium, err := h.repo.AddAuditorium(ctx, cinemaID, &req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if err := utils.InvalidateCache(ctx, h.rdb, []string{"cinemas:"}); err != nil {
+		log.Println("Redis delete cache error:", err)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "auditorium add successfully",
+		"data":    auditorium,
+	})
+}
+
+// GetRoles godoc
+// @Summary      List roles
+// @Description  List the roles with their permissions, cinema scoped roles only act on the cinema of the user
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  models.SuccessResponse{data=[]models.Role}
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/roles [get]
+func (h *AdminHandler) GetRoles(ctx *gin.Context) {
+	roles, err := h.permissions.GetRoles(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "data from database",
+		"data":    roles,
+	})
+}
+
+// GetUsers godoc
+// @Summary      List users
+// @Description  List and search the user accounts (admin access required)
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page    query  int     false  "Page"
+// @Param        search  query  string  false  "Email, name or phone number"
+// @Param        role    query  string  false  "Role name"
+// @Param        status  query  string  false  "active, suspended or deleted"
+// @Success      200  {object}  models.SuccessResponse{data=[]models.AdminUser}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/users [get]
+func (h *AdminHandler) GetUsers(ctx *gin.Context) {
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit := 20
+
+	filter := models.AdminUserFilter{
+		Search: strings.TrimSpace(ctx.Query("search")),
+		Role:   ctx.Query("role"),
+		Status: ctx.Query("status"),
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	}
+	if filter.Status != "" && filter.Status != "active" && filter.Status != "suspended" && filter.Status != "deleted" {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "status must be active, suspended or deleted",
+		})
+		return
+	}
+
+	users, totalCount, err := h.repo.GetUsers(ctx, filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"message":     "data from database",
+		"page":        page,
+		"limit":       limit,
+		"count":       len(users),
+		"total":       totalCount,
+		"total_pages": (totalCount + limit - 1) / limit,
+		"data":        users,
+	})
+}
+
+// helper, reads the user of the path and refuses changes to the admin's own account
+func (h *AdminHandler) getManagedUser(ctx *gin.Context) (*models.AdminUser, bool) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || userID < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid user ID",
+		})
+		return nil, false
+	}
+
+	if userID == claims.UserID {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "You can not change your own account",
+		})
+		return nil, false
+	}
+
+	// deleted accounts are only kept for their orders
+	user, err := h.repo.GetUser(ctx, userID)
+	if err == nil && user.DeletedAt != nil {
+		err = repositories.ErrUserNotFound
+	}
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "User not found",
+			})
+			return nil, false
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return nil, false
+	}
+	return user, true
+}
+
+// UpdateUserRole godoc
+// @Summary      Change user role
+// @Description  Promote or demote a user. Cinema scoped roles need the cinema of the user, the user has to login again with the new role.
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id    path  int                           true  "User ID"
+// @Param        role  body  models.UpdateUserRoleRequest  true  "New role"
+// @Success      200  {object}  models.SuccessResponse{data=models.AdminUser}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/users/{id}/role [patch]
+func (h *AdminHandler) UpdateUserRole(ctx *gin.Context) {
+	user, ok := h.getManagedUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateUserRoleRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	role, err := h.permissions.GetRole(ctx, req.Role)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRoleNotFound) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Role not found",
+			})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if !role.CinemaScoped {
+		req.CinemaID = nil
+	} else {
+		if req.CinemaID == nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   fmt.Sprintf("cinema_id is required for %s", role.Name),
+			})
+			return
+		}
+
+		exist, err := h.repo.IsCinemaExists(ctx, *req.CinemaID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		if !exist {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Cinema ID not found",
+			})
+			return
+		}
+	}
+
+	// tokens with the old role are revoked with the change
+	revoked, err := h.repo.UpdateUserRole(ctx, user.ID, req.Role, req.CinemaID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if revoked {
+		h.revocations.ForgetUserTokenState(ctx, user.ID)
+	}
+
+	user.Role = req.Role
+	user.CinemaID = req.CinemaID
+
+	if err := utils.InvalidateCache(ctx, h.rdb, []string{"users:"}); err != nil {
+		log.Println("Redis delete cache error:", err)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "User role updated",
+		"data":    user,
+	})
+}
+
+// SuspendUser godoc
+// @Summary      Suspend user
+// @Description  Block the account, the user is logged out everywhere and can not login until reactivated
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id      path  int                        true   "User ID"
+// @Param        reason  body  models.SuspendUserRequest  false  "Reason of the suspension"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/users/{id}/suspend [post]
+func (h *AdminHandler) SuspendUser(ctx *gin.Context) {
+	user, ok := h.getManagedUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.SuspendUserRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBind(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	suspended, err := h.revocations.SuspendUser(ctx, user.ID, strings.TrimSpace(req.Reason))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if !suspended {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "User is already suspended",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "User suspended",
+	})
+}
+
+// ReactivateUser godoc
+// @Summary      Reactivate user
+// @Description  Lift the suspension of an account
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path  int  true  "User ID"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/users/{id}/reactivate [post]
+func (h *AdminHandler) ReactivateUser(ctx *gin.Context) {
+	user, ok := h.getManagedUser(ctx)
+	if !ok {
+		return
+	}
+
+	reactivated, err := h.revocations.ReactivateUser(ctx, user.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if !reactivated {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "User is not suspended",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "User reactivated",
+	})
+}
+
+// UnlockUser godoc
+// @Summary      Unlock user login
+// @Description  Lift the lockout caused by failed logins on the account
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path  int  true  "User ID"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/users/{id}/unlock [post]
+func (h *AdminHandler) UnlockUser(ctx *gin.Context) {
+	user, ok := h.getManagedUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.loginAttempts.UnlockAccount(ctx, user.Email); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "User login unlocked",
+	})
+}
+
+// helper, reads the id of the path, what names the resource in the error
+func adminPathID(ctx *gin.Context, what string) (int, bool) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("Invalid %s ID", what),
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// GetCinemas godoc
+// @Summary      List cinemas
+// @Description  List the cinemas with their base ticket price, logo and number of auditoriums
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  models.SuccessResponse{data=[]models.AdminCinema}
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse   "Forbidden (no cinemas:write permission)"
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/cinemas [get]
+func (h *AdminHandler) GetCinemas(ctx *gin.Context) {
+	cinemas, err := h.repo.GetCinemas(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "data from database",
+		"data":    cinemas,
+	})
+}
+
+// AddCinema godoc
+// @Summary      Add cinema
+// @Description  Add a cinema with its base ticket price, the logo is optional
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        name        formData  string  true   "Cinema name"
+// @Param        base_price  formData  number  true   "Base ticket price"
+// @Param        logo        formData  file    false  "Cinema logo"
+// @Success      200  {object}  models.SuccessResponse{data=models.AdminCinema}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/cinemas [post]
+func (h *AdminHandler) AddCinema(ctx *gin.Context) {
+	var req models.AddCinema
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	imagePath, err := utils.UploadFile(ctx, "logo", "public/cinemas", "cinema", "cinemas")
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to upload logo",
+		})
+		return
+	}
+	if imagePath != "" {
+		req.ImagePath = &imagePath
+	}
+
+	cinema, err := h.repo.AddCinema(ctx, &req)
+	if err != nil {
+		if err := utils.RemoveUploadedFile("public/cinemas", imagePath); err != nil {
+			log.Println("Remove cinema logo error:", err)
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if err := utils.InvalidateCache(ctx, h.rdb, []string{"cinemas:"}); err != nil {
+		log.Println("Redis delete cache error:", err)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "cinema add successfully",
+		"data":    cinema,
+	})
+}
+
+// UpdateCinema godoc
+// @Summary      Update cinema
+// @Description  Update the fields that are sent, a new logo replaces the old one
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id          path      int     true   "Cinema ID"
+// @Param        name        formData  string  false  "Cinema name"
+// @Param        base_price  formData  number  false  "Base ticket price"
+// @Param        logo        formData  file    false  "Cinema logo"
+// @Success      200  {object}  models.SuccessResponse{data=models.AdminCinema}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/cinemas/{id} [patch]
+func (h *AdminHandler) UpdateCinema(ctx *gin.Context) {
+	cinemaID, ok := adminPathID(ctx, "cinema")
+	if !ok {
+		return
+	}
+
+	var update models.EditCinema
+	if err := ctx.ShouldBind(&update); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	imagePath, err := utils.UploadFile(ctx, "logo", "public/cinemas", "cinema", "cinemas")
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to upload logo",
+		})
+		return
+	}
+	if imagePath != "" {
+		update.ImagePath = &imagePath
+	}
+
+	cinema, oldImage, err := h.repo.UpdateCinema(ctx, cinemaID, update)
+	if err != nil {
+		if err := utils.RemoveUploadedFile("public/cinemas", imagePath); err != nil {
+			log.Println("Remove cinema logo error:", err)
+		}
+		if errors.Is(err, repositories.ErrCinemaNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Cinema ID not found",
+			})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if oldImage != nil {
+		if err := utils.RemoveUploadedFile("public/cinemas", *oldImage); err != nil {
+			log.Println("Remove cinema logo error:", err)
+		}
+	}
+
+	if err := utils.InvalidateCache(ctx, h.rdb, []string{"cinemas:", "movies:"}); err != nil {
+		log.Println("Redis delete cache error:", err)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "cinema updated successfully",
+		"data":    cinema,
+	})
+}
+
+// DeleteCinema godoc
+// @Summary      Delete cinema
+// @Description  Delete a cinema with its auditoriums and logo. Cinemas with schedules or staff can not be deleted.
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path  int  true  "Cinema ID"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/cinemas/{id} [delete]
+func (h *AdminHandler) DeleteCinema(ctx *gin.Context) {
+	cinemaID, ok := adminPathID(ctx, "cinema")
+	if !ok {
+		return
+	}
+
+	imagePath, err := h.repo.DeleteCinema(ctx, cinemaID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrCinemaNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Cinema ID not found",
+			})
+		case errors.Is(err, repositories.ErrCinemaInUse):
+			ctx.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   "Cinema has schedules or staff and can not be deleted",
+			})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+		return
+	}
+
+	if imagePath != nil {
+		if err := utils.RemoveUploadedFile("public/cinemas", *imagePath); err != nil {
+			log.Println("Remove cinema logo error:", err)
+		}
+	}
+
+	if err := utils.InvalidateCache(ctx, h.rdb, []string{"cinemas:"}); err != nil {
+		log.Println("Redis delete cache error:", err)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "cinema deleted successfully",
+	})
+}
+
+// GetLocations godoc
+// @Summary      List locations
+// @Description  List the cities where cinemas show movies with their address and coordinates
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  models.SuccessResponse{data=[]models.Location}
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse   "Forbidden (no cinemas:write permission)"
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/locations [get]
+func (h *AdminHandler) GetLocations(ctx *gin.Context) {
+	locations, err := h.repo.GetLocations(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "data from database",
+		"data":    locations,
+	})
+}
+
+// AddLocation godoc
+// @Summary      Add location
+// @Description  Add a city, latitude and longitude are set together
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        location  body  models.AddLocation  true  "Location"
+// @Success      200  {object}  models.SuccessResponse{data=models.Location}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/locations [post]
+func (h *AdminHandler) AddLocation(ctx *gin.Context) {
+	var req models.AddLocation
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	location, err := h.repo.AddLocation(ctx, &req)
+	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidCoordinates) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if err := utils.InvalidateCache(ctx, h.rdb, []string{"cinemas:"}); err != nil {
+		log.Println("Redis delete cache error:", err)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "location add successfully",
+		"data":    location,
+	})
+}
+
+// UpdateLocation godoc
+// @Summary      Update location
+// @Description  Update the fields that are sent
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id        path  int                  true  "Location ID"
+// @Param        location  body  models.EditLocation  true  "Fields to update"
+// @Success      200  {object}  models.SuccessResponse{data=models.Location}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/locations/{id} [patch]
+func (h *AdminHandler) UpdateLocation(ctx *gin.Context) {
+	locationID, ok := adminPathID(ctx, "location")
+	if !ok {
+		return
+	}
+
+	var update models.EditLocation
+	if err := ctx.ShouldBind(&update); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	location, err := h.repo.UpdateLocation(ctx, locationID, update)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrLocationNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Location ID not found",
+			})
+		case errors.Is(err, repositories.ErrInvalidCoordinates):
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+		return
+	}
+
+	if err := utils.InvalidateCache(ctx, h.rdb, []string{"cinemas:", "movies:"}); err != nil {
+		log.Println("Redis delete cache error:", err)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "location updated successfully",
+		"data":    location,
+	})
+}
+
+// DeleteLocation godoc
+// @Summary      Delete location
+// @Description  Delete a location, locations with schedules can not be deleted
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path  int  true  "Location ID"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/locations/{id} [delete]
+func (h *AdminHandler) DeleteLocation(ctx *gin.Context) {
+	locationID, ok := adminPathID(ctx, "location")
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteLocation(ctx, locationID); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrLocationNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Location ID not found",
+			})
+		case errors.Is(err, repositories.ErrLocationInUse):
+			ctx.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   "Location has schedules and can not be deleted",
+			})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+		return
+	}
+
+	if err := utils.InvalidateCache(ctx, h.rdb, []string{"cinemas:"}); err != nil {
+		log.Println("Redis delete cache error:", err)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "location deleted successfully",
+	})
+}
+
+// GetPaymentMethods godoc
+// @Summary      List payment methods
+// @Description  List every payment method, disabled ones included
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  models.SuccessResponse{data=[]models.PaymentMethod}
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse   "Forbidden (no payments:manage permission)"
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/payment-methods [get]
+func (h *AdminHandler) GetPaymentMethods(ctx *gin.Context) {
+	methods, err := h.repo.GetPaymentMethods(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "data from database",
+		"data":    methods,
+	})
+}
+
+// helper, the provider of a payment method must be registered in the payment gateway
+func (h *AdminHandler) checkPaymentProvider(ctx *gin.Context, provider string) bool {
+	if !h.gateway.HasProvider(provider) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("Payment provider %s is not supported", provider),
+		})
+		return false
+	}
+	return true
+}
+
+// AddPaymentMethod godoc
+// @Summary      Add payment method
+// @Description  Add a payment method, the provider picks the payment gateway and fee is added to every order paid with it.
+// @Description  Providers without an implementation in the payment gateway are refused.
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        method  body  models.AddPaymentMethod  true  "Payment method"
+// @Success      200  {object}  models.SuccessResponse{data=models.PaymentMethod}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/payment-methods [post]
+func (h *AdminHandler) AddPaymentMethod(ctx *gin.Context) {
+	var req models.AddPaymentMethod
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if !h.checkPaymentProvider(ctx, req.Provider) {
+		return
+	}
+
+	method, err := h.repo.AddPaymentMethod(ctx, &req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "payment method add successfully",
+		"data":    method,
+	})
+}
+
+// UpdatePaymentMethod godoc
+// @Summary      Update payment method
+// @Description  Update the fields that are sent, is_active false disables the method for new orders.
+// @Description  Providers without an implementation in the payment gateway are refused.
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id      path  int                       true  "Payment method ID"
+// @Param        method  body  models.EditPaymentMethod  true  "Fields to update"
+// @Success      200  {object}  models.SuccessResponse{data=models.PaymentMethod}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/payment-methods/{id} [patch]
+func (h *AdminHandler) UpdatePaymentMethod(ctx *gin.Context) {
+	paymentMethodID, ok := adminPathID(ctx, "payment method")
+	if !ok {
+		return
+	}
+
+	var update models.EditPaymentMethod
+	if err := ctx.ShouldBind(&update); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if update.Provider != nil && !h.checkPaymentProvider(ctx, *update.Provider) {
+		return
+	}
+
+	method, err := h.repo.UpdatePaymentMethod(ctx, paymentMethodID, update)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPaymentMethodNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Payment method ID not found",
+			})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "payment method updated successfully",
+		"data":    method,
+	})
+}
+
+// DeletePaymentMethod godoc
+// @Summary      Delete payment method
+// @Description  Delete a payment method never used by an order, disable a used one instead
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path  int  true  "Payment method ID"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/payment-methods/{id} [delete]
+func (h *AdminHandler) DeletePaymentMethod(ctx *gin.Context) {
+	paymentMethodID, ok := adminPathID(ctx, "payment method")
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeletePaymentMethod(ctx, paymentMethodID); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrPaymentMethodNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Payment method ID not found",
+			})
+		case errors.Is(err, repositories.ErrPaymentMethodInUse):
+			ctx.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   "Payment method has orders, disable it instead",
+			})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "payment method deleted successfully",
+	})
+}
@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,33 +15,105 @@ import (
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthHandler struct {
-	repo       *repositories.UserRepository
-	JWTManager *utils.JWTManager
-	rdb        *redis.Client
+	repo         *repositories.UserRepository
+	sessionRepo  *repositories.SessionRepository
+	attempts     *repositories.LoginAttemptRepository
+	revocations  *repositories.TokenRevocationRepository
+	JWTManager   *utils.JWTManager
+	refreshTTL   time.Duration
+	mailer       utils.Mailer
+	actionTokens *utils.ActionTokenSigner
+	verification models.EmailVerificationConfig
+	reset        models.PasswordResetConfig
+	twoFactor    *TwoFactorHandler
 }
 
-func NewAuthHandler(repo *repositories.UserRepository, jwtManager *utils.JWTManager, rdb *redis.Client) *AuthHandler {
+func NewAuthHandler(repo *repositories.UserRepository, sessionRepo *repositories.SessionRepository, attempts *repositories.LoginAttemptRepository, revocations *repositories.TokenRevocationRepository, jwtManager *utils.JWTManager, refreshTTL time.Duration, mailer utils.Mailer, actionTokens *utils.ActionTokenSigner, verification models.EmailVerificationConfig, reset models.PasswordResetConfig, twoFactor *TwoFactorHandler) *AuthHandler {
 	return &AuthHandler{
-		repo:       repo,
-		JWTManager: jwtManager,
-		rdb:        rdb,
+		repo:         repo,
+		sessionRepo:  sessionRepo,
+		attempts:     attempts,
+		revocations:  revocations,
+		JWTManager:   jwtManager,
+		refreshTTL:   refreshTTL,
+		mailer:       mailer,
+		actionTokens: actionTokens,
+		verification: verification,
+		reset:        reset,
+		twoFactor:    twoFactor,
 	}
 }
 
 type LoginResponse struct {
-	Success bool   `json:"success" example:"true"`
-	Message string `json:"message" example:"Login successful"`
-	Token   string `json:"token,omitempty" example:"your token..."`
+	Success      bool   `json:"success" example:"true"`
+	Message      string `json:"message" example:"Login successful"`
+	Token        string `json:"token,omitempty" example:"your token..."`
+	RefreshToken string `json:"refresh_token,omitempty" example:"your refresh token..."`
+	ExpiresIn    int    `json:"expires_in,omitempty" example:"900"`
+}
+
+// start a session for the device and return the access and refresh token
+func (u *AuthHandler) startSession(ctx *gin.Context, user *models.User, mfa bool) (string, string, error) {
+	refreshToken, err := utils.RandomHex(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	sessionID, err := u.sessionRepo.CreateSession(ctx, models.NewSession{
+		UserID:    user.ID,
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+		TokenHash: utils.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(u.refreshTTL),
+		MFA:       mfa,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	token, err := u.JWTManager.GenerateToken(user, sessionID, mfa)
+	if err != nil {
+		return "", "", err
+	}
+	return token, refreshToken, nil
+}
+
+// access tokens of a revoked session are rejected until they expire
+func (u *AuthHandler) markSessionRevoked(ctx *gin.Context, userID, sessionID int) {
+	u.revocations.MarkSessionRevoked(ctx, userID, sessionID, u.JWTManager.AccessTTL())
+}
+
+// send the verification link to the user, the token is bound to the user and the email
+func (u *AuthHandler) sendVerificationEmail(ctx *gin.Context, userID int, email string) error {
+	token := u.actionTokens.Generate(utils.ActionVerifyEmail, fmt.Sprintf("%d:%s", userID, email), u.verification.TokenTTL)
+	link := fmt.Sprintf("%s/verify-email?token=%s", strings.TrimRight(u.verification.AppURL, "/"), url.QueryEscape(token))
+
+	return u.mailer.Send(ctx, models.EmailMessage{
+		To:      email,
+		Subject: "Verify your Tickitz email",
+		Body: fmt.Sprintf("Welcome to Tickitz!\n\nOpen the link below to verify your email, it expires in %s.\n\n%s\n\nIgnore this email if you did not create an account.",
+			u.verification.TokenTTL, link),
+	})
+}
+
+// answer 429 while the account or the IP is locked out
+func loginLocked(ctx *gin.Context, lockout time.Duration) {
+	seconds := int(math.Ceil(lockout.Seconds()))
+	ctx.Header("Retry-After", strconv.Itoa(seconds))
+	ctx.JSON(http.StatusTooManyRequests, gin.H{
+		"success":     false,
+		"error":       "too many failed login attempts, please try again later",
+		"retry_after": seconds,
+	})
 }
 
 // Register godoc
 // @Summary      Register a new user
-// @Description  Create a new user account with email, password, and role.
+// @Description  Create a new user account with email and password. A verification link is sent to the email, orders need a verified email.
 // @Tags         Authentication
 // @Accept       json
 // @Produce      json
@@ -56,10 +132,6 @@ func (u *AuthHandler) Register(ctx *gin.Context) {
 		return
 	}
 
-	if req.Role == "" {
-		req.Role = "user"
-	}
-
 	if err := utils.IsValidEmail(req.Email); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
@@ -88,7 +160,7 @@ func (u *AuthHandler) Register(ctx *gin.Context) {
 	user := models.User{
 		Email:          req.Email,
 		Password:       req.Password,
-		Role:           req.Role,
+		Role:           models.RoleUser,
 		VirtualAccount: va,
 	}
 
@@ -110,6 +182,14 @@ func (u *AuthHandler) Register(ctx *gin.Context) {
 		return
 	}
 
+	// the user can ask for a new link when this one is lost
+	if _, err := u.repo.MarkVerificationSent(ctx, user.ID, 0); err != nil {
+		log.Println("Mark verification sent error:", err)
+	}
+	if err := u.sendVerificationEmail(ctx, user.ID, user.Email); err != nil {
+		log.Println("Send verification email error:", err)
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data":    user.Email,
@@ -118,13 +198,17 @@ func (u *AuthHandler) Register(ctx *gin.Context) {
 
 // Login godoc
 // @Summary      User login
-// @Description  Authenticate user and return JWT token
+// @Description  Authenticate user and return a JWT access token and a refresh token for a new session.
+// @Description  Repeated failures lock the account and the IP for a growing time, see the Retry-After header.
+// @Description  Accounts with two-factor authentication get a challenge_token instead, to finish at /auth/login/2fa.
 // @Tags         Authentication
 // @Accept       json
 // @Produce      json
 // @Param        user body models.LoginUser true "User login credentials"
 // @Success      200  {object}  LoginResponse
 // @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse   "Account suspended"
+// @Failure      429  {object}  models.ErrorResponse   "Too many failed attempts"
 // @Failure      500  {object}  models.ErrorResponse
 // @Router       /auth/login [post]
 func (u *AuthHandler) Login(ctx *gin.Context) {
@@ -139,25 +223,86 @@ func (u *AuthHandler) Login(ctx *gin.Context) {
 		return
 	}
 
+	if lockout := u.attempts.GetLockout(ctx, user.Email, ctx.ClientIP()); lockout > 0 {
+		loginLocked(ctx, lockout)
+		return
+	}
+
 	dbUser, err := u.repo.GetUserByEmail(ctx, user.Email)
+	if err == nil {
+		err = bcrypt.CompareHashAndPassword([]byte(dbUser.Password), []byte(user.Password))
+	}
 	if err != nil {
+		// unknown emails are counted too, the response must not tell whether the email exists
+		if lockout := u.attempts.RegisterFailure(ctx, user.Email, ctx.ClientIP()); lockout > 0 {
+			loginLocked(ctx, lockout)
+			return
+		}
 		ctx.JSON(http.StatusUnauthorized, gin.H{
 			"success": false,
 			"error":   "invalid email or password",
 		})
 		return
 	}
+	// with 2FA the failures are kept until the second factor is right, otherwise every new challenge would reset them
+	if dbUser.TwoFactorEnabledAt == nil {
+		u.attempts.ResetAccount(ctx, user.Email)
+	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(dbUser.Password), []byte(user.Password)); err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{
+	if dbUser.SuspendedAt != nil {
+		ctx.JSON(http.StatusForbidden, gin.H{
 			"success": false,
-			"error":   "invalid email or password",
+			"error":   "Your account has been suspended",
+		})
+		return
+	}
+
+	u.completeLogin(ctx, dbUser)
+}
+
+// helper, fingerprint of the password and the 2FA state a login challenge was issued for,
+// a password reset or a new 2FA setup invalidates the pending challenges
+func loginChallengeState(user *models.User) string {
+	state := user.Password
+	if user.TwoFactorEnabledAt != nil {
+		state += "|" + strconv.FormatInt(user.TwoFactorEnabledAt.UnixNano(), 10)
+	}
+	return utils.HashToken(state)[:16]
+}
+
+// the first factor is right, the tokens are issued after the second factor when the user has 2FA
+func (u *AuthHandler) completeLogin(ctx *gin.Context, user *models.User) {
+	if user.TwoFactorEnabledAt != nil {
+		// the nonce makes every challenge usable once
+		nonce, err := utils.RandomHex(16)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "failed to start two-factor authentication",
+			})
+			return
+		}
+
+		ttl := u.twoFactor.config.ChallengeTTL
+		subject := fmt.Sprintf("%d:%s:%s", user.ID, nonce, loginChallengeState(user))
+		ctx.JSON(http.StatusOK, gin.H{
+			"success":             true,
+			"message":             "Two-factor authentication required",
+			"two_factor_required": true,
+			"challenge_token":     u.actionTokens.Generate(utils.ActionLoginChallenge, subject, ttl),
+			"expires_in":          int(ttl.Seconds()),
 		})
 		return
 	}
 
-	token, err := u.JWTManager.GenerateToken(dbUser)
+	u.loginSucceeded(ctx, user, false)
+}
+
+// start the session and answer with the tokens
+func (u *AuthHandler) loginSucceeded(ctx *gin.Context, user *models.User, mfa bool) {
+	token, refreshToken, err := u.startSession(ctx, user, mfa)
 	if err != nil {
+		log.Println("Start session error:", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   "failed to generate token",
@@ -166,18 +311,381 @@ func (u *AuthHandler) Login(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
+		"success":                   true,
+		"message":                   "Login successful",
+		"token":                     token,
+		"refresh_token":             refreshToken,
+		"expires_in":                int(u.JWTManager.AccessTTL().Seconds()),
+		"role":                      user.Role,
+		"email":                     user.Email,
+		"email_verified":            user.EmailVerifiedAt != nil,
+		"two_factor_setup_required": !mfa && u.twoFactor.required(user.Role),
+	})
+}
+
+// LoginTwoFactor godoc
+// @Summary      Login second step
+// @Description  Finish a login of an account with two-factor authentication, with the challenge token of /auth/login
+// @Description  and a code of the authenticator app or a recovery code. Failures count towards the login lockout.
+// @Description  A challenge finishes one login, and is refused once the password or the 2FA setup changed.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param        login body models.LoginTwoFactorRequest true "Challenge token and code"
+// @Success      200  {object}  LoginResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse   "Account suspended"
+// @Failure      429  {object}  models.ErrorResponse   "Too many failed attempts"
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /auth/login/2fa [post]
+func (u *AuthHandler) LoginTwoFactor(ctx *gin.Context) {
+	var req models.LoginTwoFactorRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	subject, err := u.actionTokens.Verify(utils.ActionLoginChallenge, req.ChallengeToken)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Invalid or expired challenge, please login again",
+		})
+		return
+	}
+	parts := strings.Split(subject, ":")
+	if len(parts) != 3 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Invalid or expired challenge, please login again",
+		})
+		return
+	}
+	userID, _ := strconv.Atoi(parts[0])
+	nonce, state := parts[1], parts[2]
+
+	user, err := u.repo.GetUserByID(ctx, userID)
+	if err != nil || user.TwoFactorEnabledAt == nil || loginChallengeState(user) != state {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Invalid or expired challenge, please login again",
+		})
+		return
+	}
+
+	if lockout := u.attempts.GetLockout(ctx, user.Email, ctx.ClientIP()); lockout > 0 {
+		loginLocked(ctx, lockout)
+		return
+	}
+
+	valid, err := u.twoFactor.verifyCode(ctx, user.ID, req.Code)
+	if err != nil {
+		log.Println("Verify second factor error:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to verify code",
+		})
+		return
+	}
+	if !valid {
+		if lockout := u.attempts.RegisterFailure(ctx, user.Email, ctx.ClientIP()); lockout > 0 {
+			loginLocked(ctx, lockout)
+			return
+		}
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "invalid code",
+		})
+		return
+	}
+
+	// a challenge finishes one login, a copy of it can not start another session
+	fresh, err := u.twoFactor.repo.UseLoginChallenge(ctx, user.ID, utils.HashToken(nonce), time.Now().Add(u.twoFactor.config.ChallengeTTL))
+	if err != nil {
+		log.Println("Use login challenge error:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to verify code",
+		})
+		return
+	}
+	if !fresh {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Invalid or expired challenge, please login again",
+		})
+		return
+	}
+	u.attempts.ResetAccount(ctx, user.Email)
+
+	if user.SuspendedAt != nil {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Your account has been suspended",
+		})
+		return
+	}
+
+	u.loginSucceeded(ctx, user, true)
+}
+
+// VerifyEmail godoc
+// @Summary      Verify email
+// @Description  Verify the email of the user with the token of the verification link
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param        token body models.VerifyEmailRequest true "Verification token"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /auth/verify-email [post]
+func (u *AuthHandler) VerifyEmail(ctx *gin.Context) {
+	var req models.VerifyEmailRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	subject, err := u.actionTokens.Verify(utils.ActionVerifyEmail, req.Token)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid or expired verification link",
+		})
+		return
+	}
+
+	rawUserID, email, _ := strings.Cut(subject, ":")
+	userID, err := strconv.Atoi(rawUserID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid or expired verification link",
+		})
+		return
+	}
+
+	// a link sent to an old email does not verify the new one
+	verified, err := u.repo.VerifyEmail(ctx, userID, email)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if !verified {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid or expired verification link",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Email verified",
+	})
+}
+
+// ResendVerification godoc
+// @Summary      Resend verification email
+// @Description  Send a new verification link, at most once every VERIFY_RESEND_SECONDS.
+// @Description  The response is the same for unknown, already verified and recently sent emails.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param        email body models.ResendVerificationRequest true "Email of the account"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Router       /auth/resend-verification [post]
+func (u *AuthHandler) ResendVerification(ctx *gin.Context) {
+	var req models.ResendVerificationRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// failures are only logged, the response must not tell whether the email exists or is verified
+	sent := gin.H{
+		"success": true,
+		"message": "If the email is registered and not verified yet, a verification link has been sent",
+	}
+
+	user, err := u.repo.GetUserByEmail(ctx, req.Email)
+	if err != nil || user.EmailVerifiedAt != nil {
+		ctx.JSON(http.StatusOK, sent)
+		return
+	}
+
+	claimed, err := u.repo.MarkVerificationSent(ctx, user.ID, u.verification.ResendCooldown)
+	if err != nil {
+		log.Println("Mark verification sent error:", err)
+		ctx.JSON(http.StatusOK, sent)
+		return
+	}
+	// a link was sent recently, the cooldown is not reported
+	if !claimed {
+		ctx.JSON(http.StatusOK, sent)
+		return
+	}
+
+	if err := u.sendVerificationEmail(ctx, user.ID, user.Email); err != nil {
+		log.Println("Send verification email error:", err)
+	}
+
+	ctx.JSON(http.StatusOK, sent)
+}
+
+// ForgotPassword godoc
+// @Summary      Forgot password
+// @Description  Send a password reset link to the email. The response is the same whether the email is registered or not,
+// @Description  a new link is sent at most once every PASSWORD_RESET_COOLDOWN_SECONDS.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param        email body models.ForgotPasswordRequest true "Email of the account"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Router       /auth/forgot-password [post]
+func (u *AuthHandler) ForgotPassword(ctx *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// failures are only logged, the response must not tell whether the email exists
+	sent := gin.H{
 		"success": true,
-		"message": "Login successful",
-		"token":   token,
-		"role":    dbUser.Role,
-		"email":   dbUser.Email,
+		"message": "If the email is registered, a password reset link has been sent",
+	}
+
+	user, err := u.repo.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		ctx.JSON(http.StatusOK, sent)
+		return
+	}
+
+	token, err := utils.RandomHex(32)
+	if err != nil {
+		log.Println("Generate reset token error:", err)
+		ctx.JSON(http.StatusOK, sent)
+		return
+	}
+
+	created, err := u.repo.CreatePasswordResetToken(ctx, user.ID, utils.HashToken(token), time.Now().Add(u.reset.TokenTTL), u.reset.Cooldown)
+	if err != nil {
+		log.Println("Create reset token error:", err)
+		ctx.JSON(http.StatusOK, sent)
+		return
+	}
+	if !created {
+		ctx.JSON(http.StatusOK, sent)
+		return
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", strings.TrimRight(u.verification.AppURL, "/"), url.QueryEscape(token))
+	err = u.mailer.Send(ctx, models.EmailMessage{
+		To:      user.Email,
+		Subject: "Reset your Tickitz password",
+		Body: fmt.Sprintf("Open the link below to choose a new password, it expires in %s and can be used once.\n\n%s\n\nIgnore this email if you did not ask for a password reset.",
+			u.reset.TokenTTL, link),
 	})
+	if err != nil {
+		log.Println("Send reset email error:", err)
+	}
 
+	ctx.JSON(http.StatusOK, sent)
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Set a new password with the token of the reset link. The token can be used once, every session of the user is logged out.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param        reset body models.ResetPasswordRequest true "Reset token and new password"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /auth/reset-password [post]
+func (u *AuthHandler) ResetPassword(ctx *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if err := utils.IsValidPassword(req.NewPassword); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	hashedPass, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to hash password",
+		})
+		return
+	}
+
+	userID, err := u.repo.ResetPassword(ctx, utils.HashToken(req.Token), string(hashedPass))
+	if err != nil {
+		if errors.Is(err, repositories.ErrResetTokenInvalid) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid or expired reset link",
+			})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// whoever had the old password is logged out
+	if err := u.revocations.RevokeAllUserTokens(ctx, userID, models.SessionRevokedPasswordReset); err != nil {
+		log.Println("Revoke user tokens error:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "password has been reset but the sessions could not be ended, please logout from every device",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Password has been reset, please login again",
+	})
 }
 
 // Logout godoc
 // @Summary Logout user and invalidate JWT token
-// @Description Invalidate the JWT token by adding it to Redis blacklist so it cannot be used again
+// @Description Revoke the JWT token by its jti until it expires, and end its session
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -187,31 +695,221 @@ func (u *AuthHandler) Login(ctx *gin.Context) {
 // @Failure 500 {object} models.ErrorResponse "Internal server error during logout"
 // @Router /auth/logout [post]
 func (u *AuthHandler) Logout(ctx *gin.Context) {
-	authHeader := ctx.GetHeader("Authorization")
-	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-	tokenString = strings.TrimSpace(tokenString)
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
 
-	redisKey := fmt.Sprintf("blacklist:%s", tokenString)
+	// the token stays revoked until it would have expired
+	if err := u.revocations.RevokeToken(ctx, claims.UserID, claims.ID, claims.ExpiresAt.Time); err != nil {
+		log.Println("Revoke token error:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to logout",
+		})
+		return
+	}
 
-	if tokenString == "" {
-		ctx.JSON(http.StatusUnauthorized, gin.H{
+	// end the session so its refresh token can not be used anymore
+	if claims.SessionID != 0 {
+		if _, err := u.sessionRepo.RevokeSession(ctx, claims.UserID, claims.SessionID, models.SessionRevokedLogout); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "failed to logout",
+			})
+			return
+		}
+		// the other access tokens of the session are refused too
+		u.markSessionRevoked(ctx, claims.UserID, claims.SessionID)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Logout successful",
+	})
+}
+
+// LogoutAll godoc
+// @Summary Logout from every device
+// @Description Invalidate every token issued to the user until now and end all sessions
+// @Tags Authentication
+// @Produce json
+// @Security     BearerAuth
+// @Success 200 {object} models.SuccessResponse "Logout successful"
+// @Failure 401 {object} models.ErrorResponse "Token is required or unauthorized"
+// @Failure 500 {object} models.ErrorResponse "Internal server error during logout"
+// @Router /auth/logout-all [post]
+func (u *AuthHandler) LogoutAll(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	if err := u.revocations.RevokeAllUserTokens(ctx, claims.UserID, models.SessionRevokedLogoutAll); err != nil {
+		log.Println("Revoke user tokens error:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"error":   "Token is required",
+			"error":   "failed to logout",
 		})
 		return
 	}
 
-	err := utils.SetCache(ctx, u.rdb, redisKey, true, 30*time.Minute)
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Logged out from every device",
+	})
+}
+
+// Refresh godoc
+// @Summary      Refresh access token
+// @Description  Exchange a refresh token for a new access token and a new refresh token, the old refresh token can not be used again.
+// @Description  Using an already exchanged refresh token revokes the whole session.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param        token body models.RefreshTokenRequest true "Refresh token"
+// @Success      200  {object}  LoginResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /auth/refresh [post]
+func (u *AuthHandler) Refresh(ctx *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	refreshToken, err := utils.RandomHex(32)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"error":   "failed to logout",
+			"error":   "failed to generate token",
+		})
+		return
+	}
+
+	session, err := u.sessionRepo.RotateRefreshToken(ctx, utils.HashToken(req.RefreshToken), utils.HashToken(refreshToken), time.Now().Add(u.refreshTTL))
+	if err != nil {
+		var reused *repositories.RefreshTokenReusedError
+		switch {
+		case errors.As(err, &reused):
+			log.Println("Refresh token reuse detected:", err)
+			u.markSessionRevoked(ctx, reused.UserID, reused.SessionID)
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "refresh token already used, session revoked, please login again",
+			})
+		case errors.Is(err, repositories.ErrRefreshTokenInvalid), errors.Is(err, repositories.ErrRefreshTokenExpired):
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "failed to refresh token",
+			})
+		}
+		return
+	}
+
+	token, err := u.JWTManager.GenerateToken(&session.User, session.ID, session.MFA)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to generate token",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success":       true,
+		"message":       "Token refreshed",
+		"token":         token,
+		"refresh_token": refreshToken,
+		"expires_in":    int(u.JWTManager.AccessTTL().Seconds()),
+	})
+}
+
+// GetSessions godoc
+// @Summary      List sessions
+// @Description  List the logged-in devices of the user, current marks the session of the token
+// @Tags         Authentication
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  models.SuccessResponse{data=[]models.Session}
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /auth/sessions [get]
+func (u *AuthHandler) GetSessions(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	sessions, err := u.sessionRepo.GetUserSessions(ctx, claims.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
 		})
 		return
 	}
 
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == claims.SessionID
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "Logout successful",
+		"message": "data from database",
+		"data":    sessions,
+	})
+}
+
+// RevokeSession godoc
+// @Summary      Revoke session
+// @Description  Log out a device of the user, its refresh token and access token stop working
+// @Tags         Authentication
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  int  true  "Session ID"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /auth/sessions/{id} [delete]
+func (u *AuthHandler) RevokeSession(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	sessionID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || sessionID <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid session id",
+		})
+		return
+	}
+
+	revoked, err := u.sessionRepo.RevokeSession(ctx, claims.UserID, sessionID, models.SessionRevokedByUser)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if !revoked {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Session not found",
+		})
+		return
+	}
+	u.markSessionRevoked(ctx, claims.UserID, sessionID)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Session revoked",
 	})
 }
@@ -0,0 +1,645 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+type CatalogHandler struct {
+	repo *repositories.CatalogRepository
+	rdb  *redis.Client
+}
+
+func NewCatalogHandler(repo *repositories.CatalogRepository, rdb *redis.Client) *CatalogHandler {
+	return &CatalogHandler{
+		repo: repo,
+		rdb:  rdb,
+	}
+}
+
+// the public movie lists and filters show genres, casts and directors
+func (h *CatalogHandler) invalidateMovies(ctx *gin.Context) {
+	if err := utils.InvalidateCache(ctx, h.rdb, []string{"movies:"}); err != nil {
+		log.Println("Redis delete cache error:", err)
+	}
+}
+
+// helper, reads the page and search of a catalog list
+func catalogFilter(ctx *gin.Context) (models.CatalogFilter, int) {
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit := 20
+
+	return models.CatalogFilter{
+		Search: strings.TrimSpace(ctx.Query("search")),
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	}, page
+}
+
+// helper
+func catalogListed(ctx *gin.Context, page, limit, count, totalCount int, data any) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"message":     "data from database",
+		"page":        page,
+		"limit":       limit,
+		"count":       count,
+		"total":       totalCount,
+		"total_pages": (totalCount + limit - 1) / limit,
+		"data":        data,
+	})
+}
+
+// helper
+func bindMerge(ctx *gin.Context) (*models.MergeRequest, bool) {
+	var req models.MergeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return nil, false
+	}
+	return &req, true
+}
+
+// helper
+func catalogWriteFailed(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrGenreNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Genre ID not found",
+		})
+	case errors.Is(err, repositories.ErrPersonNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Person ID not found",
+		})
+	case errors.Is(err, repositories.ErrMergeIntoItself), errors.Is(err, repositories.ErrMergeSourceGone):
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, repositories.ErrGenreExists):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, repositories.ErrGenreInUse), errors.Is(err, repositories.ErrPersonInUse):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error() + ", merge it instead",
+		})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+}
+
+// GetGenres godoc
+// @Summary      List genres
+// @Description  List the genres by name with the number of movies using them
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page    query  int     false  "Page"
+// @Param        search  query  string  false  "Name"
+// @Success      200  {object}  models.SuccessResponse{data=[]models.Genre}
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse   "Forbidden (no movies:write permission)"
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/genres [get]
+func (h *CatalogHandler) GetGenres(ctx *gin.Context) {
+	filter, page := catalogFilter(ctx)
+
+	genres, totalCount, err := h.repo.GetGenres(ctx, filter)
+	if err != nil {
+		catalogWriteFailed(ctx, err)
+		return
+	}
+	catalogListed(ctx, page, filter.Limit, len(genres), totalCount, genres)
+}
+
+// AddGenre godoc
+// @Summary      Add genre
+// @Description  Add a genre, names are unique ignoring case
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        genre  body  models.GenreRequest  true  "Genre"
+// @Success      200  {object}  models.SuccessResponse{data=models.Genre}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/genres [post]
+func (h *CatalogHandler) AddGenre(ctx *gin.Context) {
+	var req models.GenreRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	genre, err := h.repo.AddGenre(ctx, strings.TrimSpace(req.Name))
+	if err != nil {
+		catalogWriteFailed(ctx, err)
+		return
+	}
+	h.invalidateMovies(ctx)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "genre added successfully",
+		"data":    genre,
+	})
+}
+
+// UpdateGenre godoc
+// @Summary      Rename genre
+// @Description  Rename a genre, the movies using it keep it
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id     path  int                  true  "Genre ID"
+// @Param        genre  body  models.GenreRequest  true  "Genre"
+// @Success      200  {object}  models.SuccessResponse{data=models.Genre}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/genres/{id} [patch]
+func (h *CatalogHandler) UpdateGenre(ctx *gin.Context) {
+	genreID, ok := adminPathID(ctx, "genre")
+	if !ok {
+		return
+	}
+
+	var req models.GenreRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	genre, err := h.repo.UpdateGenre(ctx, genreID, strings.TrimSpace(req.Name))
+	if err != nil {
+		catalogWriteFailed(ctx, err)
+		return
+	}
+	h.invalidateMovies(ctx)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "genre updated successfully",
+		"data":    genre,
+	})
+}
+
+// DeleteGenre godoc
+// @Summary      Delete genre
+// @Description  Delete a genre no movie uses, merge a used genre into another instead
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path  int  true  "Genre ID"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/genres/{id} [delete]
+func (h *CatalogHandler) DeleteGenre(ctx *gin.Context) {
+	genreID, ok := adminPathID(ctx, "genre")
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteGenre(ctx, genreID); err != nil {
+		catalogWriteFailed(ctx, err)
+		return
+	}
+	h.invalidateMovies(ctx)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "genre deleted successfully",
+	})
+}
+
+// MergeGenres godoc
+// @Summary      Merge genres
+// @Description  Move the movies of the source genres to the genre of the path and delete the sources, in one transaction
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id     path  int                  true  "Target genre ID"
+// @Param        merge  body  models.MergeRequest  true  "Source genres"
+// @Success      200  {object}  models.SuccessResponse{data=models.Genre}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/genres/{id}/merge [post]
+func (h *CatalogHandler) MergeGenres(ctx *gin.Context) {
+	genreID, ok := adminPathID(ctx, "genre")
+	if !ok {
+		return
+	}
+	req, ok := bindMerge(ctx)
+	if !ok {
+		return
+	}
+
+	genre, err := h.repo.MergeGenres(ctx, genreID, req.SourceIDs)
+	if err != nil {
+		catalogWriteFailed(ctx, err)
+		return
+	}
+	h.invalidateMovies(ctx)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "genres merged successfully",
+		"data":    genre,
+	})
+}
+
+// helper, casts and directors are handled the same, their photos are kept in public/<kind>
+func (h *CatalogHandler) getPeople(ctx *gin.Context, kind string) {
+	filter, page := catalogFilter(ctx)
+
+	people, totalCount, err := h.repo.GetPeople(ctx, kind, filter)
+	if err != nil {
+		catalogWriteFailed(ctx, err)
+		return
+	}
+	catalogListed(ctx, page, filter.Limit, len(people), totalCount, people)
+}
+
+// helper
+func (h *CatalogHandler) addPerson(ctx *gin.Context, kind string) {
+	var req models.AddPerson
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+
+	photoPath, err := utils.UploadFile(ctx, "photo", "public/"+kind, "person", kind)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to upload photo",
+		})
+		return
+	}
+	if photoPath != "" {
+		req.PhotoPath = &photoPath
+	}
+
+	person, err := h.repo.AddPerson(ctx, kind, &req)
+	if err != nil {
+		if err := utils.RemoveUploadedFile("public/"+kind, photoPath); err != nil {
+			log.Println("Remove photo error:", err)
+		}
+		catalogWriteFailed(ctx, err)
+		return
+	}
+	h.invalidateMovies(ctx)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "person added successfully",
+		"data":    person,
+	})
+}
+
+// helper
+func (h *CatalogHandler) updatePerson(ctx *gin.Context, kind string) {
+	personID, ok := adminPathID(ctx, "person")
+	if !ok {
+		return
+	}
+
+	var update models.EditPerson
+	if err := ctx.ShouldBind(&update); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		update.Name = &name
+	}
+
+	photoPath, err := utils.UploadFile(ctx, "photo", "public/"+kind, "person", kind)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to upload photo",
+		})
+		return
+	}
+	if photoPath != "" {
+		update.PhotoPath = &photoPath
+	}
+
+	person, oldPhoto, err := h.repo.UpdatePerson(ctx, kind, personID, update)
+	if err != nil {
+		if err := utils.RemoveUploadedFile("public/"+kind, photoPath); err != nil {
+			log.Println("Remove photo error:", err)
+		}
+		catalogWriteFailed(ctx, err)
+		return
+	}
+
+	if oldPhoto != nil {
+		if err := utils.RemoveUploadedFile("public/"+kind, *oldPhoto); err != nil {
+			log.Println("Remove photo error:", err)
+		}
+	}
+	h.invalidateMovies(ctx)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "person updated successfully",
+		"data":    person,
+	})
+}
+
+// helper
+func (h *CatalogHandler) deletePerson(ctx *gin.Context, kind string) {
+	personID, ok := adminPathID(ctx, "person")
+	if !ok {
+		return
+	}
+
+	photo, err := h.repo.DeletePerson(ctx, kind, personID)
+	if err != nil {
+		catalogWriteFailed(ctx, err)
+		return
+	}
+
+	if photo != nil {
+		if err := utils.RemoveUploadedFile("public/"+kind, *photo); err != nil {
+			log.Println("Remove photo error:", err)
+		}
+	}
+	h.invalidateMovies(ctx)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "person deleted successfully",
+	})
+}
+
+// helper
+func (h *CatalogHandler) mergePeople(ctx *gin.Context, kind string) {
+	personID, ok := adminPathID(ctx, "person")
+	if !ok {
+		return
+	}
+	req, ok := bindMerge(ctx)
+	if !ok {
+		return
+	}
+
+	person, photos, err := h.repo.MergePeople(ctx, kind, personID, req.SourceIDs)
+	if err != nil {
+		catalogWriteFailed(ctx, err)
+		return
+	}
+
+	for _, photo := range photos {
+		if err := utils.RemoveUploadedFile("public/"+kind, photo); err != nil {
+			log.Println("Remove photo error:", err)
+		}
+	}
+	h.invalidateMovies(ctx)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "people merged successfully",
+		"data":    person,
+	})
+}
+
+// GetCasts godoc
+// @Summary      List casts
+// @Description  List the casts by name with their photo, bio and number of movies
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page    query  int     false  "Page"
+// @Param        search  query  string  false  "Name"
+// @Success      200  {object}  models.SuccessResponse{data=[]models.Person}
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse   "Forbidden (no movies:write permission)"
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/casts [get]
+func (h *CatalogHandler) GetCasts(ctx *gin.Context) {
+	h.getPeople(ctx, models.PersonCast)
+}
+
+// AddCast godoc
+// @Summary      Add cast
+// @Description  Add a cast with an optional photo and bio
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        name   formData  string  true   "Name"
+// @Param        bio    formData  string  false  "Biography"
+// @Param        photo  formData  file    false  "Photo"
+// @Success      200  {object}  models.SuccessResponse{data=models.Person}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/casts [post]
+func (h *CatalogHandler) AddCast(ctx *gin.Context) {
+	h.addPerson(ctx, models.PersonCast)
+}
+
+// UpdateCast godoc
+// @Summary      Update cast
+// @Description  Update the fields sent, a new photo replaces the old one and an empty bio removes it
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id     path      int     true   "Cast ID"
+// @Param        name   formData  string  false  "Name"
+// @Param        bio    formData  string  false  "Biography"
+// @Param        photo  formData  file    false  "Photo"
+// @Success      200  {object}  models.SuccessResponse{data=models.Person}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/casts/{id} [patch]
+func (h *CatalogHandler) UpdateCast(ctx *gin.Context) {
+	h.updatePerson(ctx, models.PersonCast)
+}
+
+// DeleteCast godoc
+// @Summary      Delete cast
+// @Description  Delete a cast credited on no movie, merge a credited cast into another instead
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path  int  true  "Cast ID"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/casts/{id} [delete]
+func (h *CatalogHandler) DeleteCast(ctx *gin.Context) {
+	h.deletePerson(ctx, models.PersonCast)
+}
+
+// MergeCasts godoc
+// @Summary      Merge casts
+// @Description  Move the movies of the source casts to the cast of the path and delete the sources. The target keeps its name,
+// @Description  a missing photo or bio is taken from a source.
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id     path  int                  true  "Target cast ID"
+// @Param        merge  body  models.MergeRequest  true  "Source casts"
+// @Success      200  {object}  models.SuccessResponse{data=models.Person}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/casts/{id}/merge [post]
+func (h *CatalogHandler) MergeCasts(ctx *gin.Context) {
+	h.mergePeople(ctx, models.PersonCast)
+}
+
+// GetDirectors godoc
+// @Summary      List directors
+// @Description  List the directors by name with their photo, bio and number of movies
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page    query  int     false  "Page"
+// @Param        search  query  string  false  "Name"
+// @Success      200  {object}  models.SuccessResponse{data=[]models.Person}
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse   "Forbidden (no movies:write permission)"
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/directors [get]
+func (h *CatalogHandler) GetDirectors(ctx *gin.Context) {
+	h.getPeople(ctx, models.PersonDirector)
+}
+
+// AddDirector godoc
+// @Summary      Add director
+// @Description  Add a director with an optional photo and bio
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        name   formData  string  true   "Name"
+// @Param        bio    formData  string  false  "Biography"
+// @Param        photo  formData  file    false  "Photo"
+// @Success      200  {object}  models.SuccessResponse{data=models.Person}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/directors [post]
+func (h *CatalogHandler) AddDirector(ctx *gin.Context) {
+	h.addPerson(ctx, models.PersonDirector)
+}
+
+// UpdateDirector godoc
+// @Summary      Update director
+// @Description  Update the fields sent, a new photo replaces the old one and an empty bio removes it
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id     path      int     true   "Director ID"
+// @Param        name   formData  string  false  "Name"
+// @Param        bio    formData  string  false  "Biography"
+// @Param        photo  formData  file    false  "Photo"
+// @Success      200  {object}  models.SuccessResponse{data=models.Person}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/directors/{id} [patch]
+func (h *CatalogHandler) UpdateDirector(ctx *gin.Context) {
+	h.updatePerson(ctx, models.PersonDirector)
+}
+
+// DeleteDirector godoc
+// @Summary      Delete director
+// @Description  Delete a director of no movie, merge a director of movies into another instead
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path  int  true  "Director ID"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/directors/{id} [delete]
+func (h *CatalogHandler) DeleteDirector(ctx *gin.Context) {
+	h.deletePerson(ctx, models.PersonDirector)
+}
+
+// MergeDirectors godoc
+// @Summary      Merge directors
+// @Description  Move the movies of the source directors to the director of the path and delete the sources. The target keeps its name,
+// @Description  a missing photo or bio is taken from a source.
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id     path  int                  true  "Target director ID"
+// @Param        merge  body  models.MergeRequest  true  "Source directors"
+// @Success      200  {object}  models.SuccessResponse{data=models.Person}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/directors/{id}/merge [post]
+func (h *CatalogHandler) MergeDirectors(ctx *gin.Context) {
+	h.mergePeople(ctx, models.PersonDirector)
+}
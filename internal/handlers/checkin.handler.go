@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+type CheckInHandler struct {
+	repo    *repositories.CheckInRepository
+	tickets *utils.TicketSigner
+	window  models.CheckInWindow
+	rdb     *redis.Client
+}
+
+func NewCheckInHandler(repo *repositories.CheckInRepository, tickets *utils.TicketSigner, window models.CheckInWindow, rdb *redis.Client) *CheckInHandler {
+	return &CheckInHandler{
+		repo:    repo,
+		tickets: tickets,
+		window:  window,
+		rdb:     rdb,
+	}
+}
+
+func checkInRejected(ctx *gin.Context, code int, status, message string, ticket *models.CheckInTicket) {
+	response := gin.H{
+		"success": false,
+		"status":  status,
+		"error":   message,
+	}
+	if ticket != nil {
+		response["data"] = ticket
+	}
+	ctx.JSON(code, response)
+}
+
+// CheckIn godoc
+// @Summary      Check in a ticket
+// @Description  Verify a scanned ticket code at the gate and mark it used. The ticket must be paid, for the cinema of the staff
+// @Description  and scanned between CHECKIN_OPEN_MINUTES before and CHECKIN_CLOSE_MINUTES after the showtime.
+// @Description  A ticket is accepted once, the status field tells why a scan is rejected.
+// @Tags         Check-in
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        checkin body models.CheckInRequest true "Scanned ticket code, cinemas_schedule_id of the gate is optional"
+// @Success      200  {object} models.SuccessResponse{data=models.CheckInTicket}
+// @Failure      400  {object} models.ErrorResponse
+// @Failure      401  {object} models.ErrorResponse   "Unauthorized or invalid token"
+// @Failure      403  {object} models.ErrorResponse   "Forbidden (no checkin:scan permission)"
+// @Failure      404  {object} models.ErrorResponse
+// @Failure      409  {object} models.ErrorResponse
+// @Failure      500  {object} models.ErrorResponse
+// @Router       /checkin [post]
+func (h *CheckInHandler) CheckIn(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	var req models.CheckInRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ticketClaims, err := h.tickets.Verify(req.Code)
+	if err != nil {
+		checkInRejected(ctx, http.StatusBadRequest, models.CheckInStatusInvalid, "Invalid ticket code", nil)
+		return
+	}
+
+	ticket, err := h.repo.GetCheckInTicket(ctx, ticketClaims.OrderID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrOrderNotFound) {
+			checkInRejected(ctx, http.StatusNotFound, models.CheckInStatusInvalid, "Ticket not found", nil)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	// the code must be the one issued for the order
+	if ticket.QRCode != req.Code {
+		checkInRejected(ctx, http.StatusBadRequest, models.CheckInStatusInvalid, "Invalid ticket code", nil)
+		return
+	}
+
+	if ticket.CheckedInAt != nil {
+		checkInRejected(ctx, http.StatusConflict, models.CheckInStatusAlreadyUsed, "Ticket already used", ticket)
+		return
+	}
+	if ticket.PaymentStatus != models.PaymentStatusPaid {
+		checkInRejected(ctx, http.StatusConflict, models.CheckInStatusNotPaid, "Ticket is not paid", ticket)
+		return
+	}
+
+	// admins can scan at every cinema, staff only at their own
+	if !utils.CinemaInScope(ctx, ticket.CinemaID) {
+		checkInRejected(ctx, http.StatusConflict, models.CheckInStatusWrongCinema, "Ticket is for another cinema", ticket)
+		return
+	}
+	if req.CinemasScheduleID != 0 && req.CinemasScheduleID != ticket.CinemasScheduleID {
+		checkInRejected(ctx, http.StatusConflict, models.CheckInStatusWrongScreening, "Ticket is for another screening", ticket)
+		return
+	}
+
+	if ticket.UntilShowtime > h.window.OpenBefore {
+		checkInRejected(ctx, http.StatusConflict, models.CheckInStatusTooEarly, "Check-in is not open yet for this screening", ticket)
+		return
+	}
+	if -ticket.UntilShowtime > h.window.CloseAfter {
+		checkInRejected(ctx, http.StatusConflict, models.CheckInStatusTooLate, "Check-in is closed for this screening", ticket)
+		return
+	}
+
+	checkedInAt, err := h.repo.CheckIn(ctx, ticket.OrderID, claims.UserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTicketAlreadyUsed) {
+			checkInRejected(ctx, http.StatusConflict, models.CheckInStatusAlreadyUsed, "Ticket already used", ticket)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	ticket.CheckedInAt = &checkedInAt
+
+	if err := utils.InvalidateCache(ctx, h.rdb, []string{"users:"}); err != nil {
+		log.Println("Redis delete cache error:", err)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"status":  models.CheckInStatusCheckedIn,
+		"message": "Ticket checked in",
+		"data":    ticket,
+	})
+}
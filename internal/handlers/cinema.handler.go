@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -11,18 +12,36 @@ import (
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"github.com/redis/go-redis/v9"
 )
 
 type CinemaHandler struct {
-	repo *repositories.CinemaRepository
-	rdb  *redis.Client
+	repo     *repositories.CinemaRepository
+	holdRepo *repositories.SeatHoldRepository
+	rdb      *redis.Client
 }
 
-func NewCinemaHandler(repo *repositories.CinemaRepository, rdb *redis.Client) *CinemaHandler {
+func NewCinemaHandler(repo *repositories.CinemaRepository, holdRepo *repositories.SeatHoldRepository, rdb *redis.Client) *CinemaHandler {
 	return &CinemaHandler{
-		repo: repo,
-		rdb:  rdb,
+		repo:     repo,
+		holdRepo: holdRepo,
+		rdb:      rdb,
+	}
+}
+
+// mark seats that are temporarily held during checkout, holds are not cached with the layout
+func (h *CinemaHandler) applySeatHolds(ctx *gin.Context, layout *models.SeatLayout) {
+	held, err := h.holdRepo.GetScheduleHolds(ctx, layout.CinemaScheduleID)
+	if err != nil {
+		log.Println("Get seat holds error:", err)
+		return
+	}
+
+	for i, seat := range layout.Seats {
+		if _, ok := held[seat.SeatID]; ok && seat.Status == models.SeatStatusAvailable {
+			layout.Seats[i].Status = models.SeatStatusHeld
+		}
 	}
 }
 
@@ -151,13 +170,14 @@ func (h *CinemaHandler) GetCinemaLocation(ctx *gin.Context) {
 }
 
 // GetAvailableSeats godoc
-// @Summary      Get available seats for a cinema schedule
-// @Description  Retrieve list of available seats by cinema schedule ID
+// @Summary      Get seat layout for a cinema schedule
+// @Description  Retrieve the auditorium seat layout of a cinema schedule with the availability of every seat
 // @Tags         Cinemas
 // @Produce      json
 // @Param        cinemas_schedule_id path int true "Cinema Schedule ID"
-// @Success      200  {object} models.SuccessResponse
+// @Success      200  {object} models.SuccessResponse{data=models.SeatLayout}
 // @Failure      400  {object} models.ErrorResponse
+// @Failure      404  {object} models.ErrorResponse
 // @Failure      500  {object} models.ErrorResponse
 // @Router       /cinemas/available-seats/{cinemas_schedule_id} [get]
 func (h *CinemaHandler) GetAvailableSeats(ctx *gin.Context) {
@@ -190,14 +210,15 @@ func (h *CinemaHandler) GetAvailableSeats(ctx *gin.Context) {
 	}
 
 	redisKey := fmt.Sprintf("cinemas:available-seats:%d", cinemaSchedulesID)
-	var cached []models.CinemaSeat
+	var cached models.SeatLayout
 
 	if h.rdb != nil {
 		err := utils.GetCache(ctx, h.rdb, redisKey, &cached)
 		if err != nil {
 			log.Println("Redis error, back to DB : ", err)
 		}
-		if len(cached) != 0 {
+		if cached.Auditorium.ID != 0 {
+			h.applySeatHolds(ctx, &cached)
 			ctx.JSON(http.StatusOK, gin.H{
 				"success": true,
 				"data":    cached,
@@ -207,8 +228,15 @@ func (h *CinemaHandler) GetAvailableSeats(ctx *gin.Context) {
 		}
 	}
 
-	seats, err := h.repo.GetAvailableSeats(ctx, cinemaSchedulesID)
+	layout, err := h.repo.GetSeatLayout(ctx, cinemaSchedulesID)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Auditorium for this schedule not found",
+			})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   err.Error(),
@@ -216,24 +244,18 @@ func (h *CinemaHandler) GetAvailableSeats(ctx *gin.Context) {
 		return
 	}
 
-	if len(seats) == 0 {
-		ctx.JSON(http.StatusOK, gin.H{
-			"success": true,
-			"message": "All seats are available",
-		})
-		return
-	}
-
 	if h.rdb != nil {
-		err := utils.SetCache(ctx, h.rdb, redisKey, seats, 2*time.Minute)
+		err := utils.SetCache(ctx, h.rdb, redisKey, layout, 2*time.Minute)
 		if err != nil {
 			log.Println("Redis set cache error:", err)
 		}
 	}
 
+	h.applySeatHolds(ctx, layout)
 	ctx.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    seats,
+		"data":    layout,
+		"message": "data from database",
 	})
 }
 
@@ -266,6 +288,17 @@ func (h *CinemaHandler) GetScheduleFilter(ctx *gin.Context) {
 	location := ctx.Query("location")
 	dateStr := ctx.Query("date")
 	timeStr := ctx.Query("time")
+	if timeStr != "" {
+		showTime, err := utils.ParseShowTime(timeStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		timeStr = showTime
+	}
 
 	page, err := strconv.Atoi(ctx.Query("page"))
 	if err != nil || page < 1 {
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type LoyaltyHandler struct {
+	repo   *repositories.LoyaltyRepository
+	policy models.LoyaltyPolicy
+}
+
+func NewLoyaltyHandler(repo *repositories.LoyaltyRepository, policy models.LoyaltyPolicy) *LoyaltyHandler {
+	return &LoyaltyHandler{
+		repo:   repo,
+		policy: policy,
+	}
+}
+
+// GetPoints godoc
+// @Summary      Loyalty points
+// @Description  Points balance, membership tier and how much points are worth at checkout
+// @Tags         Profile
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  models.SuccessResponse{data=models.LoyaltySummary}
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /profile/points [get]
+func (h *LoyaltyHandler) GetPoints(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	balance, err := h.repo.GetBalance(ctx, claims.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	tier, nextTier := models.LoyaltyTierFor(balance)
+	summary := models.LoyaltySummary{
+		Balance:          balance,
+		Tier:             tier,
+		NextTier:         nextTier,
+		PointValue:       h.policy.PointValue,
+		MaxRedeemPercent: h.policy.MaxRedeemPercent,
+	}
+	if nextTier != nil {
+		summary.PointsToNextTier = nextTier.MinPoints - balance
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "data from database",
+		"data":    summary,
+	})
+}
+
+// GetPointsHistory godoc
+// @Summary      Loyalty points history
+// @Description  Points earned, redeemed, reversed and given back, newest first
+// @Tags         Profile
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page  query  int  false  "Page"
+// @Success      200  {object}  models.SuccessResponse{data=[]models.LoyaltyTransaction}
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /profile/points/history [get]
+func (h *LoyaltyHandler) GetPointsHistory(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit := 20
+
+	transactions, totalCount, err := h.repo.GetTransactions(ctx, claims.UserID, limit, (page-1)*limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"message":     "data from database",
+		"page":        page,
+		"limit":       limit,
+		"count":       len(transactions),
+		"total":       totalCount,
+		"total_pages": (totalCount + limit - 1) / limit,
+		"data":        transactions,
+	})
+}
@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// a provider account without a verified email is not linked, the email could belong to someone else
+var errEmailNotVerified = errors.New("email not verified by the provider")
+
+type OIDCHandler struct {
+	repo        *repositories.OIDCRepository
+	revocations *repositories.TokenRevocationRepository
+	auth        *AuthHandler
+	clients     map[string]*utils.OIDCClient
+	providers   []models.OIDCProvider
+	stateTTL    time.Duration
+}
+
+func NewOIDCHandler(repo *repositories.OIDCRepository, revocations *repositories.TokenRevocationRepository, auth *AuthHandler, configs []models.OIDCProviderConfig, stateTTL time.Duration) *OIDCHandler {
+	clients := make(map[string]*utils.OIDCClient, len(configs))
+	providers := make([]models.OIDCProvider, 0, len(configs))
+	for _, config := range configs {
+		clients[config.Name] = utils.NewOIDCClient(config)
+		providers = append(providers, models.OIDCProvider{Name: config.Name, DisplayName: config.DisplayName})
+	}
+
+	return &OIDCHandler{
+		repo:        repo,
+		revocations: revocations,
+		auth:        auth,
+		clients:     clients,
+		providers:   providers,
+		stateTTL:    stateTTL,
+	}
+}
+
+// a bcrypt hash of a random password nobody knows, accounts of a provider login have no password until they reset it
+func unusablePassword() (string, error) {
+	random, err := utils.RandomHex(32)
+	if err != nil {
+		return "", err
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(random), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// GetProviders godoc
+// @Summary      List login providers
+// @Description  OpenID Connect providers users can sign in with
+// @Tags         Authentication
+// @Produce      json
+// @Success      200  {object}  models.SuccessResponse{data=[]models.OIDCProvider}
+// @Router       /auth/oidc/providers [get]
+func (h *OIDCHandler) GetProviders(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    h.providers,
+	})
+}
+
+// Authorize godoc
+// @Summary      Start a provider login
+// @Description  Returns the login page url of the provider, with a state and a PKCE challenge.
+// @Description  The provider redirects back to the frontend with code and state, to send to /auth/oidc/{provider}/callback.
+// @Tags         Authentication
+// @Produce      json
+// @Param        provider path string true "Provider name"
+// @Success      200  {object}  models.SuccessResponse{data=models.OIDCAuthorization}
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      502  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /auth/oidc/{provider}/authorize [get]
+func (h *OIDCHandler) Authorize(ctx *gin.Context) {
+	provider := ctx.Param("provider")
+	client, ok := h.clients[provider]
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Login provider not found",
+		})
+		return
+	}
+
+	state, err := utils.RandomHex(32)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to generate state",
+		})
+		return
+	}
+	nonce, err := utils.RandomHex(16)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to generate state",
+		})
+		return
+	}
+
+	loginState := models.OIDCLoginState{
+		Provider:     provider,
+		CodeVerifier: utils.GenerateOIDCVerifier(),
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(h.stateTTL),
+	}
+	url, err := client.AuthCodeURL(state, loginState.Nonce, loginState.CodeVerifier)
+	if err != nil {
+		log.Println("OIDC authorize error:", err)
+		ctx.JSON(http.StatusBadGateway, gin.H{
+			"success": false,
+			"error":   "Login provider is unavailable, please try again later",
+		})
+		return
+	}
+
+	if err := h.repo.CreateLoginState(ctx, utils.HashToken(state), loginState); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": models.OIDCAuthorization{
+			AuthorizationURL: url,
+			State:            state,
+			ExpiresIn:        int(h.stateTTL.Seconds()),
+		},
+	})
+}
+
+// Callback godoc
+// @Summary      Finish a provider login
+// @Description  Exchange the code of the provider and log in. The provider account is linked to the user with the same email
+// @Description  when the provider verified it, or a new user is created. Accounts with two-factor authentication get a challenge_token
+// @Description  to finish at /auth/login/2fa.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param        provider path string true "Provider name"
+// @Param        callback body models.OIDCCallbackRequest true "Code and state of the provider redirect"
+// @Success      200  {object}  LoginResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse   "Email not verified by the provider or account suspended"
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /auth/oidc/{provider}/callback [post]
+func (h *OIDCHandler) Callback(ctx *gin.Context) {
+	provider := ctx.Param("provider")
+	client, ok := h.clients[provider]
+	if !ok {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Login provider not found",
+		})
+		return
+	}
+
+	var req models.OIDCCallbackRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	state, err := h.repo.ConsumeLoginState(ctx, utils.HashToken(req.State))
+	if err != nil && !errors.Is(err, repositories.ErrOIDCStateInvalid) {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err != nil || state.Provider != provider || time.Now().After(state.ExpiresAt) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid or expired login, please try again",
+		})
+		return
+	}
+
+	identity, err := client.Exchange(ctx, req.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		log.Println("OIDC exchange error:", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Login with the provider failed, please try again",
+		})
+		return
+	}
+
+	user, err := h.repo.GetUserByIdentity(ctx, identity.Provider, identity.Subject)
+	if errors.Is(err, repositories.ErrIdentityNotFound) {
+		user, err = h.linkIdentity(ctx, identity)
+	}
+	if err != nil {
+		if errors.Is(err, errEmailNotVerified) {
+			ctx.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "The provider did not verify the email of your account",
+			})
+			return
+		}
+		if errors.Is(err, repositories.ErrIdentityAlreadyLinked) {
+			ctx.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   "This account is already linked, please try again",
+			})
+			return
+		}
+		log.Println("OIDC login error:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Login with the provider failed",
+		})
+		return
+	}
+
+	if user.SuspendedAt != nil {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Your account has been suspended",
+		})
+		return
+	}
+
+	h.auth.completeLogin(ctx, user)
+}
+
+// link a new provider account to the user with its verified email, or create the user
+func (h *OIDCHandler) linkIdentity(ctx *gin.Context, identity *models.OIDCIdentity) (*models.User, error) {
+	if identity.Email == "" || !identity.EmailVerified || len(identity.Email) > 100 {
+		return nil, errEmailNotVerified
+	}
+
+	password, err := unusablePassword()
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := h.repo.GetUserByEmail(ctx, identity.Email)
+	if err == nil {
+		if err := h.repo.LinkIdentity(ctx, user, identity, password); err != nil {
+			return nil, err
+		}
+		// the password of an unverified account was replaced, its sessions are ended too
+		if user.EmailVerifiedAt == nil {
+			if err := h.revocations.RevokeAllUserTokens(ctx, user.ID, models.SessionRevokedIdentityLinked); err != nil {
+				log.Println("Revoke user tokens error:", err)
+			}
+			now := time.Now()
+			user.EmailVerifiedAt = &now
+			user.Password = password
+		}
+		return user, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	va, err := utils.GenerateVirtualAccount()
+	if err != nil {
+		return nil, err
+	}
+	user = &models.User{
+		Email:          identity.Email,
+		Password:       password,
+		Role:           models.RoleUser,
+		VirtualAccount: va,
+	}
+	if err := h.repo.CreateUserWithIdentity(ctx, user, identity); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -1,31 +1,160 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"github.com/redis/go-redis/v9"
 )
 
 type OrdersHandler struct {
-	repo *repositories.OrdersRepository
-	rdb  *redis.Client
+	repo     *repositories.OrdersRepository
+	holdRepo *repositories.SeatHoldRepository
+	gateway  *utils.PaymentGateway
+	rdb      *redis.Client
+	holdTTL  time.Duration
+	refunds  models.RefundPolicy
+	tickets  *utils.TicketSigner
+	loyalty  models.LoyaltyPolicy
+	points   *repositories.LoyaltyRepository
+	promos   *repositories.PromoRepository
 }
 
-func NewOrdersHandler(repo *repositories.OrdersRepository, rdb *redis.Client) *OrdersHandler {
+func NewOrdersHandler(repo *repositories.OrdersRepository, holdRepo *repositories.SeatHoldRepository, gateway *utils.PaymentGateway, rdb *redis.Client, holdTTL time.Duration, refunds models.RefundPolicy, tickets *utils.TicketSigner, loyalty models.LoyaltyPolicy, points *repositories.LoyaltyRepository, promos *repositories.PromoRepository) *OrdersHandler {
 	return &OrdersHandler{
-		repo: repo,
-		rdb:  rdb,
+		repo:     repo,
+		holdRepo: holdRepo,
+		gateway:  gateway,
+		rdb:      rdb,
+		holdTTL:  holdTTL,
+		refunds:  refunds,
+		tickets:  tickets,
+		loyalty:  loyalty,
+		points:   points,
+		promos:   promos,
 	}
 }
 
+// helper, the seat ids of the order, duplicates are refused
+func orderSeatIDs(ctx *gin.Context, seats []models.OrderSeatInput) ([]int, bool) {
+	seatIDs := make([]int, len(seats))
+	for i, seat := range seats {
+		if slices.Contains(seatIDs[:i], seat.SeatID) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Duplicate seats in order",
+			})
+			return nil, false
+		}
+		seatIDs[i] = seat.SeatID
+	}
+	return seatIDs, true
+}
+
+// helper, prices the seats and applies the promo code and the redeemed points. The usage limits of the promo code
+// are checked here for the quote, CreateOrder counts the redemption again under a lock
+func (h *OrdersHandler) priceOrder(ctx *gin.Context, userID, cinemasScheduleID, paymentMethodID int, seatIDs []int, redeemPoints int, promoCode string) (*models.PriceBreakdown, *models.PromoCode, bool) {
+	pricing, err := h.repo.GetPricingInput(ctx, cinemasScheduleID, paymentMethodID, seatIDs)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Cinema schedule not found",
+			})
+			return nil, nil, false
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return nil, nil, false
+	}
+
+	breakdown := utils.CalculateOrderPrice(pricing)
+
+	var promo *models.PromoCode
+	if promoCode = strings.TrimSpace(promoCode); promoCode != "" {
+		promo, err = h.promos.GetPromoCodeByCode(ctx, promoCode)
+		if err != nil {
+			if errors.Is(err, repositories.ErrPromoCodeNotFound) {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"success": false,
+					"error":   "Promo code not found",
+				})
+				return nil, nil, false
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return nil, nil, false
+		}
+
+		if err := utils.ApplyPromoCode(&breakdown, promo, pricing, paymentMethodID, time.Now()); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return nil, nil, false
+		}
+
+		if promo.UsageLimit != nil && promo.UsedCount >= *promo.UsageLimit {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   repositories.ErrPromoCodeUsedUp.Error(),
+			})
+			return nil, nil, false
+		}
+		if promo.PerUserLimit != nil {
+			used, err := h.promos.CountUserRedemptions(ctx, promo.ID, userID)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, gin.H{
+					"success": false,
+					"error":   err.Error(),
+				})
+				return nil, nil, false
+			}
+			if used >= *promo.PerUserLimit {
+				ctx.JSON(http.StatusBadRequest, gin.H{
+					"success": false,
+					"error":   repositories.ErrPromoCodeUserLimitHit.Error(),
+				})
+				return nil, nil, false
+			}
+		}
+	}
+
+	// the points balance is checked by the caller
+	if err := utils.ApplyPointsRedemption(&breakdown, redeemPoints, h.loyalty); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return nil, nil, false
+	}
+
+	return &breakdown, promo, true
+}
+
 // CreateOrder godoc
 // @Summary Create Order
-// @Description  Create a new order
+// @Description  Create a new order, the price is calculated by the server from the cinema price,
+// @Description  seat type surcharges, showtime rules and payment fee. total_prices is optional and must match when sent.
+// @Description  redeem_points pays up to LOYALTY_MAX_REDEEM_PERCENT of the tickets with loyalty points, they are given back when the order is not paid.
+// @Description  promo_code discounts the tickets before the points, the redemption is counted when the order is created and released when it is not paid.
+// @Description  The order starts as pending, the returned payment session is completed through the payment provider.
 // @Tags Orders
 // @Security     BearerAuth
 // @Accept       json
@@ -37,6 +166,7 @@ func NewOrdersHandler(repo *repositories.OrdersRepository, rdb *redis.Client) *O
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
 // @Router /orders [post]
 func (h *OrdersHandler) CreateOrder(ctx *gin.Context) {
 	rawClaims, _ := ctx.Get("claims")
@@ -51,42 +181,141 @@ func (h *OrdersHandler) CreateOrder(ctx *gin.Context) {
 		return
 	}
 
-	QRCode := utils.GenerateQRCode()
-
 	order := models.Order{
-		QRCode:            QRCode,
-		IsPaid:            req.IsPaid,
 		IsActive:          req.IsActive,
-		TotalPrices:       req.TotalPrices,
 		UserID:            claims.UserID,
 		CinemasScheduleID: req.CinemasScheduleID,
 		PaymentMethodID:   req.PaymentMethodID,
 		OrderSeats:        req.OrderSeats,
 	}
 
-	seatIDs := make([]int, len(order.OrderSeats))
-	for i, seat := range order.OrderSeats {
-		seatIDs[i] = seat.SeatID
+	seatIDs, ok := orderSeatIDs(ctx, order.OrderSeats)
+	if !ok {
+		return
 	}
 
-	available, err := h.repo.AreSeatsAvailable(ctx, order.CinemasScheduleID, seatIDs)
+	paymentMethod, err := h.repo.GetPaymentMethod(ctx, order.PaymentMethodID)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Payment method not found",
+			})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   err.Error(),
 		})
 		return
 	}
-	if !available {
+
+	provider, err := h.gateway.Provider(paymentMethod.Provider)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	inSchedule, err := h.repo.AreSeatsInSchedule(ctx, order.CinemasScheduleID, seatIDs)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if !inSchedule {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "One or more seats do not belong to this schedule",
+		})
+		return
+	}
+
+	held, err := h.holdRepo.GetScheduleHolds(ctx, order.CinemasScheduleID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	heldByOthers := []int{}
+	for _, seatID := range seatIDs {
+		if holder, ok := held[seatID]; ok && holder != claims.UserID {
+			heldByOthers = append(heldByOthers, seatID)
+		}
+	}
+	if len(heldByOthers) > 0 {
 		ctx.JSON(http.StatusConflict, gin.H{
+			"success":  false,
+			"error":    "One or more seats are held by another user",
+			"seat_ids": heldByOthers,
+		})
+		return
+	}
+
+	breakdown, promo, ok := h.priceOrder(ctx, claims.UserID, order.CinemasScheduleID, order.PaymentMethodID, seatIDs, req.RedeemPoints, req.PromoCode)
+	if !ok {
+		return
+	}
+	if promo != nil {
+		order.PromoCodeID = &promo.ID
+	}
+	order.PointsRedeemed = breakdown.PointsRedeemed
+
+	// client total is optional, when sent it must match the server price
+	if req.TotalPrices != 0 && math.Abs(req.TotalPrices-breakdown.Total) >= 0.01 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success":         false,
+			"error":           "total_prices does not match the order price",
+			"total_prices":    breakdown.Total,
+			"price_breakdown": breakdown,
+		})
+		return
+	}
+	order.TotalPrices = breakdown.Total
+	order.PriceBreakdown = breakdown
+
+	order.ID, err = h.repo.NextOrderID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"error":   "One or more seats are already booked",
+			"error":   err.Error(),
 		})
 		return
 	}
+	order.QRCode = h.tickets.Generate(order.ID, order.CinemasScheduleID, seatIDs)
 
 	orderID, err := h.repo.CreateOrder(ctx, &order)
 	if err != nil {
+		var conflict *repositories.SeatConflictError
+		if errors.As(err, &conflict) {
+			ctx.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   "One or more seats are already booked",
+				"seats":   conflict.Seats,
+			})
+			return
+		}
+		if errors.Is(err, repositories.ErrInsufficientPoints) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Not enough loyalty points",
+			})
+			return
+		}
+		// another order took the last redemption in the meantime
+		if errors.Is(err, repositories.ErrPromoCodeUsedUp) || errors.Is(err, repositories.ErrPromoCodeUserLimitHit) {
+			ctx.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   err.Error(),
@@ -94,13 +323,136 @@ func (h *OrdersHandler) CreateOrder(ctx *gin.Context) {
 		return
 	}
 
+	order.ID = orderID
+
+	if err := h.holdRepo.ReleaseSeats(ctx, order.CinemasScheduleID, claims.UserID, nil); err != nil {
+		log.Println("Release seat holds error:", err)
+	}
+
+	session, err := provider.CreateCharge(ctx, models.PaymentCharge{
+		OrderID:     order.ID,
+		Amount:      order.TotalPrices,
+		MethodName:  paymentMethod.Name,
+		Description: fmt.Sprintf("Tickitz order #%d", order.ID),
+	})
+	if err == nil {
+		err = h.repo.SetPaymentReference(ctx, order.ID, session.Reference)
+	}
+	if err != nil {
+		log.Println("Create payment error:", err)
+		// the order can not be paid, release the seats again
+		if err := h.repo.UpdatePaymentStatus(ctx, order.ID, models.PaymentStatusFailed); err != nil {
+			log.Println("Update payment status error:", err)
+		}
+		if err := utils.InvalidateCache(ctx, h.rdb, []string{"cinemas:", "users:"}); err != nil {
+			log.Println("Redis delete cache error:", err)
+		}
+		ctx.JSON(http.StatusBadGateway, gin.H{
+			"success": false,
+			"error":   "Failed to create payment",
+		})
+		return
+	}
+	order.PaymentReference = session.Reference
+
 	if err := utils.InvalidateCache(ctx, h.rdb, []string{"cinemas:", "users:"}); err != nil {
 		log.Println("Redis delete cache error:", err)
 	}
-	order.ID = orderID
 	ctx.JSON(http.StatusOK, gin.H{
 		"message": "Order created successfully",
 		"order":   order,
+		"payment": session,
+	})
+}
+
+// QuoteOrder godoc
+// @Summary      Quote order
+// @Description  Preview the price of an order with the promo code and redeemed points applied, nothing is booked or redeemed
+// @Tags         Orders
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        quote body models.OrderQuoteRequest true "Order to price"
+// @Success      200  {object} models.SuccessResponse{data=models.PriceBreakdown}
+// @Failure      400  {object} models.ErrorResponse
+// @Failure      401  {object} models.ErrorResponse   "Unauthorized or invalid token"
+// @Failure      500  {object} models.ErrorResponse
+// @Router       /orders/quote [post]
+func (h *OrdersHandler) QuoteOrder(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	var req models.OrderQuoteRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	seatIDs, ok := orderSeatIDs(ctx, req.OrderSeats)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repo.GetPaymentMethod(ctx, req.PaymentMethodID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Payment method not found",
+			})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	inSchedule, err := h.repo.AreSeatsInSchedule(ctx, req.CinemasScheduleID, seatIDs)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if !inSchedule {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "One or more seats do not belong to this schedule",
+		})
+		return
+	}
+
+	breakdown, _, ok := h.priceOrder(ctx, claims.UserID, req.CinemasScheduleID, req.PaymentMethodID, seatIDs, req.RedeemPoints, req.PromoCode)
+	if !ok {
+		return
+	}
+
+	if breakdown.PointsRedeemed > 0 {
+		balance, err := h.points.GetBalance(ctx, claims.UserID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		if balance < breakdown.PointsRedeemed {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Not enough loyalty points",
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    breakdown,
 	})
 }
 
@@ -146,3 +498,565 @@ func (h *OrdersHandler) GetOrdersHistory(ctx *gin.Context) {
 		"data":    orderHistory,
 	})
 }
+
+// load an order of the logged-in user, responds with 404 for orders of other users
+func (h *OrdersHandler) getUserOrder(ctx *gin.Context) (*models.OrderDetail, bool) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	orderID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || orderID <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid order id",
+		})
+		return nil, false
+	}
+
+	order, err := h.repo.GetOrderDetail(ctx, orderID)
+	if err != nil && !errors.Is(err, repositories.ErrOrderNotFound) {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return nil, false
+	}
+	if err != nil || order.UserID != claims.UserID {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Order not found",
+		})
+		return nil, false
+	}
+
+	return order, true
+}
+
+// GetOrderDetail godoc
+// @Summary      Get order detail
+// @Description  Get one order of the logged-in user with the movie, cinema, showtime, every seat, payment status and price breakdown
+// @Tags         Orders
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  int  true  "Order ID"
+// @Success      200  {object} models.SuccessResponse{data=models.OrderDetail}
+// @Failure      400  {object} models.ErrorResponse
+// @Failure      401  {object} models.ErrorResponse   "Unauthorized or invalid token"
+// @Failure      404  {object} models.ErrorResponse
+// @Failure      500  {object} models.ErrorResponse
+// @Router       /orders/{id} [get]
+func (h *OrdersHandler) GetOrderDetail(ctx *gin.Context) {
+	order, ok := h.getUserOrder(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "data from database",
+		"data":    order,
+	})
+}
+
+// GetOrderTicketPDF godoc
+// @Summary      Download e-ticket
+// @Description  Download a printable PDF e-ticket with the QR code of a paid order of the logged-in user
+// @Tags         Orders
+// @Security     BearerAuth
+// @Produce      application/pdf
+// @Param        id   path  int  true  "Order ID"
+// @Success      200
+// @Failure      400  {object} models.ErrorResponse
+// @Failure      401  {object} models.ErrorResponse   "Unauthorized or invalid token"
+// @Failure      404  {object} models.ErrorResponse
+// @Failure      409  {object} models.ErrorResponse
+// @Failure      500  {object} models.ErrorResponse
+// @Router       /orders/{id}/ticket.pdf [get]
+func (h *OrdersHandler) GetOrderTicketPDF(ctx *gin.Context) {
+	order, ok := h.getUserOrder(ctx)
+	if !ok {
+		return
+	}
+
+	if order.PaymentStatus != models.PaymentStatusPaid || order.QRCode == nil {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "Ticket is available once the order is paid",
+		})
+		return
+	}
+
+	ticket, err := utils.RenderETicketPDF(order)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tickitz-ticket-%d.pdf"`, order.ID))
+	ctx.Data(http.StatusOK, "application/pdf", ticket)
+}
+
+// GetOrderQRCode godoc
+// @Summary      Get ticket QR code
+// @Description  Render the ticket code of a paid order of the logged-in user as a QR image
+// @Tags         Orders
+// @Security     BearerAuth
+// @Produce      png
+// @Produce      image/svg+xml
+// @Param        id      path   int     true   "Order ID"
+// @Param        format  query  string  false  "png (default) or svg"
+// @Param        size    query  int     false  "Image width in pixels, 64 - 1024 (default 256)"
+// @Success      200
+// @Failure      400  {object} models.ErrorResponse
+// @Failure      401  {object} models.ErrorResponse   "Unauthorized or invalid token"
+// @Failure      404  {object} models.ErrorResponse
+// @Failure      409  {object} models.ErrorResponse
+// @Failure      500  {object} models.ErrorResponse
+// @Router       /orders/{id}/qr [get]
+func (h *OrdersHandler) GetOrderQRCode(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	orderID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || orderID <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid order id",
+		})
+		return
+	}
+
+	size, err := strconv.Atoi(ctx.DefaultQuery("size", "256"))
+	if err != nil || size < 64 || size > 1024 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "size must be between 64 and 1024",
+		})
+		return
+	}
+
+	format := ctx.DefaultQuery("format", "png")
+	if format != "png" && format != "svg" {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "format must be png or svg",
+		})
+		return
+	}
+
+	ticket, err := h.repo.GetOrderTicket(ctx, orderID)
+	if err != nil && !errors.Is(err, repositories.ErrOrderNotFound) {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err != nil || ticket.UserID != claims.UserID || ticket.QRCode == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Ticket not found",
+		})
+		return
+	}
+
+	if ticket.PaymentStatus != models.PaymentStatusPaid {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "Ticket is available once the order is paid",
+		})
+		return
+	}
+
+	if format == "svg" {
+		image, err := utils.RenderQRCodeSVG(*ticket.QRCode, size)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		ctx.Data(http.StatusOK, "image/svg+xml", image)
+		return
+	}
+
+	image, err := utils.RenderQRCodePNG(*ticket.QRCode, size)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	ctx.Data(http.StatusOK, "image/png", image)
+}
+
+// HoldSeats godoc
+// @Summary      Hold seats
+// @Description  Temporarily lock the selected seats of a schedule for the logged-in user during checkout.
+// @Description  Holding again replaces the previous selection of the user on the same schedule.
+// @Tags         Orders
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        hold body models.SeatHoldRequest true "Seats to hold"
+// @Success      200  {object} models.SuccessResponse{data=models.SeatHold}
+// @Failure      400  {object} models.ErrorResponse
+// @Failure      401  {object} models.ErrorResponse   "Unauthorized or invalid token"
+// @Failure      409  {object} models.ErrorResponse
+// @Failure      500  {object} models.ErrorResponse
+// @Router       /orders/holds [post]
+func (h *OrdersHandler) HoldSeats(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	var req models.SeatHoldRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	inSchedule, err := h.repo.AreSeatsInSchedule(ctx, req.CinemasScheduleID, req.SeatIDs)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if !inSchedule {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "One or more seats do not belong to this schedule",
+		})
+		return
+	}
+
+	available, err := h.repo.AreSeatsAvailable(ctx, req.CinemasScheduleID, req.SeatIDs)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if !available {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "One or more seats are already booked",
+		})
+		return
+	}
+
+	conflicts, err := h.holdRepo.HoldSeats(ctx, req.CinemasScheduleID, claims.UserID, req.SeatIDs, h.holdTTL)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if len(conflicts) > 0 {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"success":  false,
+			"error":    "One or more seats are held by another user",
+			"seat_ids": conflicts,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Seats held successfully",
+		"data": models.SeatHold{
+			CinemasScheduleID: req.CinemasScheduleID,
+			SeatIDs:           req.SeatIDs,
+			ExpiresAt:         time.Now().Add(h.holdTTL),
+		},
+	})
+}
+
+// ReleaseSeats godoc
+// @Summary      Release held seats
+// @Description  Cancel every seat hold of the logged-in user on a schedule
+// @Tags         Orders
+// @Security     BearerAuth
+// @Produce      json
+// @Param        cinemas_schedule_id path int true "Cinema Schedule ID"
+// @Success      200  {object} models.SuccessResponse
+// @Failure      400  {object} models.ErrorResponse
+// @Failure      401  {object} models.ErrorResponse   "Unauthorized or invalid token"
+// @Failure      500  {object} models.ErrorResponse
+// @Router       /orders/holds/{cinemas_schedule_id} [delete]
+func (h *OrdersHandler) ReleaseSeats(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	cinemaScheduleID, err := strconv.Atoi(ctx.Param("cinemas_schedule_id"))
+	if err != nil || cinemaScheduleID <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid cinemas_schedule_id",
+		})
+		return
+	}
+
+	if err := h.holdRepo.ReleaseSeats(ctx, cinemaScheduleID, claims.UserID, nil); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Seat holds released",
+	})
+}
+
+// CancelOrder godoc
+// @Summary      Cancel order
+// @Description  Cancel an order of the logged-in user and release the seats. Cancellation closes CANCEL_CUTOFF_MINUTES before the showtime.
+// @Description  A paid order is fully refunded until FULL_REFUND_MINUTES before the showtime, partially refunded after that, its earned loyalty points are reversed and redeemed points given back.
+// @Tags         Orders
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id   path  int  true  "Order ID"
+// @Success      200  {object} models.SuccessResponse{data=models.OrderCancellation}
+// @Failure      400  {object} models.ErrorResponse
+// @Failure      401  {object} models.ErrorResponse   "Unauthorized or invalid token"
+// @Failure      404  {object} models.ErrorResponse
+// @Failure      409  {object} models.ErrorResponse
+// @Failure      500  {object} models.ErrorResponse
+// @Failure      502  {object} models.ErrorResponse
+// @Router       /orders/{id}/cancel [post]
+func (h *OrdersHandler) CancelOrder(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	orderID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || orderID <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid order id",
+		})
+		return
+	}
+
+	order, err := h.repo.GetCancellableOrder(ctx, orderID)
+	if err != nil && !errors.Is(err, repositories.ErrOrderNotFound) {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err != nil || order.UserID != claims.UserID {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Order not found",
+		})
+		return
+	}
+
+	var refundAmount float64
+	if order.PaymentStatus == models.PaymentStatusPaid {
+		refundAmount, err = utils.CalculateRefund(h.refunds, order.TotalPrices, order.UntilShowtime)
+	} else if order.UntilShowtime < time.Duration(h.refunds.CutoffMinutes)*time.Minute {
+		err = utils.ErrCancellationClosed
+	}
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	h.cancelOrder(ctx, order, refundAmount, models.CancelReasonUserCancelled)
+}
+
+// AdminCancelOrder godoc
+// @Summary      Cancel any order
+// @Description  Cancel any order regardless of the showtime, a paid order is refunded with refund_percent (default 100)
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id      path  int  true  "Order ID"
+// @Param        cancel  body  models.AdminCancelOrderRequest false "Refund and reason"
+// @Success      200  {object} models.SuccessResponse{data=models.OrderCancellation}
+// @Failure      400  {object} models.ErrorResponse
+// @Failure      401  {object} models.ErrorResponse   "Unauthorized or invalid token"
+// @Failure      403  {object} models.ErrorResponse   "Forbidden (not admin)"
+// @Failure      404  {object} models.ErrorResponse
+// @Failure      409  {object} models.ErrorResponse
+// @Failure      500  {object} models.ErrorResponse
+// @Failure      502  {object} models.ErrorResponse
+// @Router       /admin/orders/{id}/cancel [post]
+func (h *OrdersHandler) AdminCancelOrder(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || orderID <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid order id",
+		})
+		return
+	}
+
+	var req models.AdminCancelOrderRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	order, err := h.repo.GetCancellableOrder(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrOrderNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "Order not found",
+			})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	// a cinema manager only cancels the orders of their cinema
+	if !utils.CinemaInScope(ctx, order.CinemaID) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Order not found",
+		})
+		return
+	}
+
+	refundPercent := 100.0
+	if req.RefundPercent != nil {
+		refundPercent = *req.RefundPercent
+	}
+	reason := models.CancelReasonAdminCancelled
+	if req.Reason != "" {
+		reason = req.Reason
+	}
+
+	var refundAmount float64
+	if order.PaymentStatus == models.PaymentStatusPaid {
+		refundAmount = math.Round(order.TotalPrices*refundPercent) / 100
+	}
+
+	h.cancelOrder(ctx, order, refundAmount, reason)
+}
+
+// refund paid orders through the provider, then cancel the order in the database.
+// A paid order is moved to refunding first so concurrent cancellations refund it only once, a refunding order
+// left by a failed attempt is retried with the same amount and idempotency key
+func (h *OrdersHandler) cancelOrder(ctx *gin.Context, order *models.CancellableOrder, refundAmount float64, reason string) {
+	var status string
+	switch order.PaymentStatus {
+	case models.PaymentStatusPending:
+		status = models.PaymentStatusCancelled
+		refundAmount = 0
+	case models.PaymentStatusPaid, models.PaymentStatusRefunding:
+		status = models.PaymentStatusRefunded
+	default:
+		ctx.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "Order with payment status " + order.PaymentStatus + " can not be cancelled",
+		})
+		return
+	}
+
+	refundSent := false
+	if status == models.PaymentStatusRefunded {
+		refund, err := h.repo.StartRefund(ctx, order.ID, refundAmount, reason)
+		if err != nil {
+			if errors.Is(err, repositories.ErrInvalidPaymentTransition) {
+				ctx.JSON(http.StatusConflict, gin.H{
+					"success": false,
+					"error":   "Order can not be cancelled",
+				})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		refundAmount, reason, refundSent = refund.Amount, refund.Reason, refund.Sent
+	}
+
+	if status == models.PaymentStatusRefunded && !refundSent && refundAmount > 0 && order.PaymentReference != nil {
+		provider, err := h.gateway.Provider(order.Provider)
+		if err == nil {
+			err = provider.Refund(ctx, *order.PaymentReference, refundAmount, fmt.Sprintf("refund-order-%d", order.ID))
+		}
+		if err != nil {
+			log.Println("Refund payment error:", err)
+			if err := h.repo.AbortRefund(ctx, order.ID); err != nil {
+				log.Println("Abort refund error:", err)
+			}
+			ctx.JSON(http.StatusBadGateway, gin.H{
+				"success": false,
+				"error":   "Failed to refund payment",
+			})
+			return
+		}
+		// the refund worker finishes the order when it can not be cancelled below
+		refundSent = true
+		if err := h.repo.MarkRefundSent(ctx, order.ID); err != nil {
+			log.Println("Mark refund sent error:", err)
+		}
+	}
+
+	result, err := h.repo.CancelOrder(ctx, order.ID, status, reason, refundAmount)
+	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidPaymentTransition) {
+			ctx.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   "Order can not be cancelled",
+			})
+			return
+		}
+		log.Println("Cancel order error:", err)
+		if refundSent {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "Payment refunded, the order is cancelled shortly",
+			})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if err := utils.InvalidateCache(ctx, h.rdb, []string{"cinemas:", "users:"}); err != nil {
+		log.Println("Redis delete cache error:", err)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Order cancelled successfully",
+		"data":    result,
+	})
+}
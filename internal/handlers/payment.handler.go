@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+type PaymentHandler struct {
+	repo    *repositories.OrdersRepository
+	gateway *utils.PaymentGateway
+	fake    *utils.FakePaymentProvider
+	rdb     *redis.Client
+}
+
+func NewPaymentHandler(repo *repositories.OrdersRepository, gateway *utils.PaymentGateway, fake *utils.FakePaymentProvider, rdb *redis.Client) *PaymentHandler {
+	return &PaymentHandler{
+		repo:    repo,
+		gateway: gateway,
+		fake:    fake,
+		rdb:     rdb,
+	}
+}
+
+// PaymentWebhook godoc
+// @Summary      Payment provider webhook
+// @Description  Callback of the payment provider, the raw body is signed with HMAC-SHA256 in the X-Signature header.
+// @Description  Midtrans notifications are sent to /payments/webhook/midtrans and carry their own signature_key instead.
+// @Description  Events that do not change the order, like a pending payment, are answered with 200.
+// @Description  A repeated event id is accepted without changing the order again.
+// @Tags         Payments
+// @Accept       json
+// @Produce      json
+// @Param        provider   path   string  true  "Payment provider"
+// @Param        X-Signature header string true "HMAC-SHA256 hex signature of the body"
+// @Param        event body models.PaymentEvent true "Payment event"
+// @Success      200  {object} models.SuccessResponse
+// @Failure      400  {object} models.ErrorResponse
+// @Failure      401  {object} models.ErrorResponse
+// @Failure      404  {object} models.ErrorResponse
+// @Failure      409  {object} models.ErrorResponse
+// @Failure      500  {object} models.ErrorResponse
+// @Router       /payments/webhook/{provider} [post]
+func (h *PaymentHandler) PaymentWebhook(ctx *gin.Context) {
+	provider, err := h.gateway.Provider(ctx.Param("provider"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	payload, err := ctx.GetRawData()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	event, err := provider.ParseWebhook(payload, ctx.GetHeader("X-Signature"))
+	if err != nil {
+		if errors.Is(err, utils.ErrPaymentEventIgnored) {
+			ctx.JSON(http.StatusOK, gin.H{
+				"success": true,
+				"message": "Payment event ignored",
+			})
+			return
+		}
+		if errors.Is(err, utils.ErrInvalidPaymentSignature) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	h.applyPaymentEvent(ctx, provider.Name(), event, payload)
+}
+
+// SimulatePayment godoc
+// @Summary      Simulate a payment
+// @Description  Complete a payment of the fake provider, only available when PAYMENT_SIMULATOR=true.
+// @Description  The event goes through the same signed webhook processing as a real provider.
+// @Tags         Payments
+// @Accept       json
+// @Produce      json
+// @Param        reference  path  string  true  "Payment reference"
+// @Param        simulation body models.PaymentSimulation true "Payment result"
+// @Success      200  {object} models.SuccessResponse
+// @Failure      400  {object} models.ErrorResponse
+// @Failure      404  {object} models.ErrorResponse
+// @Failure      409  {object} models.ErrorResponse
+// @Failure      500  {object} models.ErrorResponse
+// @Router       /payments/fake/{reference} [post]
+func (h *PaymentHandler) SimulatePayment(ctx *gin.Context) {
+	var req models.PaymentSimulation
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	eventID, err := utils.RandomHex(8)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	event := models.PaymentEvent{
+		ID:        "FAKE-EVT-" + eventID,
+		Reference: ctx.Param("reference"),
+		Status:    req.Status,
+	}
+	// a paid event carries the charged amount like a real provider does
+	if event.Status == models.PaymentStatusPaid {
+		amount, err := h.repo.GetPaymentAmount(ctx, event.Reference)
+		if errors.Is(err, repositories.ErrOrderNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		event.Amount = amount
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	parsed, err := h.fake.ParseWebhook(payload, h.fake.Sign(payload))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	h.applyPaymentEvent(ctx, h.fake.Name(), parsed, payload)
+}
+
+func (h *PaymentHandler) applyPaymentEvent(ctx *gin.Context, provider string, event *models.PaymentEvent, payload []byte) {
+	orderID, applied, err := h.repo.ApplyPaymentEvent(ctx, provider, event, payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrOrderNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		case errors.Is(err, repositories.ErrInvalidPaymentTransition):
+			ctx.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		case errors.Is(err, repositories.ErrPaymentAmountMismatch):
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+		return
+	}
+
+	if !applied {
+		ctx.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "Payment event already processed",
+		})
+		return
+	}
+
+	if err := utils.InvalidateCache(ctx, h.rdb, []string{"cinemas:", "users:"}); err != nil {
+		log.Println("Redis delete cache error:", err)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Payment event processed",
+		"data": gin.H{
+			"order_id":       orderID,
+			"payment_status": event.Status,
+		},
+	})
+}
@@ -1,28 +1,41 @@
 package handlers
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"github.com/redis/go-redis/v9"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type ProfileHandler struct {
-	repo *repositories.ProfileRepository
-	rdb  *redis.Client
+	repo        *repositories.ProfileRepository
+	ordersRepo  *repositories.OrdersRepository
+	sessionRepo *repositories.SessionRepository
+	revocations *repositories.TokenRevocationRepository
+	rdb         *redis.Client
 }
 
-func NewProfileHandler(repo *repositories.ProfileRepository, rdb *redis.Client) *ProfileHandler {
+func NewProfileHandler(repo *repositories.ProfileRepository, ordersRepo *repositories.OrdersRepository, sessionRepo *repositories.SessionRepository, revocations *repositories.TokenRevocationRepository, rdb *redis.Client) *ProfileHandler {
 	return &ProfileHandler{
-		repo: repo,
-		rdb:  rdb,
+		repo:        repo,
+		ordersRepo:  ordersRepo,
+		sessionRepo: sessionRepo,
+		revocations: revocations,
+		rdb:         rdb,
 	}
 }
 
@@ -94,7 +107,7 @@ func (h *ProfileHandler) UpdateProfile(ctx *gin.Context) {
 
 // UpdatePassword godoc
 // @Summary      Update user password
-// @Description  Update user password
+// @Description  Update user password, every token and session of the user is revoked and the user has to login again
 // @Tags         Profile
 // @Accept       multipart/form-data
 // @Produce      json
@@ -181,6 +194,13 @@ func (h *ProfileHandler) UpdatePassword(ctx *gin.Context) {
 		return
 	}
 
+	// tokens issued with the old password are not valid anymore
+	if update.User.Password != nil {
+		if err := h.revocations.RevokeAllUserTokens(ctx, userID, models.SessionRevokedPasswordChange); err != nil {
+			log.Println("Revoke user tokens error:", err)
+		}
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"status":  true,
 		"message": "password updated successfully",
@@ -244,3 +264,203 @@ func (h *ProfileHandler) GetProfile(ctx *gin.Context) {
 		"data":    userProfile,
 	})
 }
+
+// ExportData godoc
+// @Summary      Export personal data
+// @Description  Download the account, profile, linked accounts, sessions and orders of the user as a JSON file,
+// @Description  or with format=zip as a ZIP archive that also holds the e-tickets of paid orders and the profile image
+// @Tags         Profile
+// @Security     BearerAuth
+// @Produce      json
+// @Produce      application/zip
+// @Param        format query string false "json (default) or zip"
+// @Success      200 {object} models.AccountExport
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      401 {object} models.ErrorResponse
+// @Failure      500 {object} models.ErrorResponse
+// @Router       /profile/export [get]
+func (h *ProfileHandler) ExportData(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	format := ctx.DefaultQuery("format", "json")
+	if format != "json" && format != "zip" {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "format must be json or zip",
+		})
+		return
+	}
+
+	export, err := h.repo.GetAccountExport(ctx, claims.UserID)
+	if err == nil {
+		export.Sessions, err = h.sessionRepo.GetUserSessions(ctx, claims.UserID)
+	}
+	if err == nil {
+		export.Orders, err = h.ordersRepo.GetUserOrderDetails(ctx, claims.UserID)
+	}
+	if err != nil {
+		log.Println("Export account error:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to export data",
+		})
+		return
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to export data",
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("tickitz-data-%d-%s", claims.UserID, export.ExportedAt.Format("20060102"))
+	if format == "json" {
+		ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+		ctx.Data(http.StatusOK, "application/json", data)
+		return
+	}
+
+	archive, err := buildExportArchive(data, export)
+	if err != nil {
+		log.Println("Build export archive error:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to export data",
+		})
+		return
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, filename))
+	ctx.Data(http.StatusOK, "application/zip", archive)
+}
+
+// zip of data.json, the e-tickets of paid orders and the profile image
+func buildExportArchive(data []byte, export *models.AccountExport) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	addFile := func(name string, content []byte) error {
+		file, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = file.Write(content)
+		return err
+	}
+
+	if err := addFile("data.json", data); err != nil {
+		return nil, err
+	}
+
+	for i := range export.Orders {
+		order := &export.Orders[i]
+		if order.PaymentStatus != models.PaymentStatusPaid || order.QRCode == nil {
+			continue
+		}
+		ticket, err := utils.RenderETicketPDF(order)
+		if err != nil {
+			return nil, err
+		}
+		if err := addFile(fmt.Sprintf("tickets/tickitz-ticket-%d.pdf", order.ID), ticket); err != nil {
+			return nil, err
+		}
+	}
+
+	if export.Profile.ImagePath != nil && *export.Profile.ImagePath != "" {
+		name := filepath.Base(*export.Profile.ImagePath)
+		image, err := os.ReadFile(filepath.Join("public/profile", name))
+		if err == nil {
+			if err := addFile("profile/"+name, image); err != nil {
+				return nil, err
+			}
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DeleteAccount godoc
+// @Summary      Delete account
+// @Description  Delete the account of the user after confirming the password. The personal data is removed and the account anonymized,
+// @Description  orders are kept as financial records without personal data. Every token and session is revoked.
+// @Description  Accounts created with a login provider set a password with /auth/forgot-password first.
+// @Tags         Profile
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        delete body models.DeleteAccountRequest true "Current password"
+// @Success      200 {object} models.SuccessResponse
+// @Failure      400 {object} models.ErrorResponse
+// @Failure      401 {object} models.ErrorResponse
+// @Failure      500 {object} models.ErrorResponse
+// @Router       /profile [delete]
+func (h *ProfileHandler) DeleteAccount(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	var req models.DeleteAccountRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	storedUser, err := h.repo.GetProfile(ctx, claims.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to get user data",
+		})
+		return
+	}
+	if bcrypt.CompareHashAndPassword([]byte(storedUser.Password), []byte(req.Password)) != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "password is incorrect",
+		})
+		return
+	}
+
+	imagePath, err := h.repo.DeleteAccount(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "User not found",
+			})
+			return
+		}
+		log.Println("Delete account error:", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to delete account",
+		})
+		return
+	}
+
+	// the tokens were revoked with the account
+	h.revocations.ForgetUserTokenState(ctx, claims.UserID)
+	if imagePath != nil {
+		if err := utils.RemoveUploadedFile("public/profile", *imagePath); err != nil {
+			log.Println("Remove profile image error:", err)
+		}
+	}
+	if err := utils.InvalidateCache(ctx, h.rdb, []string{"users:"}); err != nil {
+		log.Println("Redis delete cache error:", err)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Account deleted",
+	})
+}
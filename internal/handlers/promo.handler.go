@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type PromoHandler struct {
+	repo *repositories.PromoRepository
+}
+
+func NewPromoHandler(repo *repositories.PromoRepository) *PromoHandler {
+	return &PromoHandler{
+		repo: repo,
+	}
+}
+
+// helper, reads the promo code id of the path
+func promoCodeID(ctx *gin.Context) (int, bool) {
+	promoID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || promoID < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid promo code ID",
+		})
+		return 0, false
+	}
+	return promoID, true
+}
+
+// helper, binds and checks a promo code, the binding tags do not compare fields
+func bindPromoCode(ctx *gin.Context) (*models.PromoCodeRequest, bool) {
+	var req models.PromoCodeRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return nil, false
+	}
+
+	message := ""
+	switch {
+	case req.DiscountType == models.PromoDiscountPercentage && req.DiscountValue > 100:
+		message = "a percentage discount can not be more than 100"
+	case req.DiscountType == models.PromoDiscountFixed && req.MaxDiscount != nil:
+		message = "max_discount is only used by percentage discounts"
+	case req.ValidFrom != nil && req.ValidUntil != nil && !req.ValidFrom.Before(*req.ValidUntil):
+		message = "valid_from must be before valid_until"
+	}
+	if message != "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   message,
+		})
+		return nil, false
+	}
+	return &req, true
+}
+
+// helper
+func promoWriteFailed(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrPromoCodeNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Promo code not found",
+		})
+	case errors.Is(err, repositories.ErrPromoCodeExists), errors.Is(err, repositories.ErrPromoCodeLimitTooLow):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	}
+}
+
+// GetPromoCodes godoc
+// @Summary      List promo codes
+// @Description  List the promo codes with their usage, newest first
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        page    query  int     false  "Page"
+// @Param        search  query  string  false  "Code or description"
+// @Param        status  query  string  false  "active, inactive or expired"
+// @Success      200  {object}  models.SuccessResponse{data=[]models.PromoCode}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse   "Forbidden (no promos:manage permission)"
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/promo-codes [get]
+func (h *PromoHandler) GetPromoCodes(ctx *gin.Context) {
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit := 20
+
+	filter := models.PromoCodeFilter{
+		Search: strings.TrimSpace(ctx.Query("search")),
+		Status: ctx.Query("status"),
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	}
+	if filter.Status != "" && filter.Status != "active" && filter.Status != "inactive" && filter.Status != "expired" {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "status must be active, inactive or expired",
+		})
+		return
+	}
+
+	promos, totalCount, err := h.repo.GetPromoCodes(ctx, filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"message":     "data from database",
+		"page":        page,
+		"limit":       limit,
+		"count":       len(promos),
+		"total":       totalCount,
+		"total_pages": (totalCount + limit - 1) / limit,
+		"data":        promos,
+	})
+}
+
+// GetPromoCode godoc
+// @Summary      Get promo code
+// @Description  Get a promo code with its usage
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path  int  true  "Promo code ID"
+// @Success      200  {object}  models.SuccessResponse{data=models.PromoCode}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/promo-codes/{id} [get]
+func (h *PromoHandler) GetPromoCode(ctx *gin.Context) {
+	promoID, ok := promoCodeID(ctx)
+	if !ok {
+		return
+	}
+
+	promo, err := h.repo.GetPromoCode(ctx, promoID)
+	if err != nil {
+		promoWriteFailed(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    promo,
+	})
+}
+
+// CreatePromoCode godoc
+// @Summary      Create promo code
+// @Description  Create a percentage or fixed discount on the ticket subtotal. Codes are stored in upper case,
+// @Description  empty movie_ids, cinema_ids and payment_method_ids are not restricted, usage limits are optional.
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        promo  body  models.PromoCodeRequest  true  "Promo code"
+// @Success      200  {object}  models.SuccessResponse{data=models.PromoCode}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/promo-codes [post]
+func (h *PromoHandler) CreatePromoCode(ctx *gin.Context) {
+	req, ok := bindPromoCode(ctx)
+	if !ok {
+		return
+	}
+
+	promo, err := h.repo.CreatePromoCode(ctx, req)
+	if err != nil {
+		promoWriteFailed(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Promo code created",
+		"data":    promo,
+	})
+}
+
+// UpdatePromoCode godoc
+// @Summary      Update promo code
+// @Description  Replace the settings of a promo code, redemptions so far are kept. usage_limit can not go below used_count.
+// @Tags         Admin
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id     path  int                      true  "Promo code ID"
+// @Param        promo  body  models.PromoCodeRequest  true  "Promo code"
+// @Success      200  {object}  models.SuccessResponse{data=models.PromoCode}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/promo-codes/{id} [put]
+func (h *PromoHandler) UpdatePromoCode(ctx *gin.Context) {
+	promoID, ok := promoCodeID(ctx)
+	if !ok {
+		return
+	}
+	req, ok := bindPromoCode(ctx)
+	if !ok {
+		return
+	}
+
+	promo, err := h.repo.UpdatePromoCode(ctx, promoID, req)
+	if err != nil {
+		promoWriteFailed(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Promo code updated",
+		"data":    promo,
+	})
+}
+
+// DeletePromoCode godoc
+// @Summary      Delete promo code
+// @Description  Delete a promo code that was never redeemed, set is_active to false to retire a used one
+// @Tags         Admin
+// @Security     BearerAuth
+// @Produce      json
+// @Param        id  path  int  true  "Promo code ID"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /admin/promo-codes/{id} [delete]
+func (h *PromoHandler) DeletePromoCode(ctx *gin.Context) {
+	promoID, ok := promoCodeID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeletePromoCode(ctx, promoID); err != nil {
+		if errors.Is(err, repositories.ErrPromoCodeInUse) {
+			ctx.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   "Promo code has been redeemed, deactivate it instead",
+			})
+			return
+		}
+		promoWriteFailed(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Promo code deleted",
+	})
+}
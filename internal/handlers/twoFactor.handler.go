@@ -0,0 +1,457 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type TwoFactorHandler struct {
+	repo        *repositories.TwoFactorRepository
+	userRepo    *repositories.UserRepository
+	sessionRepo *repositories.SessionRepository
+	JWTManager  *utils.JWTManager
+	secrets     *utils.SecretBox
+	config      models.TwoFactorConfig
+}
+
+func NewTwoFactorHandler(repo *repositories.TwoFactorRepository, userRepo *repositories.UserRepository, sessionRepo *repositories.SessionRepository, jwtManager *utils.JWTManager, secrets *utils.SecretBox, config models.TwoFactorConfig) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		repo:        repo,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		JWTManager:  jwtManager,
+		secrets:     secrets,
+		config:      config,
+	}
+}
+
+// whether the policy makes 2FA mandatory for the role
+func (h *TwoFactorHandler) required(role string) bool {
+	return h.config.RequiredForAdmin && role == models.RoleAdmin
+}
+
+// checks a code of the authenticator app, or else a recovery code. Each code is accepted once
+func (h *TwoFactorHandler) verifyCode(ctx context.Context, userID int, code string) (bool, error) {
+	secret, err := h.repo.GetSecret(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if secret.EnabledAt == nil || secret.Secret == nil {
+		return false, nil
+	}
+
+	plainSecret, err := h.secrets.Open(*secret.Secret)
+	if err != nil {
+		return false, err
+	}
+	if step, ok := utils.VerifyTOTP(plainSecret, code, time.Now()); ok {
+		return h.repo.UseTOTPStep(ctx, userID, step)
+	}
+
+	return h.repo.UseRecoveryCode(ctx, userID, utils.HashToken(utils.NormalizeRecoveryCode(code)))
+}
+
+// generate recovery codes, the hashes are stored and the codes shown once
+func newRecoveryCodes() ([]string, []string, error) {
+	codes, err := utils.GenerateRecoveryCodes(models.RecoveryCodeCount)
+	if err != nil {
+		return nil, nil, err
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = utils.HashToken(utils.NormalizeRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// GetStatus godoc
+// @Summary      Two-factor status
+// @Description  Whether two-factor authentication is enabled, required for the role, and the recovery codes left
+// @Tags         Two-factor
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  models.SuccessResponse{data=models.TwoFactorStatus}
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /auth/2fa [get]
+func (h *TwoFactorHandler) GetStatus(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	secret, err := h.repo.GetSecret(ctx, claims.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	status := models.TwoFactorStatus{
+		Enabled:   secret.EnabledAt != nil,
+		EnabledAt: secret.EnabledAt,
+		Required:  h.required(claims.Role),
+	}
+	if status.Enabled {
+		status.RecoveryCodesLeft, err = h.repo.CountRecoveryCodes(ctx, claims.UserID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "data from database",
+		"data":    status,
+	})
+}
+
+// Setup godoc
+// @Summary      Start two-factor enrollment
+// @Description  Generate a TOTP secret and its provisioning URI and QR code for an authenticator app.
+// @Description  Two-factor authentication is enabled once a code is confirmed at /auth/2fa/enable.
+// @Tags         Two-factor
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  models.SuccessResponse{data=models.TwoFactorSetup}
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /auth/2fa/setup [post]
+func (h *TwoFactorHandler) Setup(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to generate secret",
+		})
+		return
+	}
+	encrypted, err := h.secrets.Seal(secret)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to generate secret",
+		})
+		return
+	}
+
+	stored, err := h.repo.SetPendingSecret(ctx, claims.UserID, encrypted)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if !stored {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "Two-factor authentication is already enabled",
+		})
+		return
+	}
+
+	uri := utils.TOTPProvisioningURI(h.config.Issuer, claims.Email, secret)
+	qr, err := utils.RenderQRCodePNG(uri, 256)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to render QR code",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Scan the QR code with your authenticator app and confirm a code to enable two-factor authentication",
+		"data": models.TwoFactorSetup{
+			Secret:          secret,
+			ProvisioningURI: uri,
+			QRCode:          "data:image/png;base64," + base64.StdEncoding.EncodeToString(qr),
+		},
+	})
+}
+
+// Enable godoc
+// @Summary      Enable two-factor authentication
+// @Description  Confirm the secret of /auth/2fa/setup with a code of the authenticator app. Returns the recovery codes, shown only once,
+// @Description  and a new access token for the current session marked as verified with a second factor.
+// @Tags         Two-factor
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        code body models.TwoFactorCodeRequest true "Code of the authenticator app"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      409  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /auth/2fa/enable [post]
+func (h *TwoFactorHandler) Enable(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	var req models.TwoFactorCodeRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	secret, err := h.repo.GetSecret(ctx, claims.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if secret.EnabledAt != nil {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "Two-factor authentication is already enabled",
+		})
+		return
+	}
+	if secret.Secret == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Start the enrollment at /auth/2fa/setup first",
+		})
+		return
+	}
+
+	plainSecret, err := h.secrets.Open(*secret.Secret)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	step, ok := utils.VerifyTOTP(plainSecret, req.Code, time.Now())
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid code",
+		})
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to generate recovery codes",
+		})
+		return
+	}
+
+	enabled, err := h.repo.EnableTwoFactor(ctx, claims.UserID, step, hashes)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if !enabled {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "Two-factor authentication is already enabled",
+		})
+		return
+	}
+
+	// the code proves the second factor for the current session
+	response := gin.H{
+		"recovery_codes": codes,
+	}
+	if claims.SessionID != 0 {
+		if err := h.sessionRepo.MarkSessionMFA(ctx, claims.UserID, claims.SessionID); err != nil {
+			log.Println("Mark session mfa error:", err)
+		} else {
+			user := &models.User{ID: claims.UserID, Email: claims.Email, Role: claims.Role}
+			token, err := h.JWTManager.GenerateToken(user, claims.SessionID, true)
+			if err != nil {
+				log.Println("Generate token error:", err)
+			} else {
+				response["token"] = token
+				response["expires_in"] = int(h.JWTManager.AccessTTL().Seconds())
+			}
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Two-factor authentication enabled, keep the recovery codes in a safe place",
+		"data":    response,
+	})
+}
+
+// Disable godoc
+// @Summary      Disable two-factor authentication
+// @Description  Turn two-factor authentication off with the password and a code. Not allowed when it is required for the role.
+// @Tags         Two-factor
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        disable body models.DisableTwoFactorRequest true "Password and a code of the authenticator app or a recovery code"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      403  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /auth/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	var req models.DisableTwoFactorRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if h.required(claims.Role) {
+		ctx.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "Two-factor authentication is required for your role",
+		})
+		return
+	}
+
+	user, err := h.userRepo.GetUserByID(ctx, claims.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "invalid password",
+		})
+		return
+	}
+
+	valid, err := h.verifyCode(ctx, claims.UserID, req.Code)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if !valid {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "invalid code",
+		})
+		return
+	}
+
+	if err := h.repo.DisableTwoFactor(ctx, claims.UserID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Regenerate recovery codes
+// @Description  Replace the recovery codes, the old ones stop working. The new codes are shown only once.
+// @Tags         Two-factor
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        code body models.TwoFactorCodeRequest true "Code of the authenticator app or a recovery code"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      401  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /auth/2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	rawClaims, _ := ctx.Get("claims")
+	claims := rawClaims.(*utils.Claims)
+
+	var req models.TwoFactorCodeRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	valid, err := h.verifyCode(ctx, claims.UserID, req.Code)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if !valid {
+		ctx.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "invalid code",
+		})
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to generate recovery codes",
+		})
+		return
+	}
+	if err := h.repo.RegenerateRecoveryCodes(ctx, claims.UserID, hashes); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Recovery codes regenerated, keep them in a safe place",
+		"data": gin.H{
+			"recovery_codes": codes,
+		},
+	})
+}
@@ -1,17 +1,19 @@
 package middlewares
 
 import (
+	"errors"
+	"log"
 	"net/http"
 	"slices"
 	"strings"
 
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 )
 
-// get token from header and verify that
-func VerifyToken(jwtManager *utils.JWTManager, rdb *redis.Client) gin.HandlerFunc {
+// get token from header and verify that, revoked tokens are checked in redis and in postgres when redis is down
+func VerifyToken(jwtManager *utils.JWTManager, revocations *repositories.TokenRevocationRepository) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 		parts := strings.Fields(authHeader)
@@ -24,29 +26,28 @@ func VerifyToken(jwtManager *utils.JWTManager, rdb *redis.Client) gin.HandlerFun
 			return
 		}
 
-		tokenString := parts[1]
-		// check blacklist token
-		exists, err := rdb.Exists(ctx, "blacklist:"+tokenString).Result()
-		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+		claims, err := jwtManager.ValidateToken(parts[1])
+		if err != nil || claims.ID == "" || claims.IssuedAt == nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"success": false,
-				"error":   "Server error",
+				"error":   "Invalid or expired token",
 			})
 			return
 		}
-		if exists > 0 {
-			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+
+		revoked, err := revocations.IsTokenRevoked(ctx, claims.UserID, claims.SessionID, claims.ID, claims.IssuedAt.Time)
+		if err != nil {
+			log.Println("Check token revocation error:", err)
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
 				"success": false,
-				"error":   "Token Invalid, please login again",
+				"error":   "Authentication is unavailable, please try again later",
 			})
 			return
 		}
-
-		claims, err := jwtManager.ValidateToken(parts[1])
-		if err != nil {
+		if revoked {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"success": false,
-				"error":   "Invalid or expired token",
+				"error":   "Token Invalid, please login again",
 			})
 			return
 		}
@@ -87,3 +88,114 @@ func AuthMiddleware(allowedRoles ...string) func(*gin.Context) {
 		ctx.Next()
 	}
 }
+
+// checks the user has verified their email, used on routes that create orders
+func RequireVerifiedEmail(users *repositories.UserRepository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		rawClaims, _ := ctx.Get("claims")
+		claims, ok := rawClaims.(*utils.Claims)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Please login",
+			})
+			return
+		}
+
+		verified, err := users.IsEmailVerified(ctx, claims.UserID)
+		if err != nil {
+			log.Println("Check email verified error:", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "Internal server error",
+			})
+			return
+		}
+		if !verified {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Please verify your email before ordering",
+			})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// checks the role of the user has the permission. Users of a cinema scoped role need a cinema,
+// its id is set as "cinema_id" for the handler to limit the request to that cinema
+func RequirePermission(permissions *repositories.PermissionRepository, permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		rawClaims, _ := ctx.Get("claims")
+		claims, ok := rawClaims.(*utils.Claims)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Please login",
+			})
+			return
+		}
+
+		role, err := permissions.GetRole(ctx, claims.Role)
+		if err != nil && !errors.Is(err, repositories.ErrRoleNotFound) {
+			log.Println("Get role permissions error:", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "Internal server error",
+			})
+			return
+		}
+		if role == nil || !slices.Contains(role.Permissions, permission) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "You do not have access rights to this resource",
+			})
+			return
+		}
+
+		if role.CinemaScoped {
+			cinemaID, err := permissions.GetUserCinemaID(ctx, claims.UserID)
+			if err != nil {
+				log.Println("Get user cinema error:", err)
+				ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+					"success": false,
+					"error":   "Internal server error",
+				})
+				return
+			}
+			if cinemaID == nil {
+				ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+					"success": false,
+					"error":   "No cinema is assigned to your account",
+				})
+				return
+			}
+			ctx.Set("cinema_id", *cinemaID)
+		}
+		ctx.Next()
+	}
+}
+
+// checks users of the given roles passed a second factor in their session, the token must be issued after it
+func RequireTwoFactor(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		rawClaims, _ := ctx.Get("claims")
+		claims, ok := rawClaims.(*utils.Claims)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Please login",
+			})
+			return
+		}
+
+		if slices.Contains(roles, claims.Role) && !claims.MFA {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Two-factor authentication is required, enable it at /auth/2fa/setup",
+			})
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -61,21 +61,23 @@ type EditMovies struct {
 
 type Schedule struct {
 	Date string `json:"date" example:"2025-09-10"`
-	Time string `json:"time" example:"18:00"`
+	Time string `json:"time" example:"21:30"`
 }
 
 type CinemaScheduleLocation struct {
-	CinemaID   int64  `json:"cinemas_id" example:"1"`
-	LocationID int64  `json:"locations_id" example:"2"`
-	ScheduleID int    `json:"schedules_id" example:"85"`
-	Date       string `json:"date"`
-	Time       string `json:"time"`
+	CinemaID     int64  `json:"cinemas_id" example:"1"`
+	LocationID   int64  `json:"locations_id" example:"2"`
+	ScheduleID   int    `json:"schedules_id" example:"85"`
+	AuditoriumID int64  `json:"auditorium_id" example:"1"`
+	Date         string `json:"date"`
+	Time         string `json:"time"`
 }
 
 type GetSchedule struct {
 	ID        int       `json:"id" example:"1"`
 	Date      time.Time `json:"date" example:"2025-09-10"`
 	Time      string    `json:"time" example:"18:00"`
+	EndTime   string    `json:"end_time" example:"20:15"`
 	MovieID   int       `json:"movie_id" example:"1"`
 	MovieName string    `json:"title"`
 }
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// result of a ticket scan
+const (
+	CheckInStatusCheckedIn      = "checked_in"
+	CheckInStatusAlreadyUsed    = "already_used"
+	CheckInStatusInvalid        = "invalid"
+	CheckInStatusNotPaid        = "not_paid"
+	CheckInStatusWrongCinema    = "wrong_cinema"
+	CheckInStatusWrongScreening = "wrong_screening"
+	CheckInStatusTooEarly       = "too_early"
+	CheckInStatusTooLate        = "too_late"
+)
+
+type CheckInRequest struct {
+	Code              string `json:"code" binding:"required" example:"TKZ1.MTI6Mzo0LDU.f0ibSpt1U_nOiUlG_-fJ5fiTDNHRBway6MBSrvV5Wvw"`
+	CinemasScheduleID int    `json:"cinemas_schedule_id,omitempty" example:"1"`
+}
+
+// CheckInWindow is the time around the showtime when tickets are accepted
+type CheckInWindow struct {
+	OpenBefore time.Duration
+	CloseAfter time.Duration
+}
+
+type CheckInTicket struct {
+	OrderID           int           `json:"order_id"`
+	QRCode            string        `json:"-"`
+	PaymentStatus     string        `json:"payment_status"`
+	CinemaID          int           `json:"cinema_id"`
+	Cinema            string        `json:"cinema"`
+	Auditorium        string        `json:"auditorium"`
+	CinemasScheduleID int           `json:"cinemas_schedule_id"`
+	Title             string        `json:"title"`
+	Showtime          string        `json:"showtime"`
+	SeatNumbers       []string      `json:"seat_numbers"`
+	CheckedInAt       *time.Time    `json:"checked_in_at"`
+	UntilShowtime     time.Duration `json:"-"`
+}
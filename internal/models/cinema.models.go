@@ -2,10 +2,49 @@ package models
 
 import "time"
 
+const (
+	SeatTypeRegular  = "regular"
+	SeatTypeVIP      = "vip"
+	SeatTypeLoveseat = "loveseat"
+)
+
+const (
+	SeatStatusAvailable = "available"
+	SeatStatusBooked    = "booked"
+	SeatStatusHeld      = "held"
+)
+
 type CinemaSeat struct {
 	SeatID     int    `json:"seat_id"`
 	SeatNumber string `json:"seat_number"`
+	Row        string `json:"row"`
+	Column     int    `json:"column"`
 	SeatType   string `json:"seat_type"`
+	Status     string `json:"status"`
+}
+
+type Auditorium struct {
+	ID       int    `json:"id"`
+	CinemaID int    `json:"cinemas_id"`
+	Name     string `json:"name"`
+	Rows     int    `json:"rows"`
+	Columns  int    `json:"columns"`
+	Aisles   []int  `json:"aisles"`
+}
+
+type AddAuditorium struct {
+	Name         string   `json:"name" binding:"required" example:"Studio 2"`
+	Rows         int      `json:"rows" binding:"required,min=1,max=26" example:"7"`
+	Columns      int      `json:"columns" binding:"required,min=1,max=50" example:"14"`
+	Aisles       []int    `json:"aisles" example:"7"`
+	VIPRows      []string `json:"vip_rows" example:"E,F"`
+	LoveseatRows []string `json:"loveseat_rows" example:"G"`
+}
+
+type SeatLayout struct {
+	CinemaScheduleID int          `json:"cinemas_schedule_id"`
+	Auditorium       Auditorium   `json:"auditorium"`
+	Seats            []CinemaSeat `json:"seats"`
 }
 
 type GetFilterSchedules struct {
@@ -32,3 +71,47 @@ type GetFilterSchedulesCache struct {
 	Data       []GetFilterSchedules `json:"data"`
 	TotalCount int                  `json:"total_count"`
 }
+
+type AdminCinema struct {
+	ID          int     `json:"id"`
+	Name        string  `json:"name"`
+	BasePrice   float64 `json:"base_price"`
+	ImagePath   *string `json:"image_path"`
+	Auditoriums int     `json:"auditoriums"`
+}
+
+// the logo is uploaded in the logo form field
+type AddCinema struct {
+	Name      string   `form:"name" json:"name" binding:"required,max=100" example:"EBV Surabaya"`
+	BasePrice *float64 `form:"base_price" json:"base_price" binding:"required,min=0" example:"50000"`
+	ImagePath *string  `form:"-" json:"-"`
+}
+
+type EditCinema struct {
+	Name      *string  `form:"name" json:"name,omitempty" binding:"omitempty,min=1,max=100" example:"EBV Surabaya"`
+	BasePrice *float64 `form:"base_price" json:"base_price,omitempty" binding:"omitempty,min=0" example:"55000"`
+	ImagePath *string  `form:"-" json:"-"`
+}
+
+// Location is a city where cinemas show movies, stored in locations.name
+type Location struct {
+	ID        int      `json:"id"`
+	City      string   `json:"city"`
+	Address   *string  `json:"address"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+type AddLocation struct {
+	City      string   `json:"city" binding:"required,max=100" example:"Surabaya"`
+	Address   *string  `json:"address" binding:"omitempty,max=255" example:"Jl. Basuki Rahmat No. 8"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90" example:"-7.265"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180" example:"112.742"`
+}
+
+type EditLocation struct {
+	City      *string  `json:"city" binding:"omitempty,min=1,max=100" example:"Surabaya"`
+	Address   *string  `json:"address" binding:"omitempty,max=255" example:"Jl. Basuki Rahmat No. 8"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90" example:"-7.265"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180" example:"112.742"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// loyalty transaction types, points is positive for earn, opening and restore and negative for the others
+const (
+	LoyaltyOpening = "opening"
+	LoyaltyEarn    = "earn"
+	LoyaltyRedeem  = "redeem"
+	LoyaltyReverse = "reverse"
+	LoyaltyRestore = "restore"
+	LoyaltyForfeit = "forfeit"
+)
+
+// LoyaltyPolicy decides how much points are worth at checkout
+type LoyaltyPolicy struct {
+	PointValue       float64 // discount of one redeemed point
+	MaxRedeemPercent float64 // share of the ticket subtotal that can be paid with points
+}
+
+type LoyaltyTier struct {
+	Name      string `json:"name" example:"Silver"`
+	MinPoints int    `json:"min_points" example:"500"`
+}
+
+// membership tiers by balance, lowest first
+var LoyaltyTiers = []LoyaltyTier{
+	{Name: "Bronze", MinPoints: 0},
+	{Name: "Silver", MinPoints: 500},
+	{Name: "Gold", MinPoints: 2000},
+	{Name: "Platinum", MinPoints: 5000},
+}
+
+// LoyaltyTierFor returns the tier of a balance and the next one, nil at the highest tier
+func LoyaltyTierFor(balance int) (LoyaltyTier, *LoyaltyTier) {
+	current := LoyaltyTiers[0]
+	for i, tier := range LoyaltyTiers {
+		if balance < tier.MinPoints {
+			return current, &LoyaltyTiers[i]
+		}
+		current = tier
+	}
+	return current, nil
+}
+
+type LoyaltyTransaction struct {
+	ID           int       `json:"id"`
+	OrderID      *int      `json:"order_id"`
+	Type         string    `json:"type" example:"earn"`
+	Points       int       `json:"points" example:"120"`
+	BalanceAfter int       `json:"balance_after" example:"620"`
+	Description  *string   `json:"description"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type LoyaltySummary struct {
+	Balance          int          `json:"balance" example:"620"`
+	Tier             LoyaltyTier  `json:"tier"`
+	NextTier         *LoyaltyTier `json:"next_tier"`
+	PointsToNextTier int          `json:"points_to_next_tier" example:"1380"`
+	PointValue       float64      `json:"point_value" example:"100"`
+	MaxRedeemPercent float64      `json:"max_redeem_percent" example:"50"`
+}
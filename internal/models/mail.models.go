@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// EmailVerificationConfig configures the verification emails sent on registration
+type EmailVerificationConfig struct {
+	AppURL         string
+	TokenTTL       time.Duration
+	ResendCooldown time.Duration
+}
+
+// PasswordResetConfig configures the reset emails, a new link is sent at most once every cooldown
+type PasswordResetConfig struct {
+	TokenTTL time.Duration
+	Cooldown time.Duration
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required" example:"your verification token..."`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email" example:"user@mail.com"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email" example:"user@mail.com"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required" example:"your reset token..."`
+	NewPassword string `json:"new_password" binding:"required" example:"NewPassword123!"`
+}
@@ -57,3 +57,51 @@ type MoviesCache struct {
 	Movies     []Movie `json:"movies"`
 	TotalCount int     `json:"total_count"`
 }
+
+// people credited on movies, the kind is their table
+const (
+	PersonCast     = "casts"
+	PersonDirector = "directors"
+)
+
+type Genre struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Movies int    `json:"movies"`
+}
+
+type GenreRequest struct {
+	Name string `json:"name" binding:"required,max=50" example:"Musical"`
+}
+
+type Person struct {
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	PhotoPath *string `json:"photo_path"`
+	Bio       *string `json:"bio"`
+	Movies    int     `json:"movies"`
+}
+
+// the photo is uploaded in the photo form field
+type AddPerson struct {
+	Name      string  `form:"name" json:"name" binding:"required,max=100" example:"Reza Rahadian"`
+	Bio       *string `form:"bio" json:"bio" binding:"omitempty,max=5000" example:"Indonesian actor..."`
+	PhotoPath *string `form:"-" json:"-"`
+}
+
+type EditPerson struct {
+	Name      *string `form:"name" json:"name,omitempty" binding:"omitempty,min=1,max=100" example:"Reza Rahadian"`
+	Bio       *string `form:"bio" json:"bio,omitempty" binding:"omitempty,max=5000" example:"Indonesian actor..."`
+	PhotoPath *string `form:"-" json:"-"`
+}
+
+// MergeRequest moves the movies of the sources to the merge target and deletes the sources
+type MergeRequest struct {
+	SourceIDs []int `json:"source_ids" binding:"required,min=1,dive,min=1" example:"4,7"`
+}
+
+type CatalogFilter struct {
+	Search string
+	Limit  int
+	Offset int
+}
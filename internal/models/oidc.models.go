@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// OIDCProviderConfig is an OpenID Connect identity provider users can sign in with
+type OIDCProviderConfig struct {
+	Name         string
+	DisplayName  string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// OIDCProvider is a provider as listed for the login page
+type OIDCProvider struct {
+	Name        string `json:"name" example:"google"`
+	DisplayName string `json:"display_name" example:"Google"`
+}
+
+// OIDCLoginState is kept between the authorization request and the callback, used once
+type OIDCLoginState struct {
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+	ExpiresAt    time.Time
+}
+
+// OIDCIdentity is the user as told by the ID token of the provider
+type OIDCIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+}
+
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorization_url" example:"https://accounts.google.com/o/oauth2/v2/auth?client_id=..."`
+	State            string `json:"state" example:"3f1c..."`
+	ExpiresIn        int    `json:"expires_in" example:"600"`
+}
+
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" binding:"required" example:"4/0AX4XfWh..."`
+	State string `json:"state" binding:"required" example:"3f1c..."`
+}
@@ -11,18 +11,33 @@ type Order struct {
 	UserID            int              `json:"user_id"`
 	CinemasScheduleID int              `json:"cinemas_schedule_id"`
 	PaymentMethodID   int              `json:"payment_method_id"`
+	PaymentStatus     string           `json:"payment_status"`
+	PaymentReference  string           `json:"payment_reference,omitempty"`
 	CreatedAt         time.Time        `json:"created_at"`
 	UpdatedAt         time.Time        `json:"updated_at"`
 	OrderSeats        []OrderSeatInput `json:"seats"`
+	PriceBreakdown    *PriceBreakdown  `json:"price_breakdown,omitempty"`
+	PointsRedeemed    int              `json:"points_redeemed"`
+	PromoCodeID       *int             `json:"promo_code_id,omitempty"`
 }
 
 type OrderRequest struct {
-	IsPaid            bool             `json:"is_paid" `
 	IsActive          bool             `json:"is_active"`
-	TotalPrices       float64          `json:"total_prices" binding:"required" example:"120000"`
+	TotalPrices       float64          `json:"total_prices,omitempty" example:"120000"`
 	CinemasScheduleID int              `json:"cinemas_schedule_id" binding:"required" example:"1"`
 	PaymentMethodID   int              `json:"payment_method_id" binding:"required" example:"2"`
-	OrderSeats        []OrderSeatInput `json:"seats" binding:"required,dive"`
+	OrderSeats        []OrderSeatInput `json:"seats" binding:"required,min=1,dive"`
+	RedeemPoints      int              `json:"redeem_points,omitempty" binding:"min=0" example:"100"`
+	PromoCode         string           `json:"promo_code,omitempty" binding:"max=30" example:"NONTON20"`
+}
+
+// OrderQuoteRequest prices an order without creating it
+type OrderQuoteRequest struct {
+	CinemasScheduleID int              `json:"cinemas_schedule_id" binding:"required" example:"1"`
+	PaymentMethodID   int              `json:"payment_method_id" binding:"required" example:"2"`
+	OrderSeats        []OrderSeatInput `json:"seats" binding:"required,min=1,dive"`
+	RedeemPoints      int              `json:"redeem_points,omitempty" binding:"min=0" example:"100"`
+	PromoCode         string           `json:"promo_code,omitempty" binding:"max=30" example:"NONTON20"`
 }
 
 type OrderSeat struct {
@@ -43,6 +58,8 @@ type OrderHistory struct {
 	ID             int      `json:"id"`
 	IsActive       bool     `json:"is_active"`
 	IsPaid         bool     `json:"is_paid"`
+	PaymentStatus  string   `json:"payment_status"`
+	CancelReason   *string  `json:"cancel_reason"`
 	QRCode         string   `json:"qr_code"`
 	TotalPrices    float64  `json:"total_prices"`
 	UserID         int      `json:"user_id"`
@@ -57,3 +74,156 @@ type OrderHistory struct {
 	SeatNumbers    []string `json:"seat_numbers"`
 	SeatType       string   `json:"seat_type"`
 }
+
+type SeatHoldRequest struct {
+	CinemasScheduleID int   `json:"cinemas_schedule_id" binding:"required" example:"1"`
+	SeatIDs           []int `json:"seat_ids" binding:"required,min=1" example:"1,2"`
+}
+
+type SeatHold struct {
+	CinemasScheduleID int       `json:"cinemas_schedule_id"`
+	SeatIDs           []int     `json:"seat_ids"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+/* Pricing */
+type PriceAdjustment struct {
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount"`
+}
+
+type TicketPrice struct {
+	SeatID        int               `json:"seat_id"`
+	SeatNumber    string            `json:"seat_number"`
+	SeatType      string            `json:"seat_type"`
+	BasePrice     float64           `json:"base_price"`
+	SeatSurcharge float64           `json:"seat_surcharge"`
+	Adjustments   []PriceAdjustment `json:"adjustments"`
+	Price         float64           `json:"price"`
+}
+
+type PriceBreakdown struct {
+	Tickets        []TicketPrice `json:"tickets"`
+	Subtotal       float64       `json:"subtotal"`
+	PromoCode      string        `json:"promo_code,omitempty"`
+	PromoDiscount  float64       `json:"promo_discount,omitempty"`
+	PointsRedeemed int           `json:"points_redeemed,omitempty"`
+	PointsDiscount float64       `json:"points_discount,omitempty"`
+	PaymentFee     float64       `json:"payment_fee"`
+	Total          float64       `json:"total"`
+}
+
+type PriceRule struct {
+	Name       string
+	DaysOfWeek []int
+	StartTime  *string
+	EndTime    *string
+	Adjustment float64
+}
+
+type PricingSeat struct {
+	SeatID     int
+	SeatNumber string
+	SeatType   string
+	Surcharge  float64
+}
+
+type PricingInput struct {
+	MovieID      int
+	CinemaID     int
+	BasePrice    float64
+	ScheduleDate time.Time
+	ScheduleTime string
+	PaymentFee   float64
+	Seats        []PricingSeat
+	Rules        []PriceRule
+}
+
+// RefundPolicy decides how much of a paid order is refunded when it is cancelled
+type RefundPolicy struct {
+	CutoffMinutes     int     // no cancellation closer to the showtime
+	FullRefundMinutes int     // full refund when cancelled at least this long before the showtime
+	PartialPercent    float64 // refunded percentage between the cutoff and the full refund window
+}
+
+type CancellableOrder struct {
+	ID               int
+	UserID           int
+	CinemaID         int
+	PaymentStatus    string
+	PaymentReference *string
+	Provider         string
+	TotalPrices      float64
+	UntilShowtime    time.Duration
+}
+
+type AdminCancelOrderRequest struct {
+	RefundPercent *float64 `json:"refund_percent" binding:"omitempty,min=0,max=100" example:"100"`
+	Reason        string   `json:"reason" binding:"max=100" example:"screening cancelled"`
+}
+
+// PendingRefund is the refund of a refunding order, a retried cancellation refunds the same amount
+type PendingRefund struct {
+	Amount float64
+	Reason string
+	Sent   bool
+}
+
+type OrderCancellation struct {
+	OrderID        int     `json:"order_id"`
+	PaymentStatus  string  `json:"payment_status"`
+	RefundAmount   float64 `json:"refund_amount"`
+	PointsReversed int     `json:"points_reversed"`
+	PointsRestored int     `json:"points_restored"`
+}
+
+type TicketClaims struct {
+	OrderID           int   `json:"order_id"`
+	CinemasScheduleID int   `json:"cinemas_schedule_id"`
+	SeatIDs           []int `json:"seat_ids"`
+}
+
+type OrderTicket struct {
+	UserID        int
+	PaymentStatus string
+	QRCode        *string
+}
+
+type OrderDetailSeat struct {
+	SeatID     int    `json:"seat_id"`
+	SeatNumber string `json:"seat_number"`
+	SeatType   string `json:"seat_type"`
+	Status     string `json:"status"`
+}
+
+type OrderDetail struct {
+	ID                int               `json:"id"`
+	UserID            int               `json:"-"`
+	PaymentStatus     string            `json:"payment_status"`
+	IsPaid            bool              `json:"is_paid"`
+	IsActive          bool              `json:"is_active"`
+	QRCode            *string           `json:"qr_code"`
+	TotalPrices       float64           `json:"total_prices"`
+	PriceBreakdown    *PriceBreakdown   `json:"price_breakdown"`
+	PaymentMethod     string            `json:"payment_method"`
+	PaymentReference  *string           `json:"payment_reference"`
+	PaidAt            *time.Time        `json:"paid_at"`
+	CancelReason      *string           `json:"cancel_reason"`
+	CancelledAt       *time.Time        `json:"cancelled_at"`
+	RefundAmount      *float64          `json:"refund_amount"`
+	CheckedInAt       *time.Time        `json:"checked_in_at"`
+	CreatedAt         time.Time         `json:"created_at"`
+	MovieID           int               `json:"movie_id"`
+	Title             string            `json:"title"`
+	AgeRating         *string           `json:"age_rating"`
+	PosterPath        *string           `json:"poster_path"`
+	Duration          *int              `json:"duration"`
+	CinemasScheduleID int               `json:"cinemas_schedule_id"`
+	Cinema            string            `json:"cinema"`
+	CinemaImage       *string           `json:"cinema_image"`
+	Location          string            `json:"location"`
+	Auditorium        *string           `json:"auditorium"`
+	Date              string            `json:"date"`
+	Time              string            `json:"time"`
+	Seats             []OrderDetailSeat `json:"seats"`
+}
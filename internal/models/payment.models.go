@@ -0,0 +1,108 @@
+package models
+
+import "time"
+
+const (
+	PaymentStatusPending   = "pending"
+	PaymentStatusPaid      = "paid"
+	PaymentStatusRefunding = "refunding"
+	PaymentStatusFailed    = "failed"
+	PaymentStatusExpired   = "expired"
+	PaymentStatusRefunded  = "refunded"
+	PaymentStatusCancelled = "cancelled"
+)
+
+// recorded in orders.cancel_reason when an order is no longer valid
+const (
+	CancelReasonPaymentExpired = "payment_expired"
+	CancelReasonPaymentFailed  = "payment_failed"
+	CancelReasonRefunded       = "refunded"
+	CancelReasonUserCancelled  = "user_cancelled"
+	CancelReasonAdminCancelled = "admin_cancelled"
+)
+
+// amount of a paid order worth one loyalty point
+const LoyaltyPointAmount = 1000
+
+// CancelReasonForPayment returns the cancel reason of a payment status, empty when the order stays valid
+func CancelReasonForPayment(status string) string {
+	switch status {
+	case PaymentStatusExpired:
+		return CancelReasonPaymentExpired
+	case PaymentStatusFailed:
+		return CancelReasonPaymentFailed
+	case PaymentStatusRefunded:
+		return CancelReasonRefunded
+	case PaymentStatusCancelled:
+		return CancelReasonUserCancelled
+	}
+	return ""
+}
+
+// allowed payment status changes, key is the current status.
+// paid -> refunding and back is only done while a refund is sent to the provider
+var paymentTransitions = map[string][]string{
+	PaymentStatusPending:   {PaymentStatusPaid, PaymentStatusFailed, PaymentStatusExpired, PaymentStatusCancelled},
+	PaymentStatusPaid:      {PaymentStatusRefunded},
+	PaymentStatusRefunding: {PaymentStatusRefunded},
+}
+
+// PaymentSourceStatuses returns the statuses an order can move to the given status from
+func PaymentSourceStatuses(to string) []string {
+	sources := []string{}
+	for from, targets := range paymentTransitions {
+		for _, target := range targets {
+			if target == to {
+				sources = append(sources, from)
+			}
+		}
+	}
+	return sources
+}
+
+type PaymentMethod struct {
+	ID       int     `json:"id"`
+	Name     string  `json:"name"`
+	Provider string  `json:"provider"`
+	Fee      float64 `json:"fee"`
+	IsActive bool    `json:"is_active"`
+}
+
+type AddPaymentMethod struct {
+	Name     string  `json:"name" binding:"required,max=50" example:"DANA"`
+	Provider string  `json:"provider" binding:"required,max=50" example:"DANA"`
+	Fee      float64 `json:"fee" binding:"min=0" example:"2500"`
+	IsActive *bool   `json:"is_active" example:"true"`
+}
+
+type EditPaymentMethod struct {
+	Name     *string  `json:"name" binding:"omitempty,min=1,max=50" example:"DANA"`
+	Provider *string  `json:"provider" binding:"omitempty,min=1,max=50" example:"DANA"`
+	Fee      *float64 `json:"fee" binding:"omitempty,min=0" example:"2500"`
+	IsActive *bool    `json:"is_active" example:"false"`
+}
+
+type PaymentCharge struct {
+	OrderID     int
+	Amount      float64
+	MethodName  string
+	Description string
+}
+
+type PaymentSession struct {
+	Provider   string     `json:"provider"`
+	Reference  string     `json:"reference"`
+	PaymentURL string     `json:"payment_url,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+type PaymentEvent struct {
+	ID        string  `json:"id"`
+	Reference string  `json:"reference"`
+	Status    string  `json:"status"`
+	Amount    float64 `json:"amount"`
+}
+
+type PaymentSimulation struct {
+	Status string `json:"status" binding:"required,oneof=paid failed expired" example:"paid"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// permissions granted to roles in role_permissions
+const (
+	PermissionMoviesWrite      = "movies:write"
+	PermissionSchedulesWrite   = "schedules:write"
+	PermissionAuditoriumsWrite = "auditoriums:write"
+	PermissionOrdersRefund     = "orders:refund"
+	PermissionCheckInScan      = "checkin:scan"
+	PermissionUsersManage      = "users:manage"
+	PermissionPromosManage     = "promos:manage"
+	PermissionCinemasWrite     = "cinemas:write"
+	PermissionPaymentsManage   = "payments:manage"
+)
+
+// Role with its permissions, users of a cinema scoped role only act on their own cinema
+type Role struct {
+	ID           int      `json:"id"`
+	Name         string   `json:"name"`
+	Description  *string  `json:"description"`
+	CinemaScoped bool     `json:"cinema_scoped"`
+	Permissions  []string `json:"permissions"`
+}
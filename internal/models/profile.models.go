@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type Profile struct {
 	ID             int     `json:"id"`
 	Email          string  `json:"email"`
@@ -25,10 +27,48 @@ type ProfileUpdate struct {
 	LastName    *string `form:"last_name" json:"last_name"`
 	PhoneNumber *string `form:"phone_number" json:"phone_number"`
 	ImagePath   *string `form:"image_path" json:"image_path"`
-	Points      *int    `form:"points" json:"points"`
 }
 
 type UserUpdateRequest struct {
 	User    UserUpdate    `json:"user"`
 	Profile ProfileUpdate `json:"profile"`
 }
+
+// AccountExport is the personal data of a user, exported at /profile/export
+type AccountExport struct {
+	ExportedAt time.Time        `json:"exported_at"`
+	Account    ExportAccount    `json:"account"`
+	Profile    ExportProfile    `json:"profile"`
+	Identities []ExportIdentity `json:"linked_accounts"`
+	Sessions   []Session        `json:"sessions"`
+	Orders     []OrderDetail    `json:"orders"`
+}
+
+type ExportAccount struct {
+	ID                 int        `json:"id"`
+	Email              string     `json:"email"`
+	Role               string     `json:"role"`
+	VirtualAccount     *string    `json:"virtual_account"`
+	CreatedAt          *time.Time `json:"created_at"`
+	EmailVerifiedAt    *time.Time `json:"email_verified_at"`
+	TwoFactorEnabledAt *time.Time `json:"two_factor_enabled_at"`
+}
+
+type ExportProfile struct {
+	FirstName   *string `json:"first_name"`
+	LastName    *string `json:"last_name"`
+	PhoneNumber *string `json:"phone_number"`
+	Points      *int    `json:"points"`
+	ImagePath   *string `json:"image_path"`
+}
+
+type ExportIdentity struct {
+	Provider    string     `json:"provider"`
+	Email       *string    `json:"email"`
+	CreatedAt   *time.Time `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required" example:"your_password"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+const (
+	PromoDiscountPercentage = "percentage"
+	PromoDiscountFixed      = "fixed"
+)
+
+// PromoCode discounts the ticket subtotal, empty id lists are not restricted
+type PromoCode struct {
+	ID               int        `json:"id"`
+	Code             string     `json:"code" example:"NONTON20"`
+	Description      *string    `json:"description"`
+	DiscountType     string     `json:"discount_type" example:"percentage"`
+	DiscountValue    float64    `json:"discount_value" example:"20"`
+	MaxDiscount      *float64   `json:"max_discount" example:"25000"`
+	MinSpend         float64    `json:"min_spend" example:"50000"`
+	UsageLimit       *int       `json:"usage_limit" example:"100"`
+	PerUserLimit     *int       `json:"per_user_limit" example:"1"`
+	UsedCount        int        `json:"used_count" example:"12"`
+	ValidFrom        *time.Time `json:"valid_from"`
+	ValidUntil       *time.Time `json:"valid_until"`
+	MovieIDs         []int      `json:"movie_ids"`
+	CinemaIDs        []int      `json:"cinema_ids"`
+	PaymentMethodIDs []int      `json:"payment_method_ids"`
+	IsActive         bool       `json:"is_active"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+type PromoCodeRequest struct {
+	Code             string     `json:"code" binding:"required,min=3,max=30,alphanum" example:"NONTON20"`
+	Description      *string    `json:"description" binding:"omitempty,max=255" example:"20% off up to 25000"`
+	DiscountType     string     `json:"discount_type" binding:"required,oneof=percentage fixed" example:"percentage"`
+	DiscountValue    float64    `json:"discount_value" binding:"required,gt=0" example:"20"`
+	MaxDiscount      *float64   `json:"max_discount" binding:"omitempty,gt=0" example:"25000"`
+	MinSpend         float64    `json:"min_spend" binding:"min=0" example:"50000"`
+	UsageLimit       *int       `json:"usage_limit" binding:"omitempty,min=1" example:"100"`
+	PerUserLimit     *int       `json:"per_user_limit" binding:"omitempty,min=1" example:"1"`
+	ValidFrom        *time.Time `json:"valid_from" example:"2025-01-01T00:00:00Z"`
+	ValidUntil       *time.Time `json:"valid_until" example:"2025-01-31T23:59:59Z"`
+	MovieIDs         []int      `json:"movie_ids" binding:"omitempty,dive,min=1"`
+	CinemaIDs        []int      `json:"cinema_ids" binding:"omitempty,dive,min=1"`
+	PaymentMethodIDs []int      `json:"payment_method_ids" binding:"omitempty,dive,min=1"`
+	IsActive         *bool      `json:"is_active" example:"true"`
+}
+
+type PromoCodeFilter struct {
+	Search string
+	Status string
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// recorded in user_sessions.revoked_reason
+const (
+	SessionRevokedLogout         = "logout"
+	SessionRevokedByUser         = "revoked_by_user"
+	SessionRevokedTokenReuse     = "token_reuse"
+	SessionRevokedLogoutAll      = "logout_all"
+	SessionRevokedPasswordChange = "password_change"
+	SessionRevokedPasswordReset  = "password_reset"
+	SessionRevokedRoleChange     = "role_change"
+	SessionRevokedSuspended      = "suspended"
+	SessionRevokedIdentityLinked = "identity_linked"
+	SessionRevokedAccountDeleted = "account_deleted"
+)
+
+type Session struct {
+	ID         int       `json:"id"`
+	UserAgent  *string   `json:"user_agent"`
+	IPAddress  *string   `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+type NewSession struct {
+	UserID    int
+	UserAgent string
+	IPAddress string
+	TokenHash string
+	ExpiresAt time.Time
+	MFA       bool
+}
+
+// AuthSession is the session of a rotated refresh token, MFA when it was started with a second factor
+type AuthSession struct {
+	ID   int
+	MFA  bool
+	User User
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"your refresh token..."`
+}
+
+// LoginThrottleConfig configures the lockout after failed logins, a lockout doubles with every failure after the threshold
+type LoginThrottleConfig struct {
+	MaxAccountAttempts int
+	MaxIPAttempts      int
+	BaseLockout        time.Duration
+	MaxLockout         time.Duration
+	Window             time.Duration
+}
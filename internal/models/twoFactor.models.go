@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// TwoFactorConfig configures TOTP, with RequiredForAdmin admin routes need a token issued after a second factor
+type TwoFactorConfig struct {
+	Issuer           string
+	ChallengeTTL     time.Duration
+	RequiredForAdmin bool
+}
+
+// number of recovery codes generated at once
+const RecoveryCodeCount = 10
+
+type TwoFactorStatus struct {
+	Enabled           bool       `json:"enabled"`
+	EnabledAt         *time.Time `json:"enabled_at"`
+	RecoveryCodesLeft int        `json:"recovery_codes_left"`
+	Required          bool       `json:"required"`
+}
+
+type TwoFactorSetup struct {
+	Secret          string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	ProvisioningURI string `json:"provisioning_uri" example:"otpauth://totp/Tickitz:admin%40mail.com?secret=..."`
+	QRCode          string `json:"qr_code" example:"data:image/png;base64,..."`
+}
+
+// TwoFactorSecret is the encrypted TOTP secret of a user, enabled once EnabledAt is set
+type TwoFactorSecret struct {
+	Secret    *string
+	EnabledAt *time.Time
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required" example:"your_password"`
+	Code     string `json:"code" binding:"required" example:"123456"`
+}
+
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required" example:"your challenge token..."`
+	Code           string `json:"code" binding:"required" example:"123456"`
+}
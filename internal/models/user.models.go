@@ -1,20 +1,64 @@
 package models
 
+import "time"
+
 type User struct {
-	ID             int    `json:"id"`
-	Email          string `json:"email"`
-	Role           string `json:"role"`
-	Password       string `json:"password"`
-	VirtualAccount string `json:"virtual_account"`
+	ID                 int        `json:"id"`
+	Email              string     `json:"email"`
+	Role               string     `json:"role"`
+	Password           string     `json:"password"`
+	VirtualAccount     string     `json:"virtual_account"`
+	EmailVerifiedAt    *time.Time `json:"email_verified_at"`
+	SuspendedAt        *time.Time `json:"suspended_at"`
+	TwoFactorEnabledAt *time.Time `json:"two_factor_enabled_at"`
 }
 
 type RegisterUser struct {
 	Email    string `json:"email" binding:"required,email" example:"user@mail.com"`
 	Password string `json:"password" binding:"required,min=8" example:"User@testing123"`
-	Role     string `json:"role,omitempty" example:"user"`
 }
 
 type LoginUser struct {
 	Email    string `json:"email"  binding:"required,email" example:"user@mail.com"`
 	Password string `json:"password"  binding:"required" example:"your_password"`
 }
+
+const (
+	RoleUser          = "user"
+	RoleStaff         = "staff"
+	RoleCinemaManager = "cinema_manager"
+	RoleAdmin         = "admin"
+)
+
+// AdminUser is a user account as listed for the admins
+type AdminUser struct {
+	ID              int        `json:"id"`
+	Email           string     `json:"email"`
+	Role            string     `json:"role"`
+	CinemaID        *int       `json:"cinema_id"`
+	FirstName       *string    `json:"first_name"`
+	LastName        *string    `json:"last_name"`
+	PhoneNumber     *string    `json:"phone_number"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	SuspendedAt     *time.Time `json:"suspended_at"`
+	SuspendedReason *string    `json:"suspended_reason"`
+	DeletedAt       *time.Time `json:"deleted_at"`
+	CreatedAt       *time.Time `json:"created_at"`
+}
+
+type AdminUserFilter struct {
+	Search string
+	Role   string
+	Status string
+	Limit  int
+	Offset int
+}
+
+type UpdateUserRoleRequest struct {
+	Role     string `json:"role" binding:"required,max=20" example:"staff"`
+	CinemaID *int   `json:"cinema_id,omitempty" example:"1"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" binding:"max=255" example:"Chargeback fraud"`
+}
@@ -3,22 +3,41 @@ package repositories
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+var (
+	ErrUserNotFound          = errors.New("user not found")
+	ErrCinemaNotFound        = errors.New("cinema not found")
+	ErrCinemaInUse           = errors.New("cinema has schedules or staff")
+	ErrLocationNotFound      = errors.New("location not found")
+	ErrLocationInUse         = errors.New("location has schedules")
+	ErrInvalidCoordinates    = errors.New("latitude and longitude must be set together")
+	ErrPaymentMethodNotFound = errors.New("payment method not found")
+	ErrPaymentMethodInUse    = errors.New("payment method has orders")
+	ErrScreeningOverlap      = errors.New("screening overlaps another screening in the auditorium")
+	ErrAuditoriumNotFound    = errors.New("auditorium not found")
+)
+
+// AdminRepository keeps an auditorium blocked for cleaningBuffer after every screening
 type AdminRepository struct {
-	DB *pgxpool.Pool
+	DB             *pgxpool.Pool
+	cleaningBuffer time.Duration
 }
 
-func NewAdminRepository(db *pgxpool.Pool) *AdminRepository {
+func NewAdminRepository(db *pgxpool.Pool, cleaningBuffer time.Duration) *AdminRepository {
 	return &AdminRepository{
-		DB: db,
+		DB:             db,
+		cleaningBuffer: cleaningBuffer,
 	}
 }
 
@@ -48,6 +67,19 @@ func (r *AdminRepository) IsScheduleExists(ctx context.Context, scheduleID int)
 	return exist, nil
 }
 
+// helper
+func (r *AdminRepository) IsCinemaExists(ctx context.Context, cinemaID int) (bool, error) {
+	var exist bool
+	query := `SELECT EXISTS(SELECT 1 FROM cinemas WHERE id = $1)`
+	err := r.DB.QueryRow(ctx, query, cinemaID).Scan(&exist)
+	if err != nil {
+		log.Printf("ERROR \nCause :  %s", err)
+		return false, err
+	}
+
+	return exist, nil
+}
+
 func (r *AdminRepository) GetAllMovies(ctx context.Context, limit, offset int) ([]models.AdminMovies, int, error) {
 	var totalCount int
 	err := r.DB.QueryRow(ctx, "SELECT COUNT(*) FROM movies").Scan(&totalCount)
@@ -135,7 +167,7 @@ func (r *AdminRepository) GetMovieEditDetail(ctx context.Context, movieID int64)
 			(
 				SELECT json_agg(row_to_json(t))
 				FROM (
-					SELECT s.date, array_agg(s.time ORDER BY s.time) AS times
+					SELECT s.date, array_agg(to_char(s.time, 'HH24:MI') ORDER BY s.time) AS times
 					FROM schedules s
 					WHERE s.movie_id = m.id
 					GROUP BY s.date
@@ -287,15 +319,209 @@ func (r *AdminRepository) AddMovies(ctx context.Context, movie *models.AddMovies
 	return &movieData, nil
 }
 
+// insert cinemas schedule, without auditorium id it falls back to the first auditorium of the cinema
+const queryInsertCinemaSchedule = `
+	INSERT INTO cinemas_schedules (cinemas_id, schedules_id, locations_id, auditorium_id)
+	SELECT $1::int, $2::int, $3::int, a.id
+	FROM auditoriums a
+	WHERE a.cinemas_id = $1::int
+	  AND ($4::int = 0 OR a.id = $4::int)
+	ORDER BY a.id
+	LIMIT 1
+	RETURNING id
+	`
+
+// helper
+func insertCinemaSchedule(ctx context.Context, dbTx pgx.Tx, cs models.CinemaScheduleLocation, scheduleID int) (int, error) {
+	var cinemaScheduleID int
+	err := dbTx.QueryRow(ctx, queryInsertCinemaSchedule, cs.CinemaID, scheduleID, cs.LocationID, cs.AuditoriumID).Scan(&cinemaScheduleID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("%w for cinema %d", ErrAuditoriumNotFound, cs.CinemaID)
+	}
+	return cinemaScheduleID, err
+}
+
+// helper, a screening blocks its auditorium from the start time until the movie duration plus the cleaning buffer.
+// The auditoriums are locked so concurrent schedules are checked one after another, FOR NO KEY UPDATE does not
+// wait on the key share lock the inserted cinemas_schedules rows hold on them
+func (r *AdminRepository) checkScreeningOverlap(ctx context.Context, dbTx pgx.Tx, cinemaScheduleIDs []int) error {
+	if len(cinemaScheduleIDs) == 0 {
+		return nil
+	}
+
+	queryLock := `
+	SELECT id FROM auditoriums
+	WHERE id IN (SELECT auditorium_id FROM cinemas_schedules WHERE id = ANY($1))
+	ORDER BY id
+	FOR NO KEY UPDATE
+	`
+	if _, err := dbTx.Exec(ctx, queryLock, cinemaScheduleIDs); err != nil {
+		return err
+	}
+
+	query := `
+	WITH screenings AS (
+		SELECT
+			cs.id,
+			cs.auditorium_id,
+			a.name AS auditorium,
+			COALESCE(m.title, '') AS title,
+			s.date + s.time AS starts_at,
+			s.date + s.time + make_interval(mins => COALESCE(m.duration, 0) + $2) AS ends_at
+		FROM
+			cinemas_schedules cs
+			JOIN schedules s ON s.id = cs.schedules_id
+			JOIN auditoriums a ON a.id = cs.auditorium_id
+			LEFT JOIN movies m ON m.id = s.movie_id
+		WHERE
+			cs.auditorium_id IN (SELECT auditorium_id FROM cinemas_schedules WHERE id = ANY($1))
+	)
+	SELECT
+		n.auditorium,
+		n.title,
+		to_char(n.starts_at, 'YYYY-MM-DD HH24:MI'),
+		o.title,
+		to_char(o.starts_at, 'YYYY-MM-DD HH24:MI'),
+		to_char(o.ends_at, 'HH24:MI')
+	FROM
+		screenings n
+		JOIN screenings o ON o.auditorium_id = n.auditorium_id AND o.id <> n.id
+	WHERE
+		n.id = ANY($1)
+		AND n.starts_at < o.ends_at
+		AND o.starts_at < n.ends_at
+	ORDER BY
+		n.starts_at
+	LIMIT 1
+	`
+	var auditorium, title, startsAt, otherTitle, otherStartsAt, otherFreeAt string
+	err := dbTx.QueryRow(ctx, query, cinemaScheduleIDs, int(r.cleaningBuffer/time.Minute)).Scan(&auditorium, &title, &startsAt, &otherTitle, &otherStartsAt, &otherFreeAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s at %s in %s, %s starts at %s and the auditorium is free at %s",
+		ErrScreeningOverlap, title, startsAt, auditorium, otherTitle, otherStartsAt, otherFreeAt)
+}
+
+// AddCinemaSchedule runs schedules in auditoriums, a screening overlapping another one in the same auditorium is refused
 func (r *AdminRepository) AddCinemaSchedule(ctx context.Context, data []models.CinemaScheduleLocation) error {
-	query := "INSERT INTO cinemas_schedules (cinemas_id, schedules_id, locations_id) VALUES ($1, $2, $3)"
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer dbTx.Rollback(ctx)
+
+	cinemaScheduleIDs := []int{}
 	for _, item := range data {
-		_, err := r.DB.Exec(ctx, query, item.CinemaID, item.ScheduleID, item.LocationID)
+		cinemaScheduleID, err := insertCinemaSchedule(ctx, dbTx, item, item.ScheduleID)
 		if err != nil {
 			return err
 		}
+		cinemaScheduleIDs = append(cinemaScheduleIDs, cinemaScheduleID)
 	}
-	return nil
+
+	if err := r.checkScreeningOverlap(ctx, dbTx, cinemaScheduleIDs); err != nil {
+		return err
+	}
+
+	return dbTx.Commit(ctx)
+}
+
+func (r *AdminRepository) GetAuditoriums(ctx context.Context, cinemaID int) ([]models.Auditorium, error) {
+	query := `
+	SELECT id, cinemas_id, name, seat_rows, seat_columns, aisles
+	FROM auditoriums
+	WHERE cinemas_id = $1
+	ORDER BY id
+	`
+
+	rows, err := r.DB.Query(ctx, query, cinemaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var auditoriums []models.Auditorium
+	for rows.Next() {
+		var a models.Auditorium
+		err := rows.Scan(
+			&a.ID,
+			&a.CinemaID,
+			&a.Name,
+			&a.Rows,
+			&a.Columns,
+			&a.Aisles,
+		)
+		if err != nil {
+			return nil, err
+		}
+		auditoriums = append(auditoriums, a)
+	}
+	return auditoriums, nil
+}
+
+// create auditorium and generate the seat layout, rows are labelled A, B, C... and columns start from 1
+func (r *AdminRepository) AddAuditorium(ctx context.Context, cinemaID int, data *models.AddAuditorium) (*models.Auditorium, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin db transaction: %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	aisles := data.Aisles
+	if aisles == nil {
+		aisles = []int{}
+	}
+
+	auditorium := models.Auditorium{
+		CinemaID: cinemaID,
+		Name:     data.Name,
+		Rows:     data.Rows,
+		Columns:  data.Columns,
+		Aisles:   aisles,
+	}
+
+	queryAuditorium := `
+	INSERT INTO auditoriums (cinemas_id, name, seat_rows, seat_columns, aisles)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id
+	`
+	err = dbTx.QueryRow(ctx, queryAuditorium, cinemaID, data.Name, data.Rows, data.Columns, aisles).Scan(&auditorium.ID)
+	if err != nil {
+		return nil, fmt.Errorf("insert auditorium failed: %w", err)
+	}
+
+	seatTypes := map[string]string{}
+	for _, row := range data.VIPRows {
+		seatTypes[strings.ToUpper(row)] = models.SeatTypeVIP
+	}
+	for _, row := range data.LoveseatRows {
+		seatTypes[strings.ToUpper(row)] = models.SeatTypeLoveseat
+	}
+
+	querySeat := `INSERT INTO seats (auditorium_id, seat_number, seat_row, seat_column, seat_type) VALUES ($1, $2, $3, $4, $5)`
+	for i := 0; i < data.Rows; i++ {
+		row := string(rune('A' + i))
+		seatType, ok := seatTypes[row]
+		if !ok {
+			seatType = models.SeatTypeRegular
+		}
+		for col := 1; col <= data.Columns; col++ {
+			seatNumber := fmt.Sprintf("%s%d", row, col)
+			if _, err := dbTx.Exec(ctx, querySeat, auditorium.ID, seatNumber, row, col, seatType); err != nil {
+				return nil, fmt.Errorf("insert seat failed: %w", err)
+			}
+		}
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit db transaction failed: %w", err)
+	}
+
+	return &auditorium, nil
 }
 
 func (r *AdminRepository) UpdateMovies(ctx context.Context, id int, update models.EditMovies) error {
@@ -426,7 +652,7 @@ func (r *AdminRepository) UpdateMovies(ctx context.Context, id int, update model
 	}
 
 	// get old schedule
-	rows, err := dbTx.Query(ctx, "SELECT id, date::text, time::text FROM schedules WHERE movie_id=$1", id)
+	rows, err := dbTx.Query(ctx, "SELECT id, date::text, to_char(time, 'HH24:MI') FROM schedules WHERE movie_id=$1", id)
 	if err != nil {
 		return err
 	}
@@ -467,14 +693,34 @@ func (r *AdminRepository) UpdateMovies(ctx context.Context, id int, update model
 				continue
 			}
 
-			_, err := dbTx.Exec(ctx,
-				`INSERT INTO cinemas_schedules (cinemas_id, schedules_id, locations_id)
-                 VALUES ($1, $2, $3)`,
-				cs.CinemaID, scheduleID, cs.LocationID,
-			)
-			if err != nil {
+			if _, err := insertCinemaSchedule(ctx, dbTx, cs, scheduleID); err != nil {
+				return err
+			}
+		}
+	}
+
+	// a new start time or duration can make the screenings of the movie overlap others
+	if update.Duration != nil || update.Schedules != nil || update.CinemaSchedules != nil {
+		screeningRows, err := dbTx.Query(ctx, "SELECT cs.id FROM cinemas_schedules cs JOIN schedules s ON s.id = cs.schedules_id WHERE s.movie_id = $1", id)
+		if err != nil {
+			return err
+		}
+		cinemaScheduleIDs := []int{}
+		for screeningRows.Next() {
+			var cinemaScheduleID int
+			if err := screeningRows.Scan(&cinemaScheduleID); err != nil {
+				screeningRows.Close()
 				return err
 			}
+			cinemaScheduleIDs = append(cinemaScheduleIDs, cinemaScheduleID)
+		}
+		screeningRows.Close()
+		if err := screeningRows.Err(); err != nil {
+			return err
+		}
+
+		if err := r.checkScreeningOverlap(ctx, dbTx, cinemaScheduleIDs); err != nil {
+			return err
 		}
 	}
 
@@ -486,19 +732,21 @@ func (r *AdminRepository) UpdateMovies(ctx context.Context, id int, update model
 	return nil
 }
 
+// GetMovieSchedule lists the schedules, the end time is when the auditorium is free again after the cleaning buffer
 func (r *AdminRepository) GetMovieSchedule(ctx context.Context) ([]models.GetSchedule, error) {
 	query := `
 	SELECT 
 		s.id, 
-		s.date, s.
-		time, 
+		s.date,
+		to_char(s.time, 'HH24:MI'),
+		to_char(s.time + make_interval(mins => COALESCE(m.duration, 0) + $1), 'HH24:MI'),
 		s.movie_id, 
 		m.title AS movie_title
 	FROM schedules s
 	JOIN movies m  ON s.movie_id = m.id
 	`
 
-	rows, err := r.DB.Query(ctx, query)
+	rows, err := r.DB.Query(ctx, query, int(r.cleaningBuffer/time.Minute))
 	if err != nil {
 		return nil, err
 	}
@@ -511,6 +759,7 @@ func (r *AdminRepository) GetMovieSchedule(ctx context.Context) ([]models.GetSch
 			&sch.ID,
 			&sch.Date,
 			&sch.Time,
+			&sch.EndTime,
 			&sch.MovieID,
 			&sch.MovieName,
 		)
@@ -537,3 +786,444 @@ func (r *AdminRepository) DeleteMovies(ctx context.Context, movieID int) error {
 
 	return nil
 }
+
+const adminUserColumns = `
+	u.id,
+	u.email,
+	u.role,
+	u.cinema_id,
+	p.first_name,
+	p.last_name,
+	p.phone_number,
+	u.email_verified_at,
+	u.suspended_at,
+	u.suspended_reason,
+	u.deleted_at,
+	u.created_at
+`
+
+func scanAdminUser(row pgx.Row, user *models.AdminUser) error {
+	return row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.Role,
+		&user.CinemaID,
+		&user.FirstName,
+		&user.LastName,
+		&user.PhoneNumber,
+		&user.EmailVerifiedAt,
+		&user.SuspendedAt,
+		&user.SuspendedReason,
+		&user.DeletedAt,
+		&user.CreatedAt,
+	)
+}
+
+// GetUsers lists the users matching the filter, search matches the email, name and phone number
+func (r *AdminRepository) GetUsers(ctx context.Context, filter models.AdminUserFilter) ([]models.AdminUser, int, error) {
+	conditions := []string{}
+	args := []any{}
+
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conditions = append(conditions, fmt.Sprintf("(u.email ILIKE $%d OR CONCAT_WS(' ', p.first_name, p.last_name) ILIKE $%d OR p.phone_number ILIKE $%d)", len(args), len(args), len(args)))
+	}
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		conditions = append(conditions, fmt.Sprintf("u.role = $%d", len(args)))
+	}
+	switch filter.Status {
+	case "active":
+		conditions = append(conditions, "u.suspended_at IS NULL AND u.deleted_at IS NULL")
+	case "suspended":
+		conditions = append(conditions, "u.suspended_at IS NOT NULL")
+	case "deleted":
+		conditions = append(conditions, "u.deleted_at IS NOT NULL")
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var totalCount int
+	queryCount := fmt.Sprintf("SELECT COUNT(*) FROM users u LEFT JOIN profiles p ON p.user_id = u.id %s", where)
+	if err := r.DB.QueryRow(ctx, queryCount, args...).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+	SELECT %s
+	FROM users u
+	LEFT JOIN profiles p ON p.user_id = u.id
+	%s
+	ORDER BY u.id
+	LIMIT $%d OFFSET $%d
+	`, adminUserColumns, where, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []models.AdminUser{}
+	for rows.Next() {
+		var user models.AdminUser
+		if err := scanAdminUser(rows, &user); err != nil {
+			return nil, 0, err
+		}
+		users = append(users, user)
+	}
+
+	return users, totalCount, nil
+}
+
+func (r *AdminRepository) GetUser(ctx context.Context, userID int) (*models.AdminUser, error) {
+	query := fmt.Sprintf(`
+	SELECT %s
+	FROM users u
+	LEFT JOIN profiles p ON p.user_id = u.id
+	WHERE u.id = $1
+	`, adminUserColumns)
+
+	var user models.AdminUser
+	err := scanAdminUser(r.DB.QueryRow(ctx, query, userID), &user)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateUserRole changes the role and the cinema of the user, cinemaID is nil for roles that are not cinema scoped.
+// When the role changes, every token of the user is revoked in the same transaction, true is returned then
+func (r *AdminRepository) UpdateUserRole(ctx context.Context, userID int, role string, cinemaID *int) (bool, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	var oldRole string
+	err = dbTx.QueryRow(ctx, `SELECT role FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&oldRole)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, ErrUserNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+
+	query := `UPDATE users SET role = $2, cinema_id = $3, updated_at = NOW() WHERE id = $1`
+	if _, err := dbTx.Exec(ctx, query, userID, role, cinemaID); err != nil {
+		return false, err
+	}
+
+	// the role is part of the token, tokens with the old role must not work anymore
+	revoked := oldRole != role
+	if revoked {
+		if _, _, err := revokeAllUserTokens(ctx, dbTx, userID, models.SessionRevokedRoleChange); err != nil {
+			return false, err
+		}
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit db transaction failed : %w", err)
+	}
+	return revoked, nil
+}
+
+func (r *AdminRepository) GetCinemas(ctx context.Context) ([]models.AdminCinema, error) {
+	query := `
+	SELECT
+		c.id,
+		c.name,
+		COALESCE(c.prices, 0),
+		c.image_path,
+		(SELECT COUNT(*) FROM auditoriums a WHERE a.cinemas_id = c.id)
+	FROM
+		cinemas c
+	ORDER BY
+		c.id
+	`
+	rows, err := r.DB.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cinemas := []models.AdminCinema{}
+	for rows.Next() {
+		var cinema models.AdminCinema
+		if err := rows.Scan(&cinema.ID, &cinema.Name, &cinema.BasePrice, &cinema.ImagePath, &cinema.Auditoriums); err != nil {
+			return nil, err
+		}
+		cinemas = append(cinemas, cinema)
+	}
+	return cinemas, rows.Err()
+}
+
+func (r *AdminRepository) AddCinema(ctx context.Context, data *models.AddCinema) (*models.AdminCinema, error) {
+	query := `INSERT INTO cinemas (name, prices, image_path) VALUES ($1, $2, $3) RETURNING id, name, prices, image_path`
+
+	var cinema models.AdminCinema
+	err := r.DB.QueryRow(ctx, query, data.Name, data.BasePrice, data.ImagePath).Scan(&cinema.ID, &cinema.Name, &cinema.BasePrice, &cinema.ImagePath)
+	if err != nil {
+		return nil, err
+	}
+	return &cinema, nil
+}
+
+// UpdateCinema changes the fields that are set and returns the cinema with the replaced image path, nil when the image is kept
+func (r *AdminRepository) UpdateCinema(ctx context.Context, cinemaID int, update models.EditCinema) (*models.AdminCinema, *string, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	var oldImage *string
+	err = dbTx.QueryRow(ctx, `SELECT image_path FROM cinemas WHERE id = $1 FOR UPDATE`, cinemaID).Scan(&oldImage)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, ErrCinemaNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := `
+	UPDATE cinemas
+	SET
+		name = COALESCE($2, name),
+		prices = COALESCE($3, prices),
+		image_path = COALESCE($4, image_path)
+	WHERE
+		id = $1
+	RETURNING id, name, COALESCE(prices, 0), image_path, (SELECT COUNT(*) FROM auditoriums WHERE cinemas_id = $1)
+	`
+	var cinema models.AdminCinema
+	err = dbTx.QueryRow(ctx, query, cinemaID, update.Name, update.BasePrice, update.ImagePath).Scan(&cinema.ID, &cinema.Name, &cinema.BasePrice, &cinema.ImagePath, &cinema.Auditoriums)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("commit db transaction failed : %w", err)
+	}
+
+	if update.ImagePath == nil {
+		oldImage = nil
+	}
+	return &cinema, oldImage, nil
+}
+
+// DeleteCinema removes a cinema without schedules or staff with its auditoriums, the image path is returned
+func (r *AdminRepository) DeleteCinema(ctx context.Context, cinemaID int) (*string, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	// schedules cascade to their orders, they are never deleted with the cinema
+	query := `
+	SELECT
+		image_path,
+		EXISTS (SELECT 1 FROM cinemas_schedules WHERE cinemas_id = $1) OR EXISTS (SELECT 1 FROM users WHERE cinema_id = $1)
+	FROM
+		cinemas
+	WHERE
+		id = $1
+	FOR UPDATE
+	`
+	var imagePath *string
+	var inUse bool
+	err = dbTx.QueryRow(ctx, query, cinemaID).Scan(&imagePath, &inUse)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCinemaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if inUse {
+		return nil, ErrCinemaInUse
+	}
+
+	if _, err := dbTx.Exec(ctx, `DELETE FROM cinemas WHERE id = $1`, cinemaID); err != nil {
+		return nil, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit db transaction failed : %w", err)
+	}
+	return imagePath, nil
+}
+
+// helper
+func locationWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == "locations_coordinates_check" {
+		return ErrInvalidCoordinates
+	}
+	return err
+}
+
+func (r *AdminRepository) GetLocations(ctx context.Context) ([]models.Location, error) {
+	rows, err := r.DB.Query(ctx, `SELECT id, name, address, latitude, longitude FROM locations ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locations := []models.Location{}
+	for rows.Next() {
+		var location models.Location
+		if err := rows.Scan(&location.ID, &location.City, &location.Address, &location.Latitude, &location.Longitude); err != nil {
+			return nil, err
+		}
+		locations = append(locations, location)
+	}
+	return locations, rows.Err()
+}
+
+func (r *AdminRepository) AddLocation(ctx context.Context, data *models.AddLocation) (*models.Location, error) {
+	query := `
+	INSERT INTO locations (name, address, latitude, longitude)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, name, address, latitude, longitude
+	`
+	var location models.Location
+	err := r.DB.QueryRow(ctx, query, data.City, data.Address, data.Latitude, data.Longitude).Scan(&location.ID, &location.City, &location.Address, &location.Latitude, &location.Longitude)
+	if err != nil {
+		return nil, locationWriteError(err)
+	}
+	return &location, nil
+}
+
+func (r *AdminRepository) UpdateLocation(ctx context.Context, locationID int, update models.EditLocation) (*models.Location, error) {
+	query := `
+	UPDATE locations
+	SET
+		name = COALESCE($2, name),
+		address = COALESCE($3, address),
+		latitude = COALESCE($4, latitude),
+		longitude = COALESCE($5, longitude)
+	WHERE
+		id = $1
+	RETURNING id, name, address, latitude, longitude
+	`
+	var location models.Location
+	err := r.DB.QueryRow(ctx, query, locationID, update.City, update.Address, update.Latitude, update.Longitude).Scan(&location.ID, &location.City, &location.Address, &location.Latitude, &location.Longitude)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrLocationNotFound
+	}
+	if err != nil {
+		return nil, locationWriteError(err)
+	}
+	return &location, nil
+}
+
+// DeleteLocation removes a location without schedules
+func (r *AdminRepository) DeleteLocation(ctx context.Context, locationID int) error {
+	query := `DELETE FROM locations WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM cinemas_schedules WHERE locations_id = $1)`
+	cmd, err := r.DB.Exec(ctx, query, locationID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() > 0 {
+		return nil
+	}
+
+	var exist bool
+	if err := r.DB.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM locations WHERE id = $1)`, locationID).Scan(&exist); err != nil {
+		return err
+	}
+	if !exist {
+		return ErrLocationNotFound
+	}
+	return ErrLocationInUse
+}
+
+func (r *AdminRepository) GetPaymentMethods(ctx context.Context) ([]models.PaymentMethod, error) {
+	rows, err := r.DB.Query(ctx, `SELECT id, name, provider, fee, is_active FROM payment_methods ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	methods := []models.PaymentMethod{}
+	for rows.Next() {
+		var method models.PaymentMethod
+		if err := rows.Scan(&method.ID, &method.Name, &method.Provider, &method.Fee, &method.IsActive); err != nil {
+			return nil, err
+		}
+		methods = append(methods, method)
+	}
+	return methods, rows.Err()
+}
+
+func (r *AdminRepository) AddPaymentMethod(ctx context.Context, data *models.AddPaymentMethod) (*models.PaymentMethod, error) {
+	isActive := true
+	if data.IsActive != nil {
+		isActive = *data.IsActive
+	}
+
+	query := `
+	INSERT INTO payment_methods (name, provider, fee, is_active)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, name, provider, fee, is_active
+	`
+	var method models.PaymentMethod
+	err := r.DB.QueryRow(ctx, query, data.Name, data.Provider, data.Fee, isActive).Scan(&method.ID, &method.Name, &method.Provider, &method.Fee, &method.IsActive)
+	if err != nil {
+		return nil, err
+	}
+	return &method, nil
+}
+
+func (r *AdminRepository) UpdatePaymentMethod(ctx context.Context, paymentMethodID int, update models.EditPaymentMethod) (*models.PaymentMethod, error) {
+	query := `
+	UPDATE payment_methods
+	SET
+		name = COALESCE($2, name),
+		provider = COALESCE($3, provider),
+		fee = COALESCE($4, fee),
+		is_active = COALESCE($5, is_active)
+	WHERE
+		id = $1
+	RETURNING id, name, provider, fee, is_active
+	`
+	var method models.PaymentMethod
+	err := r.DB.QueryRow(ctx, query, paymentMethodID, update.Name, update.Provider, update.Fee, update.IsActive).Scan(&method.ID, &method.Name, &method.Provider, &method.Fee, &method.IsActive)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPaymentMethodNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &method, nil
+}
+
+// DeletePaymentMethod removes a payment method never used by an order, used ones are disabled instead
+func (r *AdminRepository) DeletePaymentMethod(ctx context.Context, paymentMethodID int) error {
+	query := `DELETE FROM payment_methods WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM orders WHERE payment_method_id = $1)`
+	cmd, err := r.DB.Exec(ctx, query, paymentMethodID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() > 0 {
+		return nil
+	}
+
+	var exist bool
+	if err := r.DB.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM payment_methods WHERE id = $1)`, paymentMethodID).Scan(&exist); err != nil {
+		return err
+	}
+	if !exist {
+		return ErrPaymentMethodNotFound
+	}
+	return ErrPaymentMethodInUse
+}
@@ -0,0 +1,459 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrGenreNotFound     = errors.New("genre not found")
+	ErrGenreExists       = errors.New("genre already exists")
+	ErrGenreInUse        = errors.New("genre is used by movies")
+	ErrPersonNotFound    = errors.New("person not found")
+	ErrPersonInUse       = errors.New("person is credited on movies")
+	ErrMergeIntoItself   = errors.New("can not merge into itself")
+	ErrMergeSourceGone   = errors.New("one or more merge sources not found")
+	errUnknownPersonKind = errors.New("unknown person kind")
+)
+
+// CatalogRepository manages the genres, casts and directors movies refer to
+type CatalogRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewCatalogRepository(db *pgxpool.Pool) *CatalogRepository {
+	return &CatalogRepository{
+		DB: db,
+	}
+}
+
+// how many movies credit a person, by kind
+var personMoviesCount = map[string]string{
+	models.PersonCast:     `(SELECT COUNT(*) FROM movies_cast mc WHERE mc.cast_id = p.id)`,
+	models.PersonDirector: `(SELECT COUNT(*) FROM movies m WHERE m.director_id = p.id)`,
+}
+
+// helper, the kind is used as table name so it must be known
+func personTable(kind string) (string, error) {
+	if _, ok := personMoviesCount[kind]; !ok {
+		return "", errUnknownPersonKind
+	}
+	return kind, nil
+}
+
+func (r *CatalogRepository) GetGenres(ctx context.Context, filter models.CatalogFilter) ([]models.Genre, int, error) {
+	var totalCount int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM genres WHERE name ILIKE '%' || $1 || '%'`, filter.Search).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+	SELECT
+		g.id,
+		g.name,
+		(SELECT COUNT(*) FROM movies_genres mg WHERE mg.genre_id = g.id)
+	FROM
+		genres g
+	WHERE
+		g.name ILIKE '%' || $1 || '%'
+	ORDER BY
+		g.name,
+		g.id
+	LIMIT $2 OFFSET $3
+	`
+	rows, err := r.DB.Query(ctx, query, filter.Search, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	genres := []models.Genre{}
+	for rows.Next() {
+		var genre models.Genre
+		if err := rows.Scan(&genre.ID, &genre.Name, &genre.Movies); err != nil {
+			return nil, 0, err
+		}
+		genres = append(genres, genre)
+	}
+	return genres, totalCount, rows.Err()
+}
+
+// helper
+func genreWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == "genres_name_key" {
+		return ErrGenreExists
+	}
+	return err
+}
+
+func (r *CatalogRepository) AddGenre(ctx context.Context, name string) (*models.Genre, error) {
+	var genre models.Genre
+	err := r.DB.QueryRow(ctx, `INSERT INTO genres (name) VALUES ($1) RETURNING id, name`, name).Scan(&genre.ID, &genre.Name)
+	if err != nil {
+		return nil, genreWriteError(err)
+	}
+	return &genre, nil
+}
+
+func (r *CatalogRepository) UpdateGenre(ctx context.Context, genreID int, name string) (*models.Genre, error) {
+	query := `
+	UPDATE genres SET name = $2 WHERE id = $1
+	RETURNING id, name, (SELECT COUNT(*) FROM movies_genres WHERE genre_id = $1)
+	`
+	var genre models.Genre
+	err := r.DB.QueryRow(ctx, query, genreID, name).Scan(&genre.ID, &genre.Name, &genre.Movies)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrGenreNotFound
+	}
+	if err != nil {
+		return nil, genreWriteError(err)
+	}
+	return &genre, nil
+}
+
+// DeleteGenre removes a genre no movie uses, used genres are merged instead
+func (r *CatalogRepository) DeleteGenre(ctx context.Context, genreID int) error {
+	query := `DELETE FROM genres WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM movies_genres WHERE genre_id = $1)`
+	cmd, err := r.DB.Exec(ctx, query, genreID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() > 0 {
+		return nil
+	}
+
+	var exist bool
+	if err := r.DB.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM genres WHERE id = $1)`, genreID).Scan(&exist); err != nil {
+		return err
+	}
+	if !exist {
+		return ErrGenreNotFound
+	}
+	return ErrGenreInUse
+}
+
+// helper, locks the merge target and the sources, every id must exist
+func lockMergeRows(ctx context.Context, dbTx pgx.Tx, table string, targetID int, sourceIDs []int, notFound error) error {
+	if slices.Contains(sourceIDs, targetID) {
+		return ErrMergeIntoItself
+	}
+
+	ids := append([]int{targetID}, sourceIDs...)
+	rows, err := dbTx.Query(ctx, fmt.Sprintf(`SELECT id FROM %s WHERE id = ANY($1) ORDER BY id FOR UPDATE`, table), ids)
+	if err != nil {
+		return err
+	}
+	found := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		found = append(found, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if !slices.Contains(found, targetID) {
+		return notFound
+	}
+	for _, id := range sourceIDs {
+		if !slices.Contains(found, id) {
+			return ErrMergeSourceGone
+		}
+	}
+	return nil
+}
+
+// MergeGenres moves the movies of the source genres to the target and deletes the sources
+func (r *CatalogRepository) MergeGenres(ctx context.Context, targetID int, sourceIDs []int) (*models.Genre, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	if err := lockMergeRows(ctx, dbTx, "genres", targetID, sourceIDs, ErrGenreNotFound); err != nil {
+		return nil, err
+	}
+
+	queryMovies := `
+	INSERT INTO movies_genres (movie_id, genre_id)
+	SELECT DISTINCT movie_id, $1::int FROM movies_genres WHERE genre_id = ANY($2)
+	ON CONFLICT DO NOTHING
+	`
+	if _, err := dbTx.Exec(ctx, queryMovies, targetID, sourceIDs); err != nil {
+		return nil, err
+	}
+	// the links of the sources are removed with them
+	if _, err := dbTx.Exec(ctx, `DELETE FROM genres WHERE id = ANY($1)`, sourceIDs); err != nil {
+		return nil, err
+	}
+
+	var genre models.Genre
+	query := `SELECT id, name, (SELECT COUNT(*) FROM movies_genres WHERE genre_id = $1) FROM genres WHERE id = $1`
+	if err := dbTx.QueryRow(ctx, query, targetID).Scan(&genre.ID, &genre.Name, &genre.Movies); err != nil {
+		return nil, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit db transaction failed : %w", err)
+	}
+	return &genre, nil
+}
+
+// GetPeople returns a page of the casts or directors matching the search, by name
+func (r *CatalogRepository) GetPeople(ctx context.Context, kind string, filter models.CatalogFilter) ([]models.Person, int, error) {
+	table, err := personTable(kind)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var totalCount int
+	queryCount := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE name ILIKE '%%' || $1 || '%%'`, table)
+	if err := r.DB.QueryRow(ctx, queryCount, filter.Search).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+	SELECT
+		p.id,
+		p.name,
+		p.photo_path,
+		p.bio,
+		%s
+	FROM
+		%s p
+	WHERE
+		p.name ILIKE '%%' || $1 || '%%'
+	ORDER BY
+		p.name,
+		p.id
+	LIMIT $2 OFFSET $3
+	`, personMoviesCount[kind], table)
+	rows, err := r.DB.Query(ctx, query, filter.Search, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	people := []models.Person{}
+	for rows.Next() {
+		var person models.Person
+		if err := rows.Scan(&person.ID, &person.Name, &person.PhotoPath, &person.Bio, &person.Movies); err != nil {
+			return nil, 0, err
+		}
+		people = append(people, person)
+	}
+	return people, totalCount, rows.Err()
+}
+
+// helper
+func getPerson(ctx context.Context, q pgx.Tx, kind string, personID int) (*models.Person, error) {
+	query := fmt.Sprintf(`SELECT p.id, p.name, p.photo_path, p.bio, %s FROM %s p WHERE p.id = $1`, personMoviesCount[kind], kind)
+
+	var person models.Person
+	err := q.QueryRow(ctx, query, personID).Scan(&person.ID, &person.Name, &person.PhotoPath, &person.Bio, &person.Movies)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPersonNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+func (r *CatalogRepository) AddPerson(ctx context.Context, kind string, data *models.AddPerson) (*models.Person, error) {
+	table, err := personTable(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (name, bio, photo_path) VALUES ($1, NULLIF($2, ''), $3) RETURNING id, name, photo_path, bio`, table)
+	var person models.Person
+	err = r.DB.QueryRow(ctx, query, data.Name, data.Bio, data.PhotoPath).Scan(&person.ID, &person.Name, &person.PhotoPath, &person.Bio)
+	if err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+// UpdatePerson changes the fields that are set and returns the person with the replaced photo path, nil when the photo is kept.
+// An empty bio removes it
+func (r *CatalogRepository) UpdatePerson(ctx context.Context, kind string, personID int, update models.EditPerson) (*models.Person, *string, error) {
+	table, err := personTable(kind)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	var oldPhoto *string
+	err = dbTx.QueryRow(ctx, fmt.Sprintf(`SELECT photo_path FROM %s WHERE id = $1 FOR UPDATE`, table), personID).Scan(&oldPhoto)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, ErrPersonNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := fmt.Sprintf(`
+	UPDATE %s
+	SET
+		name = COALESCE($2, name),
+		bio = CASE WHEN $3::text IS NULL THEN bio ELSE NULLIF($3::text, '') END,
+		photo_path = COALESCE($4, photo_path)
+	WHERE
+		id = $1
+	`, table)
+	if _, err := dbTx.Exec(ctx, query, personID, update.Name, update.Bio, update.PhotoPath); err != nil {
+		return nil, nil, err
+	}
+
+	person, err := getPerson(ctx, dbTx, kind, personID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("commit db transaction failed : %w", err)
+	}
+
+	if update.PhotoPath == nil {
+		oldPhoto = nil
+	}
+	return person, oldPhoto, nil
+}
+
+// DeletePerson removes a person no movie credits and returns the photo path, credited people are merged instead
+func (r *CatalogRepository) DeletePerson(ctx context.Context, kind string, personID int) (*string, error) {
+	table, err := personTable(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	person, err := getPerson(ctx, dbTx, kind, personID)
+	if err != nil {
+		return nil, err
+	}
+	if person.Movies > 0 {
+		return nil, ErrPersonInUse
+	}
+
+	if _, err := dbTx.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, table), personID); err != nil {
+		return nil, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit db transaction failed : %w", err)
+	}
+	return person.PhotoPath, nil
+}
+
+// MergePeople moves the credits of the sources to the target and deletes the sources. The target keeps its name,
+// a missing photo or bio is taken from a source. The photos not kept are returned
+func (r *CatalogRepository) MergePeople(ctx context.Context, kind string, targetID int, sourceIDs []int) (*models.Person, []string, error) {
+	table, err := personTable(kind)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	if err := lockMergeRows(ctx, dbTx, table, targetID, sourceIDs, ErrPersonNotFound); err != nil {
+		return nil, nil, err
+	}
+
+	switch kind {
+	case models.PersonCast:
+		queryMovies := `
+		INSERT INTO movies_cast (movie_id, cast_id)
+		SELECT DISTINCT movie_id, $1::int FROM movies_cast WHERE cast_id = ANY($2)
+		ON CONFLICT DO NOTHING
+		`
+		if _, err := dbTx.Exec(ctx, queryMovies, targetID, sourceIDs); err != nil {
+			return nil, nil, err
+		}
+	case models.PersonDirector:
+		if _, err := dbTx.Exec(ctx, `UPDATE movies SET director_id = $1 WHERE director_id = ANY($2)`, targetID, sourceIDs); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	queryDetails := fmt.Sprintf(`
+	UPDATE %[1]s t
+	SET
+		photo_path = COALESCE(t.photo_path, (SELECT s.photo_path FROM %[1]s s WHERE s.id = ANY($2) AND s.photo_path IS NOT NULL ORDER BY s.id LIMIT 1)),
+		bio = COALESCE(t.bio, (SELECT s.bio FROM %[1]s s WHERE s.id = ANY($2) AND s.bio IS NOT NULL ORDER BY s.id LIMIT 1))
+	WHERE
+		t.id = $1
+	`, table)
+	if _, err := dbTx.Exec(ctx, queryDetails, targetID, sourceIDs); err != nil {
+		return nil, nil, err
+	}
+
+	// the cast links of the sources are removed with them
+	queryDelete := fmt.Sprintf(`DELETE FROM %s WHERE id = ANY($1) RETURNING photo_path`, table)
+	rows, err := dbTx.Query(ctx, queryDelete, sourceIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	photos := []string{}
+	for rows.Next() {
+		var photo *string
+		if err := rows.Scan(&photo); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		if photo != nil {
+			photos = append(photos, *photo)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	person, err := getPerson(ctx, dbTx, kind, targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("commit db transaction failed : %w", err)
+	}
+
+	// the photo taken over by the target is still used
+	unused := []string{}
+	for _, photo := range photos {
+		if person.PhotoPath == nil || *person.PhotoPath != photo {
+			unused = append(unused, photo)
+		}
+	}
+	return person, unused, nil
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrTicketAlreadyUsed = errors.New("ticket already used")
+
+type CheckInRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewCheckInRepository(db *pgxpool.Pool) *CheckInRepository {
+	return &CheckInRepository{
+		DB: db,
+	}
+}
+
+func (r *CheckInRepository) GetCheckInTicket(ctx context.Context, orderID int) (*models.CheckInTicket, error) {
+	query := `
+	SELECT
+		o.id,
+		COALESCE(o.qr_code, ''),
+		o.payment_status,
+		c.id,
+		c.name,
+		COALESCE(a.name, ''),
+		cs.id,
+		m.title,
+		sch.date::text || ' ' || to_char(sch.time, 'HH24:MI'),
+		COALESCE(ARRAY_AGG(s.seat_number ORDER BY s.seat_row, s.seat_column) FILTER (WHERE s.id IS NOT NULL), '{}'),
+		o.checked_in_at,
+		EXTRACT(EPOCH FROM ((sch.date + sch.time) - NOW()::timestamp))::float8
+	FROM
+		orders o
+		JOIN cinemas_schedules cs ON o.cinemas_schedule_id = cs.id
+		JOIN cinemas c ON cs.cinemas_id = c.id
+		JOIN schedules sch ON cs.schedules_id = sch.id
+		JOIN movies m ON sch.movie_id = m.id
+		LEFT JOIN auditoriums a ON cs.auditorium_id = a.id
+		LEFT JOIN orders_seats os ON os.order_id = o.id
+		LEFT JOIN seats s ON os.seat_id = s.id
+	WHERE
+		o.id = $1
+	GROUP BY
+		o.id,
+		c.id,
+		a.name,
+		cs.id,
+		m.title,
+		sch.date,
+		sch.time
+	`
+
+	var ticket models.CheckInTicket
+	var untilShowtime float64
+	err := r.DB.QueryRow(ctx, query, orderID).Scan(
+		&ticket.OrderID,
+		&ticket.QRCode,
+		&ticket.PaymentStatus,
+		&ticket.CinemaID,
+		&ticket.Cinema,
+		&ticket.Auditorium,
+		&ticket.CinemasScheduleID,
+		&ticket.Title,
+		&ticket.Showtime,
+		&ticket.SeatNumbers,
+		&ticket.CheckedInAt,
+		&untilShowtime,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	ticket.UntilShowtime = time.Duration(untilShowtime * float64(time.Second))
+
+	return &ticket, nil
+}
+
+// CheckIn marks a paid ticket as used, only the first scan succeeds so concurrent scans can not both pass
+func (r *CheckInRepository) CheckIn(ctx context.Context, orderID, staffID int) (time.Time, error) {
+	query := `
+	UPDATE orders
+	SET
+		checked_in_at = NOW(),
+		checked_in_by = $2,
+		isactive = false,
+		updated_at = NOW()
+	WHERE
+		id = $1
+		AND payment_status = 'paid'
+		AND checked_in_at IS NULL
+	RETURNING checked_in_at
+	`
+
+	var checkedInAt time.Time
+	err := r.DB.QueryRow(ctx, query, orderID, staffID).Scan(&checkedInAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, ErrTicketAlreadyUsed
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return checkedInAt, nil
+}
@@ -78,41 +78,81 @@ func (r *CinemaRepository) IsCinemaScheduleExists(ctx context.Context, cinemaSch
 	return exist, nil
 }
 
-func (r *CinemaRepository) GetAvailableSeats(ctx context.Context, cinemaScheduleID int) ([]models.CinemaSeat, error) {
+func (r *CinemaRepository) GetSeatLayout(ctx context.Context, cinemaScheduleID int) (*models.SeatLayout, error) {
+	queryAuditorium := `
+	SELECT
+		a.id,
+		a.cinemas_id,
+		a.name,
+		a.seat_rows,
+		a.seat_columns,
+		a.aisles
+	FROM
+		cinemas_schedules cs
+		JOIN auditoriums a ON cs.auditorium_id = a.id
+	WHERE
+		cs.id = $1
+	`
 
-	query := `
+	layout := models.SeatLayout{CinemaScheduleID: cinemaScheduleID}
+	err := r.DB.QueryRow(ctx, queryAuditorium, cinemaScheduleID).Scan(
+		&layout.Auditorium.ID,
+		&layout.Auditorium.CinemaID,
+		&layout.Auditorium.Name,
+		&layout.Auditorium.Rows,
+		&layout.Auditorium.Columns,
+		&layout.Auditorium.Aisles,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	querySeats := `
 	SELECT
-		os.seat_id,
+		s.id,
 		s.seat_number,
-		s.seat_type
+		s.seat_row,
+		s.seat_column,
+		s.seat_type,
+		CASE WHEN booked.seat_id IS NULL THEN 'available' ELSE 'booked' END AS status
 	FROM
-		orders_seats os
-		JOIN seats s ON os.seat_id = s.id
-		JOIN orders o ON os.order_id = o.id
+		seats s
+		LEFT JOIN (
+			SELECT DISTINCT os.seat_id
+			FROM orders_seats os
+			JOIN orders o ON os.order_id = o.id
+			WHERE o.cinemas_schedule_id = $1
+				AND os.status = 'booked'
+		) booked ON booked.seat_id = s.id
 	WHERE
-		o.cinemas_schedule_id = $1
-		AND os.status = 'booked'
+		s.auditorium_id = $2
+	ORDER BY
+		s.seat_row ASC,
+		s.seat_column ASC
 	`
 
-	rows, err := r.DB.Query(ctx, query, cinemaScheduleID)
+	rows, err := r.DB.Query(ctx, querySeats, cinemaScheduleID, layout.Auditorium.ID)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	var seats []models.CinemaSeat
 	for rows.Next() {
 		var s models.CinemaSeat
 		err := rows.Scan(
 			&s.SeatID,
 			&s.SeatNumber,
+			&s.Row,
+			&s.Column,
 			&s.SeatType,
+			&s.Status,
 		)
 		if err != nil {
 			return nil, err
 		}
-		seats = append(seats, s)
+		layout.Seats = append(layout.Seats, s)
 	}
-	return seats, nil
+	return &layout, nil
 }
 
 func (r *CinemaRepository) GetScheduleFilter(ctx context.Context, movieID int, locationFilter *string, dateFilter *time.Time, timeFilter *string, limit, offset int) ([]models.GetFilterSchedules, int, error) {
@@ -124,7 +164,7 @@ func (r *CinemaRepository) GetScheduleFilter(ctx context.Context, movieID int, l
     WHERE s.movie_id = $1
       AND ($2::text IS NULL OR l.name = $2::text)
       AND ($3::date IS NULL OR s.date = $3::date)
-      AND ($4::show_time IS NULL OR s.time = $4::show_time)
+      AND ($4::time IS NULL OR s.time = $4::time)
     `
 	var totalCount int
 	err := r.DB.QueryRow(ctx, countQuery, movieID, locationFilter, dateFilter, timeFilter).Scan(&totalCount)
@@ -139,7 +179,7 @@ func (r *CinemaRepository) GetScheduleFilter(ctx context.Context, movieID int, l
 		c.prices AS ticket_price,
 		l.name AS location_name,
 		s.date AS schedule_date,
-		s.time AS schedule_time,
+		to_char(s.time, 'HH24:MI') AS schedule_time,
 		m.title AS movie_title
 	FROM
     	cinemas_schedules cs
@@ -155,7 +195,7 @@ func (r *CinemaRepository) GetScheduleFilter(ctx context.Context, movieID int, l
 		s.movie_id = $1
 		AND ($2::text IS NULL OR l.name = $2::text)
 		AND ($3::date IS NULL OR s.date = $3::date)
-		AND ($4::show_time IS NULL OR s.time = $4::show_time)
+		AND ($4::time IS NULL OR s.time = $4::time)
 	ORDER BY
     	s.time ASC
 	LIMIT $5 OFFSET $6
@@ -0,0 +1,316 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// seat holds live in redis, one hash per schedule of seat id -> "user id:expiry in unix ms".
+// Postgres is used when redis is unavailable, the holds written there are still checked once redis is back.
+// Both paths hold the schedule row lock while checking and writing, so a hold written to postgres is never
+// missed by a concurrent redis hold
+type SeatHoldRepository struct {
+	DB  *pgxpool.Pool
+	RDB *redis.Client
+}
+
+func NewSeatHoldRepository(db *pgxpool.Pool, rdb *redis.Client) *SeatHoldRepository {
+	return &SeatHoldRepository{
+		DB:  db,
+		RDB: rdb,
+	}
+}
+
+// ARGV: user id, now ms, ttl ms, seat ids. Returns the seat ids held by another user, when there is none
+// the seats are held, the old selection of the user and the expired holds are dropped
+var holdSeatsScript = redis.NewScript(`
+local now = tonumber(ARGV[2])
+local wanted = {}
+local conflicts = {}
+for i = 4, #ARGV do
+	wanted[ARGV[i]] = true
+	local value = redis.call('HGET', KEYS[1], ARGV[i])
+	if value then
+		local owner, expires = string.match(value, '^(%d+):(%d+)$')
+		if owner ~= ARGV[1] and tonumber(expires) > now then
+			table.insert(conflicts, tonumber(ARGV[i]))
+		end
+	end
+end
+if #conflicts > 0 then
+	return conflicts
+end
+local held = redis.call('HGETALL', KEYS[1])
+for i = 1, #held, 2 do
+	local owner, expires = string.match(held[i + 1], '^(%d+):(%d+)$')
+	if tonumber(expires) <= now or (owner == ARGV[1] and not wanted[held[i]]) then
+		redis.call('HDEL', KEYS[1], held[i])
+	end
+end
+local value = ARGV[1] .. ':' .. (now + tonumber(ARGV[3]))
+for seat in pairs(wanted) do
+	redis.call('HSET', KEYS[1], seat, value)
+end
+if redis.call('PTTL', KEYS[1]) < tonumber(ARGV[3]) then
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+return conflicts
+`)
+
+// ARGV: user id, seat ids. Deletes only the holds owned by the user, all of them when no seat id is given
+var releaseSeatsScript = redis.NewScript(`
+local released = 0
+local seats = {}
+if #ARGV == 1 then
+	local held = redis.call('HGETALL', KEYS[1])
+	for i = 1, #held, 2 do
+		table.insert(seats, held[i])
+	end
+else
+	for i = 2, #ARGV do
+		table.insert(seats, ARGV[i])
+	end
+end
+for _, seat in ipairs(seats) do
+	local value = redis.call('HGET', KEYS[1], seat)
+	if value and string.match(value, '^(%d+):') == ARGV[1] then
+		redis.call('HDEL', KEYS[1], seat)
+		released = released + 1
+	end
+end
+return released
+`)
+
+func seatHoldsKey(cinemaScheduleID int) string {
+	return fmt.Sprintf("holds:schedule=%d", cinemaScheduleID)
+}
+
+// HoldSeats holds the seats for the user and replaces the previous selection of the user on the same schedule.
+// It returns the seat ids held by another user, in that case nothing is held.
+func (r *SeatHoldRepository) HoldSeats(ctx context.Context, cinemaScheduleID, userID int, seatIDs []int, ttl time.Duration) ([]int, error) {
+	if r.RDB != nil {
+		conflicts, err := r.holdSeatsRedis(ctx, cinemaScheduleID, userID, seatIDs, ttl)
+		if err == nil {
+			return conflicts, nil
+		}
+		log.Println("Redis hold seats error, back to DB : ", err)
+	}
+
+	return r.holdSeatsDB(ctx, cinemaScheduleID, userID, seatIDs, ttl)
+}
+
+// helper, serializes the holds of a schedule until the transaction ends. NO KEY UPDATE does not block the
+// orders referencing the schedule
+func lockScheduleHolds(ctx context.Context, dbTx pgx.Tx, cinemaScheduleID int) error {
+	_, err := dbTx.Exec(ctx, `SELECT id FROM cinemas_schedules WHERE id = $1 FOR NO KEY UPDATE`, cinemaScheduleID)
+	return err
+}
+
+func (r *SeatHoldRepository) holdSeatsRedis(ctx context.Context, cinemaScheduleID, userID int, seatIDs []int, ttl time.Duration) ([]int, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	if err := lockScheduleHolds(ctx, dbTx, cinemaScheduleID); err != nil {
+		return nil, err
+	}
+
+	// holds written to postgres while redis was down still count
+	queryConflicts := `
+	SELECT seat_id FROM seat_holds
+	WHERE cinemas_schedule_id = $1 AND seat_id = ANY($2) AND user_id <> $3 AND expires_at > NOW()
+	`
+	rows, err := dbTx.Query(ctx, queryConflicts, cinemaScheduleID, seatIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	conflicts := []int{}
+	for rows.Next() {
+		var seatID int
+		if err := rows.Scan(&seatID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		conflicts = append(conflicts, seatID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return conflicts, nil
+	}
+
+	args := []any{userID, time.Now().UnixMilli(), ttl.Milliseconds()}
+	for _, seatID := range seatIDs {
+		args = append(args, seatID)
+	}
+	held, err := holdSeatsScript.Run(ctx, r.RDB, []string{seatHoldsKey(cinemaScheduleID)}, args...).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	for _, seatID := range held {
+		conflicts = append(conflicts, int(seatID))
+	}
+	if len(conflicts) > 0 {
+		return conflicts, nil
+	}
+
+	// the selection now lives in redis
+	_, err = dbTx.Exec(ctx, `DELETE FROM seat_holds WHERE cinemas_schedule_id = $1 AND user_id = $2`, cinemaScheduleID, userID)
+	if err == nil {
+		err = dbTx.Commit(ctx)
+	}
+	if err != nil {
+		log.Println("Delete fallback seat holds error:", err)
+	}
+	return nil, nil
+}
+
+func (r *SeatHoldRepository) holdSeatsDB(ctx context.Context, cinemaScheduleID, userID int, seatIDs []int, ttl time.Duration) ([]int, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	// a redis hold of another instance is not visible here, the booking still refuses a seat sold twice
+	if err := lockScheduleHolds(ctx, dbTx, cinemaScheduleID); err != nil {
+		return nil, err
+	}
+
+	// expired holds are released automatically
+	_, err = dbTx.Exec(ctx, `DELETE FROM seat_holds WHERE cinemas_schedule_id = $1 AND expires_at <= NOW()`, cinemaScheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	queryHold := `
+	INSERT INTO seat_holds (cinemas_schedule_id, seat_id, user_id, expires_at)
+	VALUES ($1, $2, $3, NOW() + $4::interval)
+	ON CONFLICT (cinemas_schedule_id, seat_id)
+	DO UPDATE SET expires_at = EXCLUDED.expires_at
+	WHERE seat_holds.user_id = EXCLUDED.user_id
+	`
+
+	interval := fmt.Sprintf("%d milliseconds", ttl.Milliseconds())
+	conflicts := []int{}
+	for _, seatID := range seatIDs {
+		cmd, err := dbTx.Exec(ctx, queryHold, cinemaScheduleID, seatID, userID, interval)
+		if err != nil {
+			return nil, err
+		}
+		if cmd.RowsAffected() == 0 {
+			conflicts = append(conflicts, seatID)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return conflicts, nil
+	}
+
+	_, err = dbTx.Exec(ctx, `DELETE FROM seat_holds WHERE cinemas_schedule_id = $1 AND user_id = $2 AND seat_id <> ALL($3)`, cinemaScheduleID, userID, seatIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// ReleaseSeats releases the holds of the user on the schedule in both stores, all of them when seatIDs is empty
+func (r *SeatHoldRepository) ReleaseSeats(ctx context.Context, cinemaScheduleID, userID int, seatIDs []int) error {
+	if r.RDB != nil {
+		args := []any{userID}
+		for _, seatID := range seatIDs {
+			args = append(args, seatID)
+		}
+		if err := releaseSeatsScript.Run(ctx, r.RDB, []string{seatHoldsKey(cinemaScheduleID)}, args...).Err(); err != nil {
+			log.Println("Redis release seats error, back to DB : ", err)
+		}
+	}
+
+	query := `
+	DELETE FROM seat_holds
+	WHERE cinemas_schedule_id = $1
+	  AND user_id = $2
+	  AND (cardinality($3::int[]) = 0 OR seat_id = ANY($3::int[]))
+	`
+	if seatIDs == nil {
+		seatIDs = []int{}
+	}
+	_, err := r.DB.Exec(ctx, query, cinemaScheduleID, userID, seatIDs)
+	return err
+}
+
+// GetScheduleHolds returns the active holds of a schedule as seat id -> user id, from redis and postgres
+func (r *SeatHoldRepository) GetScheduleHolds(ctx context.Context, cinemaScheduleID int) (map[int]int, error) {
+	query := `SELECT seat_id, user_id FROM seat_holds WHERE cinemas_schedule_id = $1 AND expires_at > NOW()`
+	rows, err := r.DB.Query(ctx, query, cinemaScheduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	held := map[int]int{}
+	for rows.Next() {
+		var seatID, userID int
+		if err := rows.Scan(&seatID, &userID); err != nil {
+			return nil, err
+		}
+		held[seatID] = userID
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if r.RDB != nil {
+		if err := r.getScheduleHoldsRedis(ctx, cinemaScheduleID, held); err != nil {
+			log.Println("Redis get seat holds error, back to DB : ", err)
+		}
+	}
+	return held, nil
+}
+
+// helper, adds the unexpired redis holds of the schedule
+func (r *SeatHoldRepository) getScheduleHoldsRedis(ctx context.Context, cinemaScheduleID int, held map[int]int) error {
+	values, err := r.RDB.HGetAll(ctx, seatHoldsKey(cinemaScheduleID)).Result()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	for field, value := range values {
+		owner, expires, ok := strings.Cut(value, ":")
+		if !ok {
+			continue
+		}
+		seatID, err := strconv.Atoi(field)
+		if err != nil {
+			continue
+		}
+		userID, err := strconv.Atoi(owner)
+		if err != nil {
+			continue
+		}
+		expiresAt, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil || expiresAt <= now {
+			continue
+		}
+		held[seatID] = userID
+	}
+	return nil
+}
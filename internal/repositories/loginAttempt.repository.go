@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// failed logins are counted in redis per account and per IP, without redis logins are not throttled
+type LoginAttemptRepository struct {
+	RDB    *redis.Client
+	config models.LoginThrottleConfig
+}
+
+func NewLoginAttemptRepository(rdb *redis.Client, config models.LoginThrottleConfig) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		RDB:    rdb,
+		config: config,
+	}
+}
+
+// counts a failure and locks when the counter reaches the threshold, the lock doubles with every further failure.
+// Returns the lock in milliseconds, 0 when not locked
+var loginFailureScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+local threshold = tonumber(ARGV[1])
+local lock = 0
+if count >= threshold then
+	lock = math.min(tonumber(ARGV[2]) * 2 ^ (count - threshold), tonumber(ARGV[3]))
+	redis.call('SET', KEYS[2], 1, 'PX', math.floor(lock))
+end
+redis.call('PEXPIRE', KEYS[1], math.floor(tonumber(ARGV[4]) + lock))
+return math.floor(lock)
+`)
+
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func loginFailuresKey(kind, value string) string {
+	return fmt.Sprintf("auth:login-failures:%s:%s", kind, value)
+}
+
+func loginLockKey(kind, value string) string {
+	return fmt.Sprintf("auth:login-lock:%s:%s", kind, value)
+}
+
+// GetLockout returns how long the account or the IP stays locked, 0 when login is allowed
+func (r *LoginAttemptRepository) GetLockout(ctx context.Context, email, ip string) time.Duration {
+	if r.RDB == nil {
+		return 0
+	}
+
+	pipe := r.RDB.Pipeline()
+	accountCmd := pipe.PTTL(ctx, loginLockKey("email", normalizeLoginEmail(email)))
+	ipCmd := pipe.PTTL(ctx, loginLockKey("ip", ip))
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Println("Redis login lockout error:", err)
+		return 0
+	}
+
+	// PTTL is negative when the key does not exist
+	return max(accountCmd.Val(), ipCmd.Val(), 0)
+}
+
+// RegisterFailure counts a failed login and returns the lockout it caused, 0 when login is still allowed
+func (r *LoginAttemptRepository) RegisterFailure(ctx context.Context, email, ip string) time.Duration {
+	if r.RDB == nil {
+		return 0
+	}
+
+	email = normalizeLoginEmail(email)
+	targets := []struct {
+		kind, value string
+		threshold   int
+	}{
+		{"email", email, r.config.MaxAccountAttempts},
+		{"ip", ip, r.config.MaxIPAttempts},
+	}
+
+	var lockout time.Duration
+	for _, target := range targets {
+		keys := []string{loginFailuresKey(target.kind, target.value), loginLockKey(target.kind, target.value)}
+		lock, err := loginFailureScript.Run(ctx, r.RDB, keys,
+			target.threshold, r.config.BaseLockout.Milliseconds(), r.config.MaxLockout.Milliseconds(), r.config.Window.Milliseconds(),
+		).Int64()
+		if err != nil {
+			log.Println("Redis login failure error:", err)
+			continue
+		}
+		lockout = max(lockout, time.Duration(lock)*time.Millisecond)
+	}
+	return lockout
+}
+
+// ResetAccount clears the failures of the account after a successful login, the IP counter is kept
+func (r *LoginAttemptRepository) ResetAccount(ctx context.Context, email string) {
+	if r.RDB == nil {
+		return
+	}
+	if err := r.RDB.Del(ctx, loginFailuresKey("email", normalizeLoginEmail(email))).Err(); err != nil {
+		log.Println("Redis reset login failures error:", err)
+	}
+}
+
+// UnlockAccount lifts the lockout of the account and clears its failures
+func (r *LoginAttemptRepository) UnlockAccount(ctx context.Context, email string) error {
+	if r.RDB == nil {
+		return nil
+	}
+	email = normalizeLoginEmail(email)
+	return r.RDB.Del(ctx, loginFailuresKey("email", email), loginLockKey("email", email)).Err()
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrInsufficientPoints = errors.New("not enough loyalty points")
+)
+
+type LoyaltyRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewLoyaltyRepository(db *pgxpool.Pool) *LoyaltyRepository {
+	return &LoyaltyRepository{
+		DB: db,
+	}
+}
+
+// GetBalance returns the points of the user
+func (r *LoyaltyRepository) GetBalance(ctx context.Context, userID int) (int, error) {
+	var balance int
+	err := r.DB.QueryRow(ctx, `SELECT points FROM profiles WHERE user_id = $1`, userID).Scan(&balance)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return balance, err
+}
+
+// GetTransactions returns a page of the ledger of the user, newest first, and the total count
+func (r *LoyaltyRepository) GetTransactions(ctx context.Context, userID, limit, offset int) ([]models.LoyaltyTransaction, int, error) {
+	var totalCount int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM loyalty_transactions WHERE user_id = $1`, userID).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+	SELECT id, order_id, type, points, balance_after, description, created_at
+	FROM loyalty_transactions
+	WHERE user_id = $1
+	ORDER BY id DESC
+	LIMIT $2 OFFSET $3
+	`
+	rows, err := r.DB.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	transactions := []models.LoyaltyTransaction{}
+	for rows.Next() {
+		var transaction models.LoyaltyTransaction
+		if err := rows.Scan(&transaction.ID, &transaction.OrderID, &transaction.Type, &transaction.Points, &transaction.BalanceAfter, &transaction.Description, &transaction.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions, totalCount, rows.Err()
+}
+
+// how addLoyaltyPoints handles a debit larger than the balance
+type pointsDebit int
+
+const (
+	debitClamp         pointsDebit = iota // the balance stops at zero
+	debitStrict                           // fails with ErrInsufficientPoints
+	debitAllowNegative                    // the balance goes below zero, for points that were already spent
+)
+
+// helper, changes the balance of the user and writes the ledger entry, the applied change is returned.
+// debit decides what happens when a debit is larger than the balance
+func addLoyaltyPoints(ctx context.Context, dbTx pgx.Tx, userID int, orderID *int, txType string, points int, description string, debit pointsDebit) (int, error) {
+	if points == 0 {
+		return 0, nil
+	}
+
+	var balance int
+	err := dbTx.QueryRow(ctx, `SELECT points FROM profiles WHERE user_id = $1 FOR UPDATE`, userID).Scan(&balance)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// a user without profile has no balance
+		if debit == debitStrict && points < 0 {
+			return 0, ErrInsufficientPoints
+		}
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if points < 0 && balance+points < 0 && debit != debitAllowNegative {
+		if debit == debitStrict {
+			return 0, ErrInsufficientPoints
+		}
+		points = -max(balance, 0)
+		if points == 0 {
+			return 0, nil
+		}
+	}
+	balance += points
+
+	if _, err := dbTx.Exec(ctx, `UPDATE profiles SET points = $2 WHERE user_id = $1`, userID, balance); err != nil {
+		return 0, err
+	}
+
+	query := `
+	INSERT INTO loyalty_transactions (user_id, order_id, type, points, balance_after, description)
+	VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+	`
+	if _, err := dbTx.Exec(ctx, query, userID, orderID, txType, points, balance, description); err != nil {
+		return 0, err
+	}
+	return points, nil
+}
@@ -294,7 +294,7 @@ func (mr *MoviesRepository) GetSchedulesMovies(ctx context.Context) ([]models.Mo
         SELECT 
             s.id,
             s.date,
-            s.time,
+            to_char(s.time, 'HH24:MI'),
             m.title,
             c.name,
 			c.prices AS ticket_price,
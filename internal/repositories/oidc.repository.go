@@ -0,0 +1,178 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrOIDCStateInvalid      = errors.New("invalid or expired login state")
+	ErrIdentityNotFound      = errors.New("identity not linked")
+	ErrIdentityAlreadyLinked = errors.New("identity already linked")
+)
+
+type OIDCRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewOIDCRepository(db *pgxpool.Pool) *OIDCRepository {
+	return &OIDCRepository{
+		DB: db,
+	}
+}
+
+// CreateLoginState stores a pending provider login until the callback
+func (r *OIDCRepository) CreateLoginState(ctx context.Context, stateHash string, state models.OIDCLoginState) error {
+	query := `
+	INSERT INTO oidc_login_states (state_hash, provider, code_verifier, nonce, expires_at)
+	VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := r.DB.Exec(ctx, query, stateHash, state.Provider, state.CodeVerifier, state.Nonce, state.ExpiresAt); err != nil {
+		return err
+	}
+
+	// abandoned logins are not needed anymore
+	if _, err := r.DB.Exec(ctx, `DELETE FROM oidc_login_states WHERE expires_at < NOW()`); err != nil {
+		log.Println("Delete expired oidc login states error:", err)
+	}
+	return nil
+}
+
+// ConsumeLoginState returns and deletes a pending login, a state is accepted once
+func (r *OIDCRepository) ConsumeLoginState(ctx context.Context, stateHash string) (*models.OIDCLoginState, error) {
+	query := `
+	DELETE FROM oidc_login_states
+	WHERE state_hash = $1
+	RETURNING provider, code_verifier, nonce, expires_at
+	`
+
+	var state models.OIDCLoginState
+	err := r.DB.QueryRow(ctx, query, stateHash).Scan(&state.Provider, &state.CodeVerifier, &state.Nonce, &state.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOIDCStateInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// GetUserByIdentity returns the user linked to the provider account and records the login
+func (r *OIDCRepository) GetUserByIdentity(ctx context.Context, provider, subject string) (*models.User, error) {
+	query := `
+	WITH identity AS (
+		UPDATE user_identities SET last_login_at = NOW()
+		WHERE provider = $1 AND subject = $2
+		RETURNING user_id
+	)
+	SELECT u.id, u.email, u.password, u.role, u.email_verified_at, u.suspended_at, u.totp_enabled_at
+	FROM users u JOIN identity i ON u.id = i.user_id
+	`
+
+	user := &models.User{}
+	err := r.DB.QueryRow(ctx, query, provider, subject).Scan(&user.ID, &user.Email, &user.Password, &user.Role, &user.EmailVerifiedAt, &user.SuspendedAt, &user.TwoFactorEnabledAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrIdentityNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// LinkIdentity links the provider account to the user with the same email. The provider verified the email,
+// an unverified account is verified and its password replaced, it was set by someone who never proved the email
+func (r *OIDCRepository) LinkIdentity(ctx context.Context, user *models.User, identity *models.OIDCIdentity, unusablePassword string) error {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	if err := insertIdentity(ctx, dbTx, user.ID, identity); err != nil {
+		return err
+	}
+
+	if user.EmailVerifiedAt == nil {
+		query := `UPDATE users SET email_verified_at = NOW(), password = $2, updated_at = NOW() WHERE id = $1 AND email_verified_at IS NULL`
+		if _, err := dbTx.Exec(ctx, query, user.ID, unusablePassword); err != nil {
+			return err
+		}
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit db transaction failed : %w", err)
+	}
+	return nil
+}
+
+// CreateUserWithIdentity provisions a verified user and their profile for a new provider account
+func (r *OIDCRepository) CreateUserWithIdentity(ctx context.Context, user *models.User, identity *models.OIDCIdentity) error {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	queryUser := `
+	INSERT INTO users (email, password, role, virtual_account, email_verified_at)
+	VALUES ($1, $2, $3, $4, NOW())
+	RETURNING id, email_verified_at
+	`
+	err = dbTx.QueryRow(ctx, queryUser, user.Email, user.Password, user.Role, user.VirtualAccount).Scan(&user.ID, &user.EmailVerifiedAt)
+	if err != nil {
+		return err
+	}
+
+	queryProfile := `
+	INSERT INTO profiles (user_id, first_name, last_name, points)
+	VALUES ($1, NULLIF(LEFT($2, 100), ''), NULLIF(LEFT($3, 100), ''), 0)
+	`
+	if _, err := dbTx.Exec(ctx, queryProfile, user.ID, identity.FirstName, identity.LastName); err != nil {
+		return err
+	}
+
+	if err := insertIdentity(ctx, dbTx, user.ID, identity); err != nil {
+		return err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit db transaction failed : %w", err)
+	}
+	return nil
+}
+
+// helper
+func insertIdentity(ctx context.Context, dbTx pgx.Tx, userID int, identity *models.OIDCIdentity) error {
+	query := `
+	INSERT INTO user_identities (user_id, provider, subject, email, last_login_at)
+	VALUES ($1, $2, $3, $4, NOW())
+	ON CONFLICT (provider, subject) DO NOTHING
+	`
+	cmd, err := dbTx.Exec(ctx, query, userID, identity.Provider, identity.Subject, identity.Email)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return ErrIdentityAlreadyLinked
+	}
+	return nil
+}
+
+// GetUserByEmail finds the account an identity is linked to, emails are compared case insensitive
+func (r *OIDCRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	query := `SELECT id, email, password, role, email_verified_at, suspended_at, totp_enabled_at FROM users WHERE LOWER(email) = LOWER($1)`
+
+	user := &models.User{}
+	err := r.DB.QueryRow(ctx, query, email).Scan(&user.ID, &user.Email, &user.Password, &user.Role, &user.EmailVerifiedAt, &user.SuspendedAt, &user.TwoFactorEnabledAt)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
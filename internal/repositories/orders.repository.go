@@ -2,10 +2,14 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"time"
 
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -19,6 +23,21 @@ func NewOrdersRepository(db *pgxpool.Pool) *OrdersRepository {
 	}
 }
 
+var (
+	ErrOrderNotFound            = errors.New("order not found")
+	ErrInvalidPaymentTransition = errors.New("invalid payment status transition")
+	ErrPaymentAmountMismatch    = errors.New("payment amount does not match the order total")
+)
+
+// returned by CreateOrder when seats are booked by another order in the meantime
+type SeatConflictError struct {
+	Seats []models.CinemaSeat
+}
+
+func (e *SeatConflictError) Error() string {
+	return "one or more seats are already booked"
+}
+
 func (r *OrdersRepository) CreateOrder(ctx context.Context, order *models.Order) (int, error) {
 	dbTx, err := r.DB.Begin(ctx)
 	if err != nil {
@@ -26,24 +45,99 @@ func (r *OrdersRepository) CreateOrder(ctx context.Context, order *models.Order)
 	}
 	defer dbTx.Rollback(ctx)
 
-	queryOrders := `INSERT INTO orders (qr_code, isPaid, isActive, total_prices, user_id, cinemas_schedule_id, payment_method_id)
-	VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	// lock the screening so bookings of the same schedule run one after another
+	var scheduleID int
+	err = dbTx.QueryRow(ctx, `SELECT id FROM cinemas_schedules WHERE id = $1 FOR UPDATE`, order.CinemasScheduleID).Scan(&scheduleID)
+	if err != nil {
+		return 0, err
+	}
+
+	seatIDs := make([]int, len(order.OrderSeats))
+	for i, seat := range order.OrderSeats {
+		seatIDs[i] = seat.SeatID
+	}
+
+	queryTaken := `
+	SELECT
+		s.id,
+		s.seat_number,
+		s.seat_row,
+		s.seat_column,
+		s.seat_type
+	FROM
+		orders_seats os
+		JOIN seats s ON os.seat_id = s.id
+	WHERE
+		os.cinemas_schedule_id = $1
+		AND os.seat_id = ANY($2)
+		AND os.status = 'booked'
+	ORDER BY
+		s.seat_row,
+		s.seat_column
+	`
+	rows, err := dbTx.Query(ctx, queryTaken, order.CinemasScheduleID, seatIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	var taken []models.CinemaSeat
+	for rows.Next() {
+		seat := models.CinemaSeat{Status: models.SeatStatusBooked}
+		if err := rows.Scan(&seat.SeatID, &seat.SeatNumber, &seat.Row, &seat.Column, &seat.SeatType); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		taken = append(taken, seat)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(taken) > 0 {
+		return 0, &SeatConflictError{Seats: taken}
+	}
+
+	// the id is reserved with NextOrderID so the ticket code can be signed before the insert
+	queryOrders := `INSERT INTO orders (id, qr_code, isPaid, isActive, total_prices, user_id, cinemas_schedule_id, payment_method_id, price_breakdown, payment_status, points_redeemed, promo_code_id)
+	VALUES ($1, $2, false, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`
 
 	var orderID int
-	values := []any{order.QRCode, order.IsPaid, order.IsActive, order.TotalPrices, order.UserID, order.CinemasScheduleID, order.PaymentMethodID}
+	order.IsPaid = false
+	order.PaymentStatus = models.PaymentStatusPending
+	values := []any{order.ID, order.QRCode, order.IsActive, order.TotalPrices, order.UserID, order.CinemasScheduleID, order.PaymentMethodID, order.PriceBreakdown, order.PaymentStatus, order.PointsRedeemed, order.PromoCodeID}
 	err = dbTx.QueryRow(ctx, queryOrders, values...).Scan(&orderID)
 	if err != nil {
 		return 0, err
 	}
 
-	querySeats := `INSERT INTO orders_seats (status, order_id, seat_id) VALUES ($1, $2, $3)`
+	if order.PromoCodeID != nil {
+		if err := redeemPromoCode(ctx, dbTx, *order.PromoCodeID, order.UserID, orderID, order.PriceBreakdown.PromoDiscount); err != nil {
+			return 0, err
+		}
+	}
+
+	if order.PointsRedeemed > 0 {
+		description := fmt.Sprintf("Redeemed on order #%d", orderID)
+		if _, err := addLoyaltyPoints(ctx, dbTx, order.UserID, &orderID, models.LoyaltyRedeem, -order.PointsRedeemed, description, debitStrict); err != nil {
+			return 0, err
+		}
+	}
+
+	querySeats := `INSERT INTO orders_seats (status, order_id, seat_id, cinemas_schedule_id) VALUES ($1, $2, $3, $4)`
 
-	for _, seat := range order.OrderSeats {
-		values := []any{seat.Status, orderID, seat.SeatID}
+	for i, seat := range order.OrderSeats {
+		values := []any{models.SeatStatusBooked, orderID, seat.SeatID, order.CinemasScheduleID}
 		_, err := dbTx.Exec(ctx, querySeats, values...)
 		if err != nil {
+			// the unique index on booked seats is the last line of defence
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return 0, &SeatConflictError{Seats: []models.CinemaSeat{{SeatID: seat.SeatID, Status: models.SeatStatusBooked}}}
+			}
 			return 0, err
 		}
+		order.OrderSeats[i].Status = models.SeatStatusBooked
 	}
 
 	if err := dbTx.Commit(ctx); err != nil {
@@ -57,8 +151,7 @@ func (r *OrdersRepository) AreSeatsAvailable(ctx context.Context, cinemaSchedule
 	query := `
         SELECT 1
         FROM orders_seats os
-        JOIN orders o ON os.order_id = o.id
-        WHERE o.cinemas_schedule_id = $1
+        WHERE os.cinemas_schedule_id = $1
           AND os.seat_id = ANY($2)
           AND os.status = 'booked'
         LIMIT 1
@@ -71,12 +164,646 @@ func (r *OrdersRepository) AreSeatsAvailable(ctx context.Context, cinemaSchedule
 	return err == pgx.ErrNoRows, nil
 }
 
+// check every seat belongs to the auditorium of the screening
+func (r *OrdersRepository) AreSeatsInSchedule(ctx context.Context, cinemaScheduleID int, seatIDs []int) (bool, error) {
+	query := `
+        SELECT COUNT(DISTINCT s.id)
+        FROM seats s
+        JOIN cinemas_schedules cs ON cs.auditorium_id = s.auditorium_id
+        WHERE cs.id = $1
+          AND s.id = ANY($2)
+    `
+	var count int
+	if err := r.DB.QueryRow(ctx, query, cinemaScheduleID, seatIDs).Scan(&count); err != nil {
+		return false, err
+	}
+
+	uniqueSeats := map[int]struct{}{}
+	for _, id := range seatIDs {
+		uniqueSeats[id] = struct{}{}
+	}
+	return count == len(uniqueSeats), nil
+}
+
+// collect everything needed to price the seats of a screening
+func (r *OrdersRepository) GetPricingInput(ctx context.Context, cinemaScheduleID, paymentMethodID int, seatIDs []int) (*models.PricingInput, error) {
+	querySchedule := `
+	SELECT
+		COALESCE(sch.movie_id, 0),
+		cs.cinemas_id,
+		COALESCE(c.prices, 0),
+		sch.date,
+		to_char(sch.time, 'HH24:MI'),
+		pm.fee
+	FROM
+		cinemas_schedules cs
+		JOIN cinemas c ON cs.cinemas_id = c.id
+		JOIN schedules sch ON cs.schedules_id = sch.id
+		JOIN payment_methods pm ON pm.id = $2
+	WHERE
+		cs.id = $1
+	`
+
+	var input models.PricingInput
+	err := r.DB.QueryRow(ctx, querySchedule, cinemaScheduleID, paymentMethodID).Scan(
+		&input.MovieID,
+		&input.CinemaID,
+		&input.BasePrice,
+		&input.ScheduleDate,
+		&input.ScheduleTime,
+		&input.PaymentFee,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	querySeats := `
+	SELECT
+		s.id,
+		s.seat_number,
+		s.seat_type,
+		COALESCE(stp.surcharge, 0)
+	FROM
+		seats s
+		LEFT JOIN seat_type_prices stp ON stp.seat_type = s.seat_type
+	WHERE
+		s.id = ANY($1)
+	ORDER BY
+		s.seat_row,
+		s.seat_column
+	`
+	rows, err := r.DB.Query(ctx, querySeats, seatIDs)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var seat models.PricingSeat
+		if err := rows.Scan(&seat.SeatID, &seat.SeatNumber, &seat.SeatType, &seat.Surcharge); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		input.Seats = append(input.Seats, seat)
+	}
+	rows.Close()
+
+	queryRules := `
+	SELECT
+		name,
+		COALESCE(days_of_week, '{}'),
+		to_char(start_time, 'HH24:MI'),
+		to_char(end_time, 'HH24:MI'),
+		adjustment
+	FROM
+		price_rules
+	WHERE
+		is_active = true
+	ORDER BY
+		id
+	`
+	rows, err = r.DB.Query(ctx, queryRules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rule models.PriceRule
+		if err := rows.Scan(&rule.Name, &rule.DaysOfWeek, &rule.StartTime, &rule.EndTime, &rule.Adjustment); err != nil {
+			return nil, err
+		}
+		input.Rules = append(input.Rules, rule)
+	}
+
+	return &input, nil
+}
+
+// NextOrderID reserves the id of a new order
+func (r *OrdersRepository) NextOrderID(ctx context.Context) (int, error) {
+	var orderID int
+	err := r.DB.QueryRow(ctx, `SELECT nextval(pg_get_serial_sequence('orders', 'id'))`).Scan(&orderID)
+	return orderID, err
+}
+
+func (r *OrdersRepository) GetOrderTicket(ctx context.Context, orderID int) (*models.OrderTicket, error) {
+	query := `SELECT user_id, payment_status, qr_code FROM orders WHERE id = $1`
+
+	var ticket models.OrderTicket
+	err := r.DB.QueryRow(ctx, query, orderID).Scan(&ticket.UserID, &ticket.PaymentStatus, &ticket.QRCode)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// GetPaymentMethod returns an enabled payment method, disabled ones are not found
+func (r *OrdersRepository) GetPaymentMethod(ctx context.Context, paymentMethodID int) (*models.PaymentMethod, error) {
+	query := `SELECT id, name, provider, fee, is_active FROM payment_methods WHERE id = $1 AND is_active = true`
+
+	var method models.PaymentMethod
+	err := r.DB.QueryRow(ctx, query, paymentMethodID).Scan(&method.ID, &method.Name, &method.Provider, &method.Fee, &method.IsActive)
+	if err != nil {
+		return nil, err
+	}
+	return &method, nil
+}
+
+// GetPaymentAmount returns the total price of the order charged with the payment reference
+func (r *OrdersRepository) GetPaymentAmount(ctx context.Context, reference string) (float64, error) {
+	var totalPrices float64
+	err := r.DB.QueryRow(ctx, `SELECT total_prices FROM orders WHERE payment_reference = $1`, reference).Scan(&totalPrices)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrOrderNotFound
+	}
+	return totalPrices, err
+}
+
+func (r *OrdersRepository) SetPaymentReference(ctx context.Context, orderID int, reference string) error {
+	query := `UPDATE orders SET payment_reference = $2, updated_at = NOW() WHERE id = $1`
+	_, err := r.DB.Exec(ctx, query, orderID, reference)
+	return err
+}
+
+// move the payment status only when the transition is allowed, seats are released once the order can not be paid anymore.
+// loyalty points are earned when the order is paid and reversed when it is refunded, redeemed points and the promo code
+// are given back when the order is not paid or refunded.
+func setPaymentStatus(ctx context.Context, dbTx pgx.Tx, orderID int, status, reason string, refundAmount *float64) (*models.OrderCancellation, error) {
+	query := `
+	UPDATE orders
+	SET
+		payment_status = $2::varchar,
+		ispaid = ($2::varchar = 'paid'),
+		paid_at = CASE WHEN $2::varchar = 'paid' THEN NOW() ELSE paid_at END,
+		isactive = CASE WHEN $2::varchar = 'paid' THEN isactive ELSE false END,
+		cancel_reason = NULLIF($4::varchar, ''),
+		cancelled_at = CASE WHEN $4::varchar = '' THEN NULL ELSE NOW() END,
+		refund_amount = CASE WHEN $2::varchar = 'refunded' THEN COALESCE($5::numeric, total_prices) ELSE refund_amount END,
+		points_earned = CASE WHEN $2::varchar = 'paid' THEN FLOOR(COALESCE(total_prices, 0) / $6::numeric)::int ELSE points_earned END,
+		updated_at = NOW()
+	WHERE
+		id = $1
+		AND payment_status = ANY($3)
+	RETURNING user_id, COALESCE(refund_amount, 0), points_earned, points_redeemed
+	`
+
+	result := models.OrderCancellation{OrderID: orderID, PaymentStatus: status}
+	var userID, points, redeemed int
+	err := dbTx.QueryRow(ctx, query, orderID, status, models.PaymentSourceStatuses(status), reason, refundAmount, models.LoyaltyPointAmount).Scan(&userID, &result.RefundAmount, &points, &redeemed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvalidPaymentTransition
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if status != models.PaymentStatusPaid {
+		querySeats := `UPDATE orders_seats SET status = 'available', updated_at = NOW() WHERE order_id = $1 AND status = 'booked'`
+		if _, err := dbTx.Exec(ctx, querySeats, orderID); err != nil {
+			return nil, err
+		}
+	}
+
+	// points are earned on payment, an order that ends unpaid or refunded gives the redeemed points back
+	switch status {
+	case models.PaymentStatusPaid:
+		if _, err := addLoyaltyPoints(ctx, dbTx, userID, &orderID, models.LoyaltyEarn, points, fmt.Sprintf("Earned on order #%d", orderID), debitClamp); err != nil {
+			return nil, err
+		}
+	default:
+		restored, err := addLoyaltyPoints(ctx, dbTx, userID, &orderID, models.LoyaltyRestore, redeemed, fmt.Sprintf("Given back from order #%d", orderID), debitClamp)
+		if err != nil {
+			return nil, err
+		}
+		result.PointsRestored = restored
+
+		if err := releasePromoRedemption(ctx, dbTx, orderID); err != nil {
+			return nil, err
+		}
+
+		if status == models.PaymentStatusRefunded {
+			// the earned points may be spent already, the balance goes negative instead of keeping them
+			reversed, err := addLoyaltyPoints(ctx, dbTx, userID, &orderID, models.LoyaltyReverse, -points, fmt.Sprintf("Reversed for refunded order #%d", orderID), debitAllowNegative)
+			if err != nil {
+				return nil, err
+			}
+			result.PointsReversed = -reversed
+		}
+	}
+
+	return &result, nil
+}
+
+func (r *OrdersRepository) UpdatePaymentStatus(ctx context.Context, orderID int, status string) error {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	if _, err := setPaymentStatus(ctx, dbTx, orderID, status, models.CancelReasonForPayment(status), nil); err != nil {
+		return err
+	}
+
+	return dbTx.Commit(ctx)
+}
+
+// ApplyPaymentEvent stores a provider callback and updates the order, a repeated event is ignored and reported as not applied
+func (r *OrdersRepository) ApplyPaymentEvent(ctx context.Context, provider string, event *models.PaymentEvent, payload []byte) (int, bool, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	var eventID int
+	queryEvent := `
+	INSERT INTO payment_events (provider, event_id, status, payload)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (provider, event_id) DO NOTHING
+	RETURNING id
+	`
+	err = dbTx.QueryRow(ctx, queryEvent, provider, event.ID, event.Status, string(payload)).Scan(&eventID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	var orderID int
+	var totalPrices float64
+	queryOrder := `SELECT id, total_prices FROM orders WHERE payment_reference = $1 FOR UPDATE`
+	err = dbTx.QueryRow(ctx, queryOrder, event.Reference).Scan(&orderID, &totalPrices)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, ErrOrderNotFound
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	if event.Status == models.PaymentStatusPaid && math.Abs(event.Amount-totalPrices) >= 0.01 {
+		return orderID, false, ErrPaymentAmountMismatch
+	}
+
+	var refundAmount *float64
+	if event.Status == models.PaymentStatusRefunded && event.Amount > 0 {
+		refundAmount = &event.Amount
+	}
+	if _, err := setPaymentStatus(ctx, dbTx, orderID, event.Status, models.CancelReasonForPayment(event.Status), refundAmount); err != nil {
+		return orderID, false, err
+	}
+
+	if _, err := dbTx.Exec(ctx, `UPDATE payment_events SET order_id = $1 WHERE id = $2`, orderID, eventID); err != nil {
+		return orderID, false, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return orderID, false, err
+	}
+
+	return orderID, true, nil
+}
+
+func (r *OrdersRepository) GetCancellableOrder(ctx context.Context, orderID int) (*models.CancellableOrder, error) {
+	query := `
+	SELECT
+		o.id,
+		o.user_id,
+		COALESCE(cs.cinemas_id, 0),
+		o.payment_status,
+		o.payment_reference,
+		COALESCE(pm.provider, ''),
+		COALESCE(o.total_prices, 0),
+		EXTRACT(EPOCH FROM ((sch.date + sch.time) - NOW()::timestamp))::float8
+	FROM
+		orders o
+		JOIN cinemas_schedules cs ON o.cinemas_schedule_id = cs.id
+		JOIN schedules sch ON cs.schedules_id = sch.id
+		LEFT JOIN payment_methods pm ON o.payment_method_id = pm.id
+	WHERE
+		o.id = $1
+	`
+
+	var order models.CancellableOrder
+	var untilShowtime float64
+	err := r.DB.QueryRow(ctx, query, orderID).Scan(
+		&order.ID,
+		&order.UserID,
+		&order.CinemaID,
+		&order.PaymentStatus,
+		&order.PaymentReference,
+		&order.Provider,
+		&order.TotalPrices,
+		&untilShowtime,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	order.UntilShowtime = time.Duration(untilShowtime * float64(time.Second))
+
+	return &order, nil
+}
+
+// StartRefund moves a paid order to refunding and keeps the refund amount and reason. A refunding order is returned
+// again with the kept refund, the provider refunds once for the same idempotency key
+func (r *OrdersRepository) StartRefund(ctx context.Context, orderID int, amount float64, reason string) (*models.PendingRefund, error) {
+	query := `
+	UPDATE orders
+	SET
+		payment_status = 'refunding',
+		refund_amount = CASE WHEN payment_status = 'paid' AND refund_sent_at IS NULL THEN $2 ELSE refund_amount END,
+		cancel_reason = CASE WHEN payment_status = 'paid' AND refund_sent_at IS NULL THEN $3 ELSE cancel_reason END,
+		updated_at = NOW()
+	WHERE
+		id = $1
+		AND payment_status IN ('paid', 'refunding')
+	RETURNING COALESCE(refund_amount, 0)::float8, COALESCE(cancel_reason, ''), refund_sent_at IS NOT NULL
+	`
+
+	var refund models.PendingRefund
+	err := r.DB.QueryRow(ctx, query, orderID, amount, reason).Scan(&refund.Amount, &refund.Reason, &refund.Sent)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvalidPaymentTransition
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// MarkRefundSent records that the provider accepted the refund, the order is not refunded again
+func (r *OrdersRepository) MarkRefundSent(ctx context.Context, orderID int) error {
+	_, err := r.DB.Exec(ctx, `UPDATE orders SET refund_sent_at = NOW(), updated_at = NOW() WHERE id = $1`, orderID)
+	return err
+}
+
+// AbortRefund moves a refunding order back to paid when the provider refund failed, unless another attempt refunded it
+func (r *OrdersRepository) AbortRefund(ctx context.Context, orderID int) error {
+	query := `
+	UPDATE orders
+	SET payment_status = 'paid', refund_amount = NULL, cancel_reason = NULL, updated_at = NOW()
+	WHERE id = $1 AND payment_status = 'refunding' AND refund_sent_at IS NULL
+	`
+	_, err := r.DB.Exec(ctx, query, orderID)
+	return err
+}
+
+// FinishSentRefunds refunds the orders left refunding after the provider accepted their refund for longer than
+// the grace period, it returns the refunded order ids
+func (r *OrdersRepository) FinishSentRefunds(ctx context.Context, grace time.Duration, limit int) ([]int, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	// skip locked rows, their cancellation is finishing right now
+	query := `
+	SELECT id, COALESCE(refund_amount, 0)::float8, COALESCE(cancel_reason, '')
+	FROM orders
+	WHERE payment_status = 'refunding' AND refund_sent_at < NOW() - $1::interval
+	ORDER BY refund_sent_at
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED
+	`
+
+	interval := fmt.Sprintf("%d milliseconds", grace.Milliseconds())
+	rows, err := dbTx.Query(ctx, query, interval, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	orderIDs := []int{}
+	refunds := []models.PendingRefund{}
+	for rows.Next() {
+		var orderID int
+		var refund models.PendingRefund
+		if err := rows.Scan(&orderID, &refund.Amount, &refund.Reason); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		orderIDs = append(orderIDs, orderID)
+		refunds = append(refunds, refund)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, orderID := range orderIDs {
+		if _, err := setPaymentStatus(ctx, dbTx, orderID, models.PaymentStatusRefunded, refunds[i].Reason, &refunds[i].Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return orderIDs, nil
+}
+
+// CancelOrder cancels a pending order or refunds a paid one, releasing the seats and reversing the earned points
+func (r *OrdersRepository) CancelOrder(ctx context.Context, orderID int, status, reason string, refundAmount float64) (*models.OrderCancellation, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	result, err := setPaymentStatus(ctx, dbTx, orderID, status, reason, &refundAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetOrderDetail returns one order with its screening, every seat and the payment
+func (r *OrdersRepository) GetOrderDetail(ctx context.Context, orderID int) (*models.OrderDetail, error) {
+	query := `
+	SELECT
+		o.id,
+		o.user_id,
+		o.payment_status,
+		COALESCE(o.ispaid, false),
+		COALESCE(o.isactive, false),
+		o.qr_code,
+		COALESCE(o.total_prices, 0),
+		o.price_breakdown,
+		COALESCE(pm.name, ''),
+		o.payment_reference,
+		o.paid_at,
+		o.cancel_reason,
+		o.cancelled_at,
+		o.refund_amount::float8,
+		o.checked_in_at,
+		o.created_at,
+		m.id,
+		m.title,
+		m.age_rating,
+		m.poster_path,
+		m.duration,
+		cs.id,
+		c.name,
+		c.image_path,
+		COALESCE(l.name, ''),
+		a.name,
+		sch.date::text,
+		to_char(sch.time, 'HH24:MI')
+	FROM
+		orders o
+		JOIN cinemas_schedules cs ON o.cinemas_schedule_id = cs.id
+		JOIN cinemas c ON cs.cinemas_id = c.id
+		JOIN schedules sch ON cs.schedules_id = sch.id
+		JOIN movies m ON sch.movie_id = m.id
+		LEFT JOIN locations l ON cs.locations_id = l.id
+		LEFT JOIN auditoriums a ON cs.auditorium_id = a.id
+		LEFT JOIN payment_methods pm ON o.payment_method_id = pm.id
+	WHERE
+		o.id = $1
+	`
+
+	var order models.OrderDetail
+	err := r.DB.QueryRow(ctx, query, orderID).Scan(
+		&order.ID,
+		&order.UserID,
+		&order.PaymentStatus,
+		&order.IsPaid,
+		&order.IsActive,
+		&order.QRCode,
+		&order.TotalPrices,
+		&order.PriceBreakdown,
+		&order.PaymentMethod,
+		&order.PaymentReference,
+		&order.PaidAt,
+		&order.CancelReason,
+		&order.CancelledAt,
+		&order.RefundAmount,
+		&order.CheckedInAt,
+		&order.CreatedAt,
+		&order.MovieID,
+		&order.Title,
+		&order.AgeRating,
+		&order.PosterPath,
+		&order.Duration,
+		&order.CinemasScheduleID,
+		&order.Cinema,
+		&order.CinemaImage,
+		&order.Location,
+		&order.Auditorium,
+		&order.Date,
+		&order.Time,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	querySeats := `
+	SELECT
+		s.id,
+		s.seat_number,
+		s.seat_type,
+		COALESCE(os.status, '')
+	FROM
+		orders_seats os
+		JOIN seats s ON os.seat_id = s.id
+	WHERE
+		os.order_id = $1
+	ORDER BY
+		s.seat_row,
+		s.seat_column
+	`
+	rows, err := r.DB.Query(ctx, querySeats, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	order.Seats = []models.OrderDetailSeat{}
+	for rows.Next() {
+		var seat models.OrderDetailSeat
+		if err := rows.Scan(&seat.SeatID, &seat.SeatNumber, &seat.SeatType, &seat.Status); err != nil {
+			return nil, err
+		}
+		order.Seats = append(order.Seats, seat)
+	}
+
+	return &order, nil
+}
+
+// ExpireUnpaidOrders expires the pending orders created before the window and releases their seats, it returns the expired order ids
+func (r *OrdersRepository) ExpireUnpaidOrders(ctx context.Context, window time.Duration, limit int) ([]int, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	// skip locked rows, they are being paid right now
+	query := `
+	SELECT id
+	FROM orders
+	WHERE payment_status = 'pending' AND created_at < NOW() - $1::interval
+	ORDER BY created_at
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED
+	`
+
+	interval := fmt.Sprintf("%d milliseconds", window.Milliseconds())
+	rows, err := dbTx.Query(ctx, query, interval, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	orderIDs := []int{}
+	for rows.Next() {
+		var orderID int
+		if err := rows.Scan(&orderID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		orderIDs = append(orderIDs, orderID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// the same transition as an expired payment event, seats, redeemed points and the promo code are given back
+	for _, orderID := range orderIDs {
+		if _, err := setPaymentStatus(ctx, dbTx, orderID, models.PaymentStatusExpired, models.CancelReasonPaymentExpired, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return orderIDs, nil
+}
+
 func (r *OrdersRepository) GetOrdersHistory(ctx context.Context, userID int) ([]models.OrderHistory, error) {
 	query := `
         SELECT
             o.id,
             o.isactive,
             o.ispaid,
+            o.payment_status,
+            o.cancel_reason,
             o.qr_code,
             o.total_prices,
             o.user_id,
@@ -87,9 +814,9 @@ func (r *OrdersRepository) GetOrdersHistory(ctx context.Context, userID int) ([]
             c.image_path,
             l.name AS location,
             sch.date::text,
-            sch.time::text,
-            ARRAY_AGG(s.seat_number) AS seat_number,
-            s.seat_type
+            to_char(sch.time, 'HH24:MI'),
+            ARRAY_AGG(s.seat_number ORDER BY s.seat_row, s.seat_column) AS seat_number,
+            COALESCE(STRING_AGG(DISTINCT s.seat_type, ', '), '') AS seat_type
         FROM
             orders o
             LEFT JOIN cinemas_schedules cs ON o.cinemas_schedule_id = cs.id
@@ -110,11 +837,12 @@ func (r *OrdersRepository) GetOrdersHistory(ctx context.Context, userID int) ([]
             c.name,
             c.image_path,
             l.name,
-            s.seat_type,
             sch.date,
             sch.time,
             o.isactive,
             o.ispaid,
+            o.payment_status,
+            o.cancel_reason,
             o.qr_code,
             o.total_prices
         ORDER BY
@@ -134,6 +862,8 @@ func (r *OrdersRepository) GetOrdersHistory(ctx context.Context, userID int) ([]
 			&oh.ID,
 			&oh.IsActive,
 			&oh.IsPaid,
+			&oh.PaymentStatus,
+			&oh.CancelReason,
 			&oh.QRCode,
 			&oh.TotalPrices,
 			&oh.UserID,
@@ -155,3 +885,34 @@ func (r *OrdersRepository) GetOrdersHistory(ctx context.Context, userID int) ([]
 	}
 	return orderHistory, nil
 }
+
+// GetUserOrderDetails returns every order of the user with its seats, oldest first
+func (r *OrdersRepository) GetUserOrderDetails(ctx context.Context, userID int) ([]models.OrderDetail, error) {
+	rows, err := r.DB.Query(ctx, `SELECT id FROM orders WHERE user_id = $1 ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orderIDs := []int{}
+	for rows.Next() {
+		var orderID int
+		if err := rows.Scan(&orderID); err != nil {
+			return nil, err
+		}
+		orderIDs = append(orderIDs, orderID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	orders := make([]models.OrderDetail, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		order, err := r.GetOrderDetail(ctx, orderID)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, *order)
+	}
+	return orders, nil
+}
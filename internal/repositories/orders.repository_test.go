@@ -0,0 +1,241 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testDB connects to the migrated database of TEST_DATABASE_URL, the test is skipped without it
+func testDB(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := pgxpool.New(context.Background(), url)
+	if err != nil {
+		t.Fatalf("connect database: %v", err)
+	}
+	if err := db.Ping(context.Background()); err != nil {
+		t.Fatalf("ping database: %v", err)
+	}
+	t.Cleanup(db.Close)
+	return db
+}
+
+type bookingFixture struct {
+	cinemaScheduleID int
+	seatID           int
+	paymentMethodID  int
+	userIDs          []int
+}
+
+// createBookingFixture adds a screening with one seat and the users booking it, everything is removed after the test
+func createBookingFixture(t *testing.T, db *pgxpool.Pool, users int) bookingFixture {
+	t.Helper()
+	ctx := context.Background()
+	suffix := time.Now().UnixNano()
+	var fixture bookingFixture
+	var cinemaID, auditoriumID, movieID, scheduleID int
+
+	steps := []struct {
+		query string
+		args  []any
+		dest  *int
+	}{
+		{`INSERT INTO cinemas (name, prices) VALUES ($1, 50000) RETURNING id`, []any{fmt.Sprintf("test cinema %d", suffix)}, &cinemaID},
+		{`INSERT INTO auditoriums (cinemas_id, name, seat_rows, seat_columns) VALUES ($1, 'Test', 1, 1) RETURNING id`, []any{&cinemaID}, &auditoriumID},
+		{`INSERT INTO seats (auditorium_id, seat_number, seat_row, seat_column, seat_type) VALUES ($1, 'A1', 'A', 1, 'regular') RETURNING id`, []any{&auditoriumID}, &fixture.seatID},
+		{`INSERT INTO movies (title, duration) VALUES ($1, 120) RETURNING id`, []any{fmt.Sprintf("test movie %d", suffix)}, &movieID},
+		{`INSERT INTO schedules (movie_id, date, time) VALUES ($1, CURRENT_DATE + 7, '21:30') RETURNING id`, []any{&movieID}, &scheduleID},
+		{`INSERT INTO cinemas_schedules (cinemas_id, schedules_id, auditorium_id) VALUES ($1, $2, $3) RETURNING id`, []any{&cinemaID, &scheduleID, &auditoriumID}, &fixture.cinemaScheduleID},
+		{`INSERT INTO payment_methods (name, provider) VALUES ('Test', 'fake') RETURNING id`, nil, &fixture.paymentMethodID},
+	}
+
+	t.Cleanup(func() {
+		// the screening, its orders and seats are removed with the cinema and the movie
+		db.Exec(ctx, `DELETE FROM cinemas WHERE id = $1`, cinemaID)
+		db.Exec(ctx, `DELETE FROM movies WHERE id = $1`, movieID)
+		db.Exec(ctx, `DELETE FROM payment_methods WHERE id = $1`, fixture.paymentMethodID)
+		db.Exec(ctx, `DELETE FROM users WHERE id = ANY($1)`, fixture.userIDs)
+	})
+
+	for _, step := range steps {
+		args := make([]any, len(step.args))
+		for i, arg := range step.args {
+			if id, ok := arg.(*int); ok {
+				arg = *id
+			}
+			args[i] = arg
+		}
+		if err := db.QueryRow(ctx, step.query, args...).Scan(step.dest); err != nil {
+			t.Fatalf("create fixture: %v", err)
+		}
+	}
+
+	for i := 0; i < users; i++ {
+		var userID int
+		email := fmt.Sprintf("booking-%d-%d@test.local", suffix, i)
+		if err := db.QueryRow(ctx, `INSERT INTO users (email, password) VALUES ($1, 'x') RETURNING id`, email).Scan(&userID); err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		fixture.userIDs = append(fixture.userIDs, userID)
+	}
+	return fixture
+}
+
+// a pending order of the fixture seat for the user
+func newTestOrder(ctx context.Context, repo *OrdersRepository, fixture bookingFixture, userID int) (*models.Order, error) {
+	orderID, err := repo.NextOrderID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Order{
+		ID:                orderID,
+		QRCode:            fmt.Sprintf("test-ticket-%d", orderID),
+		IsActive:          true,
+		TotalPrices:       50000,
+		UserID:            userID,
+		CinemasScheduleID: fixture.cinemaScheduleID,
+		PaymentMethodID:   fixture.paymentMethodID,
+		OrderSeats:        []models.OrderSeatInput{{SeatID: fixture.seatID}},
+		PriceBreakdown:    &models.PriceBreakdown{},
+	}, nil
+}
+
+func TestCreateOrderBooksSeatOnce(t *testing.T) {
+	db := testDB(t)
+	repo := NewOrdersRepository(db)
+	const attempts = 20
+	fixture := createBookingFixture(t, db, attempts)
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	results := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(userID int) {
+			defer wg.Done()
+			ctx := context.Background()
+
+			order, err := newTestOrder(ctx, repo, fixture, userID)
+			if err != nil {
+				results <- err
+				return
+			}
+
+			<-start
+			_, err = repo.CreateOrder(ctx, order)
+			results <- err
+		}(fixture.userIDs[i])
+	}
+	close(start)
+	wg.Wait()
+	close(results)
+
+	booked, conflicts := 0, 0
+	for err := range results {
+		var conflict *SeatConflictError
+		switch {
+		case err == nil:
+			booked++
+		case errors.As(err, &conflict):
+			conflicts++
+			if len(conflict.Seats) != 1 || conflict.Seats[0].SeatID != fixture.seatID {
+				t.Errorf("conflict reports seats %+v, want seat %d", conflict.Seats, fixture.seatID)
+			}
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if booked != 1 || conflicts != attempts-1 {
+		t.Fatalf("booked %d and refused %d orders, want 1 and %d", booked, conflicts, attempts-1)
+	}
+
+	var rows int
+	query := `SELECT COUNT(*) FROM orders_seats WHERE cinemas_schedule_id = $1 AND seat_id = $2 AND status = 'booked'`
+	if err := db.QueryRow(context.Background(), query, fixture.cinemaScheduleID, fixture.seatID).Scan(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if rows != 1 {
+		t.Fatalf("seat booked %d times, want 1", rows)
+	}
+}
+
+func TestExpireUnpaidOrdersGivesBackPromoAndPoints(t *testing.T) {
+	db := testDB(t)
+	repo := NewOrdersRepository(db)
+	ctx := context.Background()
+	fixture := createBookingFixture(t, db, 1)
+	userID := fixture.userIDs[0]
+
+	if _, err := db.Exec(ctx, `INSERT INTO profiles (user_id, points) VALUES ($1, 1000)`, userID); err != nil {
+		t.Fatalf("create profile: %v", err)
+	}
+	var promoID int
+	code := fmt.Sprintf("EXP%d", time.Now().UnixNano())
+	queryPromo := `INSERT INTO promo_codes (code, discount_type, discount_value, usage_limit) VALUES ($1, 'fixed', 10000, 1) RETURNING id`
+	if err := db.QueryRow(ctx, queryPromo, code).Scan(&promoID); err != nil {
+		t.Fatalf("create promo code: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec(ctx, `DELETE FROM promo_codes WHERE id = $1`, promoID)
+	})
+
+	order, err := newTestOrder(ctx, repo, fixture, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order.PromoCodeID = &promoID
+	order.PointsRedeemed = 200
+	order.PriceBreakdown = &models.PriceBreakdown{PromoDiscount: 10000, PointsRedeemed: 200, PointsDiscount: 20000}
+	orderID, err := repo.CreateOrder(ctx, order)
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	if _, err := db.Exec(ctx, `UPDATE orders SET created_at = NOW() - interval '1 hour' WHERE id = $1`, orderID); err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := repo.ExpireUnpaidOrders(ctx, 30*time.Minute, 10000)
+	if err != nil {
+		t.Fatalf("expire unpaid orders: %v", err)
+	}
+	if !slices.Contains(expired, orderID) {
+		t.Fatalf("order %d not expired, expired %v", orderID, expired)
+	}
+
+	var status string
+	var usedCount, points, restored int
+	var released bool
+	query := `
+	SELECT
+		(SELECT payment_status FROM orders WHERE id = $1),
+		(SELECT used_count FROM promo_codes WHERE id = $2),
+		(SELECT released_at IS NOT NULL FROM promo_redemptions WHERE order_id = $1),
+		(SELECT points FROM profiles WHERE user_id = $3),
+		(SELECT COALESCE(SUM(points), 0) FROM loyalty_transactions WHERE order_id = $1 AND type = 'restore')
+	`
+	if err := db.QueryRow(ctx, query, orderID, promoID, userID).Scan(&status, &usedCount, &released, &points, &restored); err != nil {
+		t.Fatal(err)
+	}
+	if status != models.PaymentStatusExpired {
+		t.Errorf("payment status %s, want %s", status, models.PaymentStatusExpired)
+	}
+	if usedCount != 0 || !released {
+		t.Errorf("promo used %d times, redemption released %v, want 0 and true", usedCount, released)
+	}
+	if points != 1000 || restored != 200 {
+		t.Errorf("balance %d with %d points restored, want 1000 and 200", points, restored)
+	}
+}
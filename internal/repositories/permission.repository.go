@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrRoleNotFound = errors.New("role not found")
+)
+
+// roles change rarely, a role is cached in redis and read from postgres when redis is unavailable
+const roleCacheTTL = 10 * time.Minute
+
+type PermissionRepository struct {
+	DB  *pgxpool.Pool
+	RDB *redis.Client
+}
+
+func NewPermissionRepository(db *pgxpool.Pool, rdb *redis.Client) *PermissionRepository {
+	return &PermissionRepository{
+		DB:  db,
+		RDB: rdb,
+	}
+}
+
+func roleKey(name string) string {
+	return fmt.Sprintf("auth:role:%s", name)
+}
+
+const roleQuery = `
+SELECT
+	r.id,
+	r.name,
+	r.description,
+	r.cinema_scoped,
+	COALESCE(ARRAY_AGG(p.name ORDER BY p.name) FILTER (WHERE p.name IS NOT NULL), '{}')
+FROM
+	roles r
+	LEFT JOIN role_permissions rp ON rp.role_id = r.id
+	LEFT JOIN permissions p ON rp.permission_id = p.id
+`
+
+func scanRole(row pgx.Row, role *models.Role) error {
+	return row.Scan(&role.ID, &role.Name, &role.Description, &role.CinemaScoped, &role.Permissions)
+}
+
+// GetRole returns the role with its permissions
+func (r *PermissionRepository) GetRole(ctx context.Context, name string) (*models.Role, error) {
+	var cached models.Role
+	if r.RDB != nil {
+		if err := utils.GetCache(ctx, r.RDB, roleKey(name), &cached); err != nil {
+			log.Println("Redis error, back to DB : ", err)
+		}
+		if cached.Name != "" {
+			return &cached, nil
+		}
+	}
+
+	var role models.Role
+	err := scanRole(r.DB.QueryRow(ctx, roleQuery+` WHERE r.name = $1 GROUP BY r.id`, name), &role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrRoleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if r.RDB != nil {
+		if err := utils.SetCache(ctx, r.RDB, roleKey(name), role, roleCacheTTL); err != nil {
+			log.Println("Redis set cache error:", err)
+		}
+	}
+	return &role, nil
+}
+
+// GetRoles returns every role with its permissions
+func (r *PermissionRepository) GetRoles(ctx context.Context) ([]models.Role, error) {
+	rows, err := r.DB.Query(ctx, roleQuery+` GROUP BY r.id ORDER BY r.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []models.Role{}
+	for rows.Next() {
+		var role models.Role
+		if err := scanRole(rows, &role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+// GetUserCinemaID returns the cinema the user is assigned to, nil when the user is not assigned
+func (r *PermissionRepository) GetUserCinemaID(ctx context.Context, userID int) (*int, error) {
+	var cinemaID *int
+	err := r.DB.QueryRow(ctx, `SELECT cinema_id FROM users WHERE id = $1`, userID).Scan(&cinemaID)
+	if err != nil {
+		return nil, err
+	}
+	return cinemaID, nil
+}
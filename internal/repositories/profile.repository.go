@@ -2,10 +2,13 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -44,11 +47,6 @@ func (pr *ProfileRepository) UpdateProfile(ctx context.Context, userID int, upda
 		profileArgs = append(profileArgs, *update.Profile.ImagePath)
 		argPos++
 	}
-	if update.Profile.Points != nil {
-		profileSet = append(profileSet, fmt.Sprintf("points = $%d", argPos))
-		profileArgs = append(profileArgs, *update.Profile.Points)
-		argPos++
-	}
 
 	if len(profileSet) > 0 {
 		query := fmt.Sprintf("UPDATE profiles SET %s WHERE user_id = $%d", strings.Join(profileSet, ", "), argPos)
@@ -64,7 +62,12 @@ func (pr *ProfileRepository) UpdateProfile(ctx context.Context, userID int, upda
 	userPos := 1
 
 	if update.User.Email != nil {
-		userSet = append(userSet, fmt.Sprintf("email = $%d", userPos))
+		// a new email has to be verified again
+		userSet = append(userSet,
+			fmt.Sprintf("email_verified_at = CASE WHEN email = $%d THEN email_verified_at END", userPos),
+			fmt.Sprintf("verification_sent_at = CASE WHEN email = $%d THEN verification_sent_at END", userPos),
+			fmt.Sprintf("email = $%d", userPos),
+		)
 		userArgs = append(userArgs, update.User.Email)
 		userPos++
 	}
@@ -129,3 +132,130 @@ func (pr *ProfileRepository) GetProfile(ctx context.Context, userID int) (*model
 
 	return &userProfile, err
 }
+
+// GetAccountExport returns the account, profile and linked provider accounts of the user, orders and sessions are added by the caller
+func (pr *ProfileRepository) GetAccountExport(ctx context.Context, userID int) (*models.AccountExport, error) {
+	query := `
+	SELECT
+		u.id,
+		u.email,
+		u.role,
+		u.virtual_account,
+		u.created_at,
+		u.email_verified_at,
+		u.totp_enabled_at,
+		p.first_name,
+		p.last_name,
+		p.phone_number,
+		p.points,
+		p.image_path
+	FROM users u
+	LEFT JOIN profiles p ON u.id = p.user_id
+	WHERE u.id = $1
+	`
+
+	export := &models.AccountExport{ExportedAt: time.Now()}
+	account := &export.Account
+	profile := &export.Profile
+	err := pr.DB.QueryRow(ctx, query, userID).Scan(
+		&account.ID,
+		&account.Email,
+		&account.Role,
+		&account.VirtualAccount,
+		&account.CreatedAt,
+		&account.EmailVerifiedAt,
+		&account.TwoFactorEnabledAt,
+		&profile.FirstName,
+		&profile.LastName,
+		&profile.PhoneNumber,
+		&profile.Points,
+		&profile.ImagePath,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := pr.DB.Query(ctx, `SELECT provider, email, created_at, last_login_at FROM user_identities WHERE user_id = $1 ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	export.Identities = []models.ExportIdentity{}
+	for rows.Next() {
+		var identity models.ExportIdentity
+		if err := rows.Scan(&identity.Provider, &identity.Email, &identity.CreatedAt, &identity.LastLoginAt); err != nil {
+			return nil, err
+		}
+		export.Identities = append(export.Identities, identity)
+	}
+	return export, rows.Err()
+}
+
+// DeleteAccount anonymizes the user, deletes their personal data and revokes every token. The users row and the orders
+// are kept, they are financial records. Returns the path of the profile image to remove
+func (pr *ProfileRepository) DeleteAccount(ctx context.Context, userID int) (*string, error) {
+	dbTx, err := pr.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	var imagePath *string
+	var points int
+	query := `SELECT image_path, points FROM profiles WHERE user_id = $1 FOR UPDATE`
+	if err := dbTx.QueryRow(ctx, query, userID).Scan(&imagePath, &points); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	// the password is not a bcrypt hash anymore, no password matches it
+	queryUser := `
+	UPDATE users
+	SET
+		email = 'deleted-' || id || '@deleted.invalid',
+		password = '!deleted',
+		virtual_account = NULL,
+		email_verified_at = NULL,
+		verification_sent_at = NULL,
+		totp_secret = NULL,
+		totp_enabled_at = NULL,
+		totp_last_step = NULL,
+		deleted_at = NOW(),
+		updated_at = NOW()
+	WHERE id = $1 AND deleted_at IS NULL
+	`
+	cmd, err := dbTx.Exec(ctx, queryUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.RowsAffected() == 0 {
+		return nil, pgx.ErrNoRows
+	}
+
+	queryProfile := `UPDATE profiles SET first_name = NULL, last_name = NULL, phone_number = NULL, image_path = NULL WHERE user_id = $1`
+	if _, err := dbTx.Exec(ctx, queryProfile, userID); err != nil {
+		return nil, err
+	}
+	if _, err := addLoyaltyPoints(ctx, dbTx, userID, nil, models.LoyaltyForfeit, -max(points, 0), "Account deleted", debitClamp); err != nil {
+		return nil, err
+	}
+
+	for _, table := range []string{"user_identities", "user_recovery_codes", "password_reset_tokens", "seat_holds"} {
+		if _, err := dbTx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", table), userID); err != nil {
+			return nil, err
+		}
+	}
+
+	// devices are not personal data to keep
+	if _, err := dbTx.Exec(ctx, `UPDATE user_sessions SET user_agent = NULL, ip_address = NULL WHERE user_id = $1`, userID); err != nil {
+		return nil, err
+	}
+	if _, _, err := revokeAllUserTokens(ctx, dbTx, userID, models.SessionRevokedAccountDeleted); err != nil {
+		return nil, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit db transaction failed : %w", err)
+	}
+	return imagePath, nil
+}
@@ -0,0 +1,284 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrPromoCodeNotFound     = errors.New("promo code not found")
+	ErrPromoCodeExists       = errors.New("promo code already exists")
+	ErrPromoCodeInUse        = errors.New("promo code has been redeemed")
+	ErrPromoCodeLimitTooLow  = errors.New("usage limit is below the redemptions of the promo code")
+	ErrPromoCodeUsedUp       = errors.New("promo code is no longer available")
+	ErrPromoCodeUserLimitHit = errors.New("promo code already used the maximum number of times")
+)
+
+type PromoRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewPromoRepository(db *pgxpool.Pool) *PromoRepository {
+	return &PromoRepository{
+		DB: db,
+	}
+}
+
+const promoCodeColumns = `
+	id, code, description, discount_type, discount_value, max_discount, min_spend, usage_limit, per_user_limit,
+	used_count, valid_from, valid_until, movie_ids, cinema_ids, payment_method_ids, is_active, created_at, updated_at
+`
+
+// helper
+func scanPromoCode(row pgx.Row, promo *models.PromoCode) error {
+	return row.Scan(
+		&promo.ID,
+		&promo.Code,
+		&promo.Description,
+		&promo.DiscountType,
+		&promo.DiscountValue,
+		&promo.MaxDiscount,
+		&promo.MinSpend,
+		&promo.UsageLimit,
+		&promo.PerUserLimit,
+		&promo.UsedCount,
+		&promo.ValidFrom,
+		&promo.ValidUntil,
+		&promo.MovieIDs,
+		&promo.CinemaIDs,
+		&promo.PaymentMethodIDs,
+		&promo.IsActive,
+		&promo.CreatedAt,
+		&promo.UpdatedAt,
+	)
+}
+
+// helper, a duplicate code or a usage limit below used_count are reported as errors of the request
+func promoWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.ConstraintName {
+		case "promo_codes_code_key":
+			return ErrPromoCodeExists
+		case "promo_codes_used_count_check":
+			return ErrPromoCodeLimitTooLow
+		}
+	}
+	return err
+}
+
+// GetPromoCodes returns a page of promo codes, newest first, and the total count.
+// Status is active, inactive or expired, empty for all
+func (r *PromoRepository) GetPromoCodes(ctx context.Context, filter models.PromoCodeFilter) ([]models.PromoCode, int, error) {
+	conditions := []string{}
+	args := []any{}
+
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conditions = append(conditions, fmt.Sprintf("(code ILIKE $%d OR description ILIKE $%d)", len(args), len(args)))
+	}
+	switch filter.Status {
+	case "active":
+		conditions = append(conditions, "is_active = true AND (valid_until IS NULL OR valid_until > NOW())")
+	case "inactive":
+		conditions = append(conditions, "is_active = false")
+	case "expired":
+		conditions = append(conditions, "valid_until <= NOW()")
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var totalCount int
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM promo_codes `+where, args...).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM promo_codes %s ORDER BY id DESC LIMIT $%d OFFSET $%d`, promoCodeColumns, where, len(args)-1, len(args))
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	promos := []models.PromoCode{}
+	for rows.Next() {
+		var promo models.PromoCode
+		if err := scanPromoCode(rows, &promo); err != nil {
+			return nil, 0, err
+		}
+		promos = append(promos, promo)
+	}
+	return promos, totalCount, rows.Err()
+}
+
+func (r *PromoRepository) GetPromoCode(ctx context.Context, promoID int) (*models.PromoCode, error) {
+	var promo models.PromoCode
+	err := scanPromoCode(r.DB.QueryRow(ctx, `SELECT `+promoCodeColumns+` FROM promo_codes WHERE id = $1`, promoID), &promo)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPromoCodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &promo, nil
+}
+
+// GetPromoCodeByCode looks up a code as entered at checkout, codes are stored in upper case
+func (r *PromoRepository) GetPromoCodeByCode(ctx context.Context, code string) (*models.PromoCode, error) {
+	var promo models.PromoCode
+	err := scanPromoCode(r.DB.QueryRow(ctx, `SELECT `+promoCodeColumns+` FROM promo_codes WHERE code = UPPER($1)`, code), &promo)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPromoCodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &promo, nil
+}
+
+func promoCodeValues(req *models.PromoCodeRequest) []any {
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+	movieIDs, cinemaIDs, paymentMethodIDs := req.MovieIDs, req.CinemaIDs, req.PaymentMethodIDs
+	if movieIDs == nil {
+		movieIDs = []int{}
+	}
+	if cinemaIDs == nil {
+		cinemaIDs = []int{}
+	}
+	if paymentMethodIDs == nil {
+		paymentMethodIDs = []int{}
+	}
+	return []any{
+		strings.ToUpper(req.Code), req.Description, req.DiscountType, req.DiscountValue, req.MaxDiscount, req.MinSpend, req.UsageLimit,
+		req.PerUserLimit, req.ValidFrom, req.ValidUntil, movieIDs, cinemaIDs, paymentMethodIDs, isActive,
+	}
+}
+
+func (r *PromoRepository) CreatePromoCode(ctx context.Context, req *models.PromoCodeRequest) (*models.PromoCode, error) {
+	query := `
+	INSERT INTO promo_codes (code, description, discount_type, discount_value, max_discount, min_spend, usage_limit,
+		per_user_limit, valid_from, valid_until, movie_ids, cinema_ids, payment_method_ids, is_active)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	RETURNING ` + promoCodeColumns
+
+	var promo models.PromoCode
+	if err := scanPromoCode(r.DB.QueryRow(ctx, query, promoCodeValues(req)...), &promo); err != nil {
+		return nil, promoWriteError(err)
+	}
+	return &promo, nil
+}
+
+// UpdatePromoCode replaces the settings of a promo code, its redemptions are kept
+func (r *PromoRepository) UpdatePromoCode(ctx context.Context, promoID int, req *models.PromoCodeRequest) (*models.PromoCode, error) {
+	query := `
+	UPDATE promo_codes
+	SET
+		code = $1, description = $2, discount_type = $3, discount_value = $4, max_discount = $5, min_spend = $6, usage_limit = $7,
+		per_user_limit = $8, valid_from = $9, valid_until = $10, movie_ids = $11, cinema_ids = $12, payment_method_ids = $13,
+		is_active = $14, updated_at = NOW()
+	WHERE
+		id = $15
+	RETURNING ` + promoCodeColumns
+
+	var promo models.PromoCode
+	err := scanPromoCode(r.DB.QueryRow(ctx, query, append(promoCodeValues(req), promoID)...), &promo)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPromoCodeNotFound
+	}
+	if err != nil {
+		return nil, promoWriteError(err)
+	}
+	return &promo, nil
+}
+
+// DeletePromoCode removes a promo code that was never redeemed, used codes are deactivated instead
+func (r *PromoRepository) DeletePromoCode(ctx context.Context, promoID int) error {
+	query := `
+	DELETE FROM promo_codes
+	WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM promo_redemptions WHERE promo_code_id = $1)
+	`
+	cmd, err := r.DB.Exec(ctx, query, promoID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() > 0 {
+		return nil
+	}
+
+	if _, err := r.GetPromoCode(ctx, promoID); err != nil {
+		return err
+	}
+	return ErrPromoCodeInUse
+}
+
+// CountUserRedemptions returns how many times the user redeemed the promo code on orders still valid
+func (r *PromoRepository) CountUserRedemptions(ctx context.Context, promoID, userID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM promo_redemptions WHERE promo_code_id = $1 AND user_id = $2 AND released_at IS NULL`
+	err := r.DB.QueryRow(ctx, query, promoID, userID).Scan(&count)
+	return count, err
+}
+
+// helper, counts the redemption of the order. The promo row stays locked until the order is committed,
+// so concurrent orders can not use more than usage_limit or per_user_limit
+func redeemPromoCode(ctx context.Context, dbTx pgx.Tx, promoID, userID, orderID int, discount float64) error {
+	query := `
+	UPDATE promo_codes
+	SET used_count = used_count + 1
+	WHERE id = $1 AND is_active = true AND (usage_limit IS NULL OR used_count < usage_limit)
+	RETURNING per_user_limit
+	`
+	var perUserLimit *int
+	err := dbTx.QueryRow(ctx, query, promoID).Scan(&perUserLimit)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrPromoCodeUsedUp
+	}
+	if err != nil {
+		return err
+	}
+
+	if perUserLimit != nil {
+		var used int
+		queryUsed := `SELECT COUNT(*) FROM promo_redemptions WHERE promo_code_id = $1 AND user_id = $2 AND released_at IS NULL`
+		if err := dbTx.QueryRow(ctx, queryUsed, promoID, userID).Scan(&used); err != nil {
+			return err
+		}
+		if used >= *perUserLimit {
+			return ErrPromoCodeUserLimitHit
+		}
+	}
+
+	queryRedemption := `INSERT INTO promo_redemptions (promo_code_id, user_id, order_id, discount) VALUES ($1, $2, $3, $4)`
+	_, err = dbTx.Exec(ctx, queryRedemption, promoID, userID, orderID, discount)
+	return err
+}
+
+// helper, gives the redemption of the order back to the promo code
+func releasePromoRedemption(ctx context.Context, dbTx pgx.Tx, orderID int) error {
+	query := `UPDATE promo_redemptions SET released_at = NOW() WHERE order_id = $1 AND released_at IS NULL RETURNING promo_code_id`
+	var promoID int
+	err := dbTx.QueryRow(ctx, query, orderID).Scan(&promoID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = dbTx.Exec(ctx, `UPDATE promo_codes SET used_count = used_count - 1 WHERE id = $1 AND used_count > 0`, promoID)
+	return err
+}
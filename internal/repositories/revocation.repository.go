@@ -0,0 +1,372 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// how long the "tokens valid after" watermark of a user is cached in redis
+const tokenWatermarkCacheTTL = 10 * time.Minute
+
+// cached instead of the watermark while the user is suspended
+const userSuspendedMarker = "suspended"
+
+// revocations are written to postgres and mirrored to redis, requests are checked against redis
+// and fall back to postgres when redis is unavailable. When a redis write fails the watermark of the user is dropped,
+// the next request of the user writes the revocations from postgres back to redis
+type TokenRevocationRepository struct {
+	DB  *pgxpool.Pool
+	RDB *redis.Client
+}
+
+func NewTokenRevocationRepository(db *pgxpool.Pool, rdb *redis.Client) *TokenRevocationRepository {
+	return &TokenRevocationRepository{
+		DB:  db,
+		RDB: rdb,
+	}
+}
+
+func revokedTokenKey(jti string) string {
+	return fmt.Sprintf("auth:revoked-token:%s", jti)
+}
+
+func revokedSessionKey(sessionID int) string {
+	return fmt.Sprintf("auth:revoked-session:%d", sessionID)
+}
+
+func tokenWatermarkKey(userID int) string {
+	return fmt.Sprintf("auth:tokens-valid-after-ms:%d", userID)
+}
+
+// RevokeToken revokes one access token until it expires
+func (r *TokenRevocationRepository) RevokeToken(ctx context.Context, userID int, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	query := `
+	INSERT INTO revoked_tokens (jti, user_id, expires_at)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (jti) DO NOTHING
+	`
+	if _, err := r.DB.Exec(ctx, query, jti, userID, expiresAt); err != nil {
+		return err
+	}
+
+	// expired revocations are not needed anymore
+	if _, err := r.DB.Exec(ctx, `DELETE FROM revoked_tokens WHERE expires_at < NOW()`); err != nil {
+		log.Println("Delete expired revoked tokens error:", err)
+	}
+
+	if r.RDB != nil {
+		if err := r.RDB.Set(ctx, revokedTokenKey(jti), 1, ttl).Err(); err != nil {
+			log.Println("Redis revoke token error:", err)
+			r.forgetUserTokenState(ctx, userID)
+		}
+	}
+	return nil
+}
+
+// MarkSessionRevoked rejects the access tokens of a session revoked in user_sessions, ttl is the access token lifetime
+func (r *TokenRevocationRepository) MarkSessionRevoked(ctx context.Context, userID, sessionID int, ttl time.Duration) {
+	if r.RDB == nil {
+		return
+	}
+	if err := r.RDB.Set(ctx, revokedSessionKey(sessionID), 1, ttl).Err(); err != nil {
+		log.Println("Redis revoke session error:", err)
+		r.forgetUserTokenState(ctx, userID)
+	}
+}
+
+// RevokeAllUserTokens invalidates every token issued to the user until now and ends all their sessions
+func (r *TokenRevocationRepository) RevokeAllUserTokens(ctx context.Context, userID int, reason string) error {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	validAfter, suspended, err := revokeAllUserTokens(ctx, dbTx, userID, reason)
+	if err != nil {
+		return err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return err
+	}
+
+	r.cacheUserTokenState(ctx, userID, validAfter.UnixMilli(), suspended)
+	return nil
+}
+
+// ForgetUserTokenState drops the cached token state of the user after tokens were revoked by another repository
+func (r *TokenRevocationRepository) ForgetUserTokenState(ctx context.Context, userID int) {
+	r.forgetUserTokenState(ctx, userID)
+}
+
+// SuspendUser blocks the account and rejects all its tokens, false when the user does not exist or is already suspended
+func (r *TokenRevocationRepository) SuspendUser(ctx context.Context, userID int, reason string) (bool, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	query := `
+	UPDATE users
+	SET suspended_at = NOW(), suspended_reason = NULLIF($2, ''), tokens_valid_after = $3, updated_at = NOW()
+	WHERE id = $1 AND suspended_at IS NULL
+	`
+	cmd, err := dbTx.Exec(ctx, query, userID, reason, time.Now())
+	if err != nil {
+		return false, err
+	}
+	if cmd.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if err := revokeUserSessions(ctx, dbTx, userID, models.SessionRevokedSuspended); err != nil {
+		return false, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return false, err
+	}
+
+	r.cacheUserTokenState(ctx, userID, 0, true)
+	return true, nil
+}
+
+// ReactivateUser lifts the suspension, the user has to login again. False when the user is not suspended
+func (r *TokenRevocationRepository) ReactivateUser(ctx context.Context, userID int) (bool, error) {
+	query := `UPDATE users SET suspended_at = NULL, suspended_reason = NULL, updated_at = NOW() WHERE id = $1 AND suspended_at IS NOT NULL`
+	cmd, err := r.DB.Exec(ctx, query, userID)
+	if err != nil {
+		return false, err
+	}
+	if cmd.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	// read again from postgres on the next request
+	r.forgetUserTokenState(ctx, userID)
+	return true, nil
+}
+
+// helper, moves the watermark of the user to now and revokes all their sessions, returns the watermark and
+// whether the user is suspended
+func revokeAllUserTokens(ctx context.Context, dbTx pgx.Tx, userID int, reason string) (time.Time, bool, error) {
+	// the clock of the server issuing the tokens, truncated like the iat of the tokens
+	validAfter := time.Now().Truncate(time.Millisecond)
+	var suspended bool
+	query := `UPDATE users SET tokens_valid_after = $2, updated_at = NOW() WHERE id = $1 RETURNING suspended_at IS NOT NULL`
+	if err := dbTx.QueryRow(ctx, query, userID, validAfter).Scan(&suspended); err != nil {
+		return time.Time{}, false, err
+	}
+
+	if err := revokeUserSessions(ctx, dbTx, userID, reason); err != nil {
+		return time.Time{}, false, err
+	}
+	return validAfter, suspended, nil
+}
+
+// helper
+func revokeUserSessions(ctx context.Context, dbTx pgx.Tx, userID int, reason string) error {
+	query := `UPDATE user_sessions SET revoked_at = NOW(), revoked_reason = $2 WHERE user_id = $1 AND revoked_at IS NULL`
+	_, err := dbTx.Exec(ctx, query, userID, reason)
+	return err
+}
+
+// the cached watermark is the unix milliseconds of tokens_valid_after, or userSuspendedMarker for a suspended user.
+// A watermark that can not be written is dropped, an older one would keep accepting the revoked tokens
+func (r *TokenRevocationRepository) cacheUserTokenState(ctx context.Context, userID int, validAfter int64, suspended bool) {
+	if r.RDB == nil {
+		return
+	}
+
+	var value any = validAfter
+	if suspended {
+		value = userSuspendedMarker
+	}
+	if err := r.RDB.Set(ctx, tokenWatermarkKey(userID), value, tokenWatermarkCacheTTL).Err(); err != nil {
+		log.Println("Redis set token watermark error:", err)
+		r.forgetUserTokenState(ctx, userID)
+	}
+}
+
+// drops the cached watermark, the next request of the user restores the revocations from postgres
+func (r *TokenRevocationRepository) forgetUserTokenState(ctx context.Context, userID int) {
+	if r.RDB == nil {
+		return
+	}
+	if err := r.RDB.Del(ctx, tokenWatermarkKey(userID)).Err(); err != nil {
+		log.Println("Redis delete token watermark error:", err)
+	}
+}
+
+// IsTokenRevoked checks the jti, the session and the watermark of the user
+func (r *TokenRevocationRepository) IsTokenRevoked(ctx context.Context, userID, sessionID int, jti string, issuedAt time.Time) (bool, error) {
+	if r.RDB != nil {
+		revoked, err := r.isTokenRevokedRedis(ctx, userID, sessionID, jti, issuedAt)
+		if err == nil {
+			return revoked, nil
+		}
+		log.Println("Redis token revocation error, back to DB : ", err)
+	}
+
+	return r.isTokenRevokedDB(ctx, userID, sessionID, jti, issuedAt)
+}
+
+func (r *TokenRevocationRepository) isTokenRevokedRedis(ctx context.Context, userID, sessionID int, jti string, issuedAt time.Time) (bool, error) {
+	pipe := r.RDB.Pipeline()
+	tokenCmd := pipe.Exists(ctx, revokedTokenKey(jti))
+	sessionCmd := pipe.Exists(ctx, revokedSessionKey(sessionID))
+	watermarkCmd := pipe.Get(ctx, tokenWatermarkKey(userID))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return false, err
+	}
+
+	if tokenCmd.Val() > 0 || sessionCmd.Val() > 0 {
+		return true, nil
+	}
+
+	watermark, err := watermarkCmd.Result()
+	if errors.Is(err, redis.Nil) {
+		state, err := r.restoreUserTokenState(ctx, userID)
+		if err != nil {
+			return false, err
+		}
+		return state.isRevoked(sessionID, jti, issuedAt), nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if watermark == userSuspendedMarker {
+		return true, nil
+	}
+	validAfter, err := strconv.ParseInt(watermark, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	return issuedAt.UnixMilli() < validAfter, nil
+}
+
+// the revocations of a user in postgres
+type userTokenState struct {
+	validAfter *time.Time
+	suspended  bool
+	tokens     map[string]time.Time // jti -> token expiry
+	sessions   map[int]time.Time    // session id -> session expiry
+}
+
+func (s *userTokenState) isRevoked(sessionID int, jti string, issuedAt time.Time) bool {
+	if _, ok := s.tokens[jti]; ok {
+		return true
+	}
+	if _, ok := s.sessions[sessionID]; ok {
+		return true
+	}
+	return s.suspended || (s.validAfter != nil && issuedBefore(issuedAt, *s.validAfter))
+}
+
+// helper, the iat of a token only has milliseconds, a token issued in the millisecond of the watermark is valid
+func issuedBefore(issuedAt, validAfter time.Time) bool {
+	return issuedAt.UnixMilli() < validAfter.UnixMilli()
+}
+
+// restoreUserTokenState writes the unexpired revoked tokens and sessions of the user back to redis, then the watermark.
+// The watermark is only set when missing, a newer one written by a revocation is kept
+func (r *TokenRevocationRepository) restoreUserTokenState(ctx context.Context, userID int) (*userTokenState, error) {
+	state := userTokenState{tokens: map[string]time.Time{}, sessions: map[int]time.Time{}}
+	// a deleted account is rejected like a suspended one
+	query := `SELECT tokens_valid_after, suspended_at IS NOT NULL OR deleted_at IS NOT NULL FROM users WHERE id = $1`
+	err := r.DB.QueryRow(ctx, query, userID).Scan(&state.validAfter, &state.suspended)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+	SELECT jti, NULL::int, expires_at FROM revoked_tokens WHERE user_id = $1 AND expires_at > NOW()
+	UNION ALL
+	SELECT NULL, id, expires_at FROM user_sessions WHERE user_id = $1 AND revoked_at IS NOT NULL AND expires_at > NOW()
+	`
+	rows, err := r.DB.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var jti *string
+		var sessionID *int
+		var expiresAt time.Time
+		if err := rows.Scan(&jti, &sessionID, &expiresAt); err != nil {
+			return nil, err
+		}
+		if jti != nil {
+			state.tokens[*jti] = expiresAt
+		} else if sessionID != nil {
+			state.sessions[*sessionID] = expiresAt
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	pipe := r.RDB.Pipeline()
+	for jti, expiresAt := range state.tokens {
+		pipe.Set(ctx, revokedTokenKey(jti), 1, time.Until(expiresAt))
+	}
+	for sessionID, expiresAt := range state.sessions {
+		pipe.Set(ctx, revokedSessionKey(sessionID), 1, time.Until(expiresAt))
+	}
+	if len(state.tokens) > 0 || len(state.sessions) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var watermark any = int64(0)
+	if state.suspended {
+		watermark = userSuspendedMarker
+	} else if state.validAfter != nil {
+		watermark = state.validAfter.UnixMilli()
+	}
+	if err := r.RDB.SetNX(ctx, tokenWatermarkKey(userID), watermark, tokenWatermarkCacheTTL).Err(); err != nil {
+		log.Println("Redis set token watermark error:", err)
+	}
+	return &state, nil
+}
+
+func (r *TokenRevocationRepository) isTokenRevokedDB(ctx context.Context, userID, sessionID int, jti string, issuedAt time.Time) (bool, error) {
+	query := `
+	SELECT
+		EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1),
+		EXISTS (SELECT 1 FROM user_sessions WHERE id = $2 AND revoked_at IS NOT NULL),
+		(SELECT tokens_valid_after FROM users WHERE id = $3),
+		EXISTS (SELECT 1 FROM users WHERE id = $3 AND (suspended_at IS NOT NULL OR deleted_at IS NOT NULL))
+	`
+
+	var tokenRevoked, sessionRevoked, suspended bool
+	var validAfter *time.Time
+	err := r.DB.QueryRow(ctx, query, jti, sessionID, userID).Scan(&tokenRevoked, &sessionRevoked, &validAfter, &suspended)
+	if err != nil {
+		return false, err
+	}
+
+	if tokenRevoked || sessionRevoked || suspended {
+		return true, nil
+	}
+	return validAfter != nil && issuedBefore(issuedAt, *validAfter), nil
+}
@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+)
+
+// RefreshTokenReusedError is returned when an already rotated refresh token is used again, the whole session is revoked
+type RefreshTokenReusedError struct {
+	UserID    int
+	SessionID int
+}
+
+func (e *RefreshTokenReusedError) Error() string {
+	return fmt.Sprintf("refresh token reused, session %d revoked", e.SessionID)
+}
+
+type SessionRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewSessionRepository(db *pgxpool.Pool) *SessionRepository {
+	return &SessionRepository{
+		DB: db,
+	}
+}
+
+// CreateSession starts a session with its first refresh token
+func (r *SessionRepository) CreateSession(ctx context.Context, session models.NewSession) (int, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	querySession := `
+	INSERT INTO user_sessions (user_id, user_agent, ip_address, expires_at, mfa)
+	VALUES ($1, LEFT($2, 255), $3, $4, $5)
+	RETURNING id
+	`
+	var sessionID int
+	err = dbTx.QueryRow(ctx, querySession, session.UserID, session.UserAgent, session.IPAddress, session.ExpiresAt, session.MFA).Scan(&sessionID)
+	if err != nil {
+		return 0, err
+	}
+
+	queryToken := `INSERT INTO refresh_tokens (session_id, token_hash, expires_at) VALUES ($1, $2, $3)`
+	if _, err := dbTx.Exec(ctx, queryToken, sessionID, session.TokenHash, session.ExpiresAt); err != nil {
+		return 0, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return sessionID, nil
+}
+
+// RotateRefreshToken replaces a refresh token with a new one in the same session and returns the session.
+// A token that was already rotated revokes the session, the token has been stolen or replayed.
+func (r *SessionRepository) RotateRefreshToken(ctx context.Context, tokenHash, newTokenHash string, expiresAt time.Time) (*models.AuthSession, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	query := `
+	SELECT
+		rt.id,
+		rt.session_id,
+		rt.used_at IS NOT NULL,
+		rt.expires_at <= NOW(),
+		s.revoked_at IS NOT NULL,
+		s.mfa,
+		u.id,
+		u.email,
+		u.role
+	FROM
+		refresh_tokens rt
+		JOIN user_sessions s ON rt.session_id = s.id
+		JOIN users u ON s.user_id = u.id
+	WHERE
+		rt.token_hash = $1
+	FOR UPDATE OF rt, s
+	`
+
+	var tokenID, sessionID int
+	var used, expired, revoked, mfa bool
+	var user models.User
+	err = dbTx.QueryRow(ctx, query, tokenHash).Scan(&tokenID, &sessionID, &used, &expired, &revoked, &mfa, &user.ID, &user.Email, &user.Role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if revoked {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	if used {
+		if err := revokeSession(ctx, dbTx, sessionID, models.SessionRevokedTokenReuse); err != nil {
+			return nil, err
+		}
+		if err := dbTx.Commit(ctx); err != nil {
+			return nil, err
+		}
+		return nil, &RefreshTokenReusedError{UserID: user.ID, SessionID: sessionID}
+	}
+
+	if expired {
+		return nil, ErrRefreshTokenExpired
+	}
+
+	if _, err := dbTx.Exec(ctx, `UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1`, tokenID); err != nil {
+		return nil, err
+	}
+
+	queryToken := `INSERT INTO refresh_tokens (session_id, token_hash, expires_at) VALUES ($1, $2, $3)`
+	if _, err := dbTx.Exec(ctx, queryToken, sessionID, newTokenHash, expiresAt); err != nil {
+		return nil, err
+	}
+
+	querySession := `UPDATE user_sessions SET last_used_at = NOW(), expires_at = $2 WHERE id = $1`
+	if _, err := dbTx.Exec(ctx, querySession, sessionID, expiresAt); err != nil {
+		return nil, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &models.AuthSession{ID: sessionID, MFA: mfa, User: user}, nil
+}
+
+// helper
+func revokeSession(ctx context.Context, dbTx pgx.Tx, sessionID int, reason string) error {
+	query := `UPDATE user_sessions SET revoked_at = NOW(), revoked_reason = $2 WHERE id = $1 AND revoked_at IS NULL`
+	_, err := dbTx.Exec(ctx, query, sessionID, reason)
+	return err
+}
+
+// GetUserSessions returns the active sessions of the user, newest first
+func (r *SessionRepository) GetUserSessions(ctx context.Context, userID int) ([]models.Session, error) {
+	query := `
+	SELECT id, user_agent, ip_address, created_at, last_used_at, expires_at
+	FROM user_sessions
+	WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+	ORDER BY last_used_at DESC
+	`
+
+	rows, err := r.DB.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []models.Session{}
+	for rows.Next() {
+		var session models.Session
+		if err := rows.Scan(&session.ID, &session.UserAgent, &session.IPAddress, &session.CreatedAt, &session.LastUsedAt, &session.ExpiresAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// RevokeSession revokes an active session of the user, false when the session does not exist or is already revoked
+func (r *SessionRepository) RevokeSession(ctx context.Context, userID, sessionID int, reason string) (bool, error) {
+	query := `UPDATE user_sessions SET revoked_at = NOW(), revoked_reason = $3 WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	cmd, err := r.DB.Exec(ctx, query, sessionID, userID, reason)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+// MarkSessionMFA records that the user passed a second factor in the session
+func (r *SessionRepository) MarkSessionMFA(ctx context.Context, userID, sessionID int) error {
+	_, err := r.DB.Exec(ctx, `UPDATE user_sessions SET mfa = true WHERE id = $1 AND user_id = $2`, sessionID, userID)
+	return err
+}
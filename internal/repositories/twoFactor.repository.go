@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TwoFactorRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewTwoFactorRepository(db *pgxpool.Pool) *TwoFactorRepository {
+	return &TwoFactorRepository{
+		DB: db,
+	}
+}
+
+func (r *TwoFactorRepository) GetSecret(ctx context.Context, userID int) (*models.TwoFactorSecret, error) {
+	var secret models.TwoFactorSecret
+	err := r.DB.QueryRow(ctx, `SELECT totp_secret, totp_enabled_at FROM users WHERE id = $1`, userID).Scan(&secret.Secret, &secret.EnabledAt)
+	if err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+func (r *TwoFactorRepository) CountRecoveryCodes(ctx context.Context, userID int) (int, error) {
+	var count int
+	err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM user_recovery_codes WHERE user_id = $1 AND used_at IS NULL`, userID).Scan(&count)
+	return count, err
+}
+
+// SetPendingSecret stores a new secret until it is confirmed, false when 2FA is already enabled
+func (r *TwoFactorRepository) SetPendingSecret(ctx context.Context, userID int, encryptedSecret string) (bool, error) {
+	query := `UPDATE users SET totp_secret = $2, totp_last_step = NULL, updated_at = NOW() WHERE id = $1 AND totp_enabled_at IS NULL`
+	cmd, err := r.DB.Exec(ctx, query, userID, encryptedSecret)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+// helper
+func replaceRecoveryCodes(ctx context.Context, dbTx pgx.Tx, userID int, codeHashes []string) error {
+	if _, err := dbTx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO user_recovery_codes (user_id, code_hash) SELECT $1, UNNEST($2::varchar[])`
+	_, err := dbTx.Exec(ctx, query, userID, codeHashes)
+	return err
+}
+
+// EnableTwoFactor turns 2FA on with the confirmed secret, step is the time step of the confirming code.
+// False when 2FA is already enabled
+func (r *TwoFactorRepository) EnableTwoFactor(ctx context.Context, userID int, step int64, codeHashes []string) (bool, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	query := `
+	UPDATE users
+	SET totp_enabled_at = NOW(), totp_last_step = $2, updated_at = NOW()
+	WHERE id = $1 AND totp_secret IS NOT NULL AND totp_enabled_at IS NULL
+	`
+	cmd, err := dbTx.Exec(ctx, query, userID, step)
+	if err != nil {
+		return false, err
+	}
+	if cmd.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if err := replaceRecoveryCodes(ctx, dbTx, userID, codeHashes); err != nil {
+		return false, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *TwoFactorRepository) DisableTwoFactor(ctx context.Context, userID int) error {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	query := `UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = NULL, updated_at = NOW() WHERE id = $1`
+	if _, err := dbTx.Exec(ctx, query, userID); err != nil {
+		return err
+	}
+	if _, err := dbTx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return dbTx.Commit(ctx)
+}
+
+// RegenerateRecoveryCodes replaces every recovery code of the user
+func (r *TwoFactorRepository) RegenerateRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	if err := replaceRecoveryCodes(ctx, dbTx, userID, codeHashes); err != nil {
+		return err
+	}
+	return dbTx.Commit(ctx)
+}
+
+// UseTOTPStep accepts a verified code once, false when the step or a later one was already used
+func (r *TwoFactorRepository) UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	query := `UPDATE users SET totp_last_step = $2 WHERE id = $1 AND (totp_last_step IS NULL OR totp_last_step < $2)`
+	cmd, err := r.DB.Exec(ctx, query, userID, step)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+// UseRecoveryCode marks an unused recovery code of the user as used, false when there is none
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	query := `UPDATE user_recovery_codes SET used_at = NOW() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+	cmd, err := r.DB.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+// UseLoginChallenge accepts a login challenge once, false when its nonce was already used
+func (r *TwoFactorRepository) UseLoginChallenge(ctx context.Context, userID int, nonceHash string, expiresAt time.Time) (bool, error) {
+	// used challenges are only kept while they could still be replayed
+	if _, err := r.DB.Exec(ctx, `DELETE FROM used_login_challenges WHERE expires_at <= NOW()`); err != nil {
+		return false, err
+	}
+
+	query := `
+	INSERT INTO used_login_challenges (nonce_hash, user_id, expires_at)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (nonce_hash) DO NOTHING
+	`
+	cmd, err := r.DB.Exec(ctx, query, nonceHash, userID, expiresAt)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() > 0, nil
+}
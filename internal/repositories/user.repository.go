@@ -2,12 +2,19 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+var (
+	ErrResetTokenInvalid = errors.New("invalid or expired reset token")
+)
+
 type UserRepository struct {
 	DB *pgxpool.Pool
 }
@@ -51,17 +58,136 @@ func (r *UserRepository) RegisterUser(ctx context.Context, user *models.User) er
 		return fmt.Errorf("commit db transaction failed : %w", err)
 	}
 
+	user.ID = userID
 	return nil
 }
 
 func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	user := &models.User{}
 
-	query := `SELECT id, email, password, role FROM users WHERE email=$1`
+	query := `SELECT id, email, password, role, email_verified_at, suspended_at, totp_enabled_at FROM users WHERE email=$1`
 
-	err := r.DB.QueryRow(ctx, query, email).Scan(&user.ID, &user.Email, &user.Password, &user.Role)
+	err := r.DB.QueryRow(ctx, query, email).Scan(&user.ID, &user.Email, &user.Password, &user.Role, &user.EmailVerifiedAt, &user.SuspendedAt, &user.TwoFactorEnabledAt)
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
+
+func (r *UserRepository) GetUserByID(ctx context.Context, userID int) (*models.User, error) {
+	user := &models.User{}
+
+	query := `SELECT id, email, password, role, email_verified_at, suspended_at, totp_enabled_at FROM users WHERE id=$1`
+
+	err := r.DB.QueryRow(ctx, query, userID).Scan(&user.ID, &user.Email, &user.Password, &user.Role, &user.EmailVerifiedAt, &user.SuspendedAt, &user.TwoFactorEnabledAt)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// VerifyEmail marks the email of the user as verified, false when the user or email does not match anymore
+func (r *UserRepository) VerifyEmail(ctx context.Context, userID int, email string) (bool, error) {
+	query := `UPDATE users SET email_verified_at = COALESCE(email_verified_at, NOW()), updated_at = NOW() WHERE id = $1 AND email = $2`
+	cmd, err := r.DB.Exec(ctx, query, userID, email)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+// MarkVerificationSent records a verification email, false when the last one was sent less than cooldown ago
+func (r *UserRepository) MarkVerificationSent(ctx context.Context, userID int, cooldown time.Duration) (bool, error) {
+	query := `
+	UPDATE users
+	SET verification_sent_at = NOW()
+	WHERE
+		id = $1
+		AND email_verified_at IS NULL
+		AND (verification_sent_at IS NULL OR verification_sent_at < NOW() - $2::interval)
+	`
+	interval := fmt.Sprintf("%d milliseconds", cooldown.Milliseconds())
+	cmd, err := r.DB.Exec(ctx, query, userID, interval)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+func (r *UserRepository) IsEmailVerified(ctx context.Context, userID int) (bool, error) {
+	var verified bool
+	err := r.DB.QueryRow(ctx, `SELECT email_verified_at IS NOT NULL FROM users WHERE id = $1`, userID).Scan(&verified)
+	return verified, err
+}
+
+// CreatePasswordResetToken stores a reset token and invalidates the previous ones of the user,
+// false when the last token was created less than cooldown ago
+func (r *UserRepository) CreatePasswordResetToken(ctx context.Context, userID int, tokenHash string, expiresAt time.Time, cooldown time.Duration) (bool, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	// lock the user so concurrent requests are counted once
+	if _, err := dbTx.Exec(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+		return false, err
+	}
+
+	var recent bool
+	queryRecent := `SELECT EXISTS (SELECT 1 FROM password_reset_tokens WHERE user_id = $1 AND created_at > NOW() - $2::interval)`
+	interval := fmt.Sprintf("%d milliseconds", cooldown.Milliseconds())
+	if err := dbTx.QueryRow(ctx, queryRecent, userID, interval).Scan(&recent); err != nil {
+		return false, err
+	}
+	if recent {
+		return false, nil
+	}
+
+	if _, err := dbTx.Exec(ctx, `UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`, userID); err != nil {
+		return false, err
+	}
+
+	queryToken := `INSERT INTO password_reset_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3)`
+	if _, err := dbTx.Exec(ctx, queryToken, userID, tokenHash, expiresAt); err != nil {
+		return false, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ResetPassword uses a reset token once and sets the new password, returns the user of the token
+func (r *UserRepository) ResetPassword(ctx context.Context, tokenHash, hashedPassword string) (int, error) {
+	dbTx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed begin db transaction : %w", err)
+	}
+	defer dbTx.Rollback(ctx)
+
+	query := `
+	UPDATE password_reset_tokens
+	SET used_at = NOW()
+	WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+	RETURNING user_id
+	`
+	var userID int
+	err = dbTx.QueryRow(ctx, query, tokenHash).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrResetTokenInvalid
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := dbTx.Exec(ctx, `UPDATE users SET password = $1, updated_at = NOW() WHERE id = $2`, hashedPassword, userID); err != nil {
+		return 0, err
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
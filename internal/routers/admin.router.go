@@ -3,21 +3,74 @@ package routers
 import (
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/handlers"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/middlewares"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 )
 
-func AdminRouter(r *gin.Engine, adminHandler *handlers.AdminHandler, jwtManager *utils.JWTManager, rdb *redis.Client) {
+func AdminRouter(r *gin.Engine, adminHandler *handlers.AdminHandler, catalogHandler *handlers.CatalogHandler, ordersHandler *handlers.OrdersHandler, promoHandler *handlers.PromoHandler, jwtManager *utils.JWTManager, revocations *repositories.TokenRevocationRepository, permissions *repositories.PermissionRepository, twoFactorRequired bool) {
 	adminRoutes := r.Group("/admin")
-	adminRoutes.Use(middlewares.VerifyToken(jwtManager, rdb))
-	adminRoutes.Use(middlewares.AuthMiddleware("admin"))
+	adminRoutes.Use(middlewares.VerifyToken(jwtManager, revocations))
+	if twoFactorRequired {
+		adminRoutes.Use(middlewares.RequireTwoFactor(models.RoleAdmin))
+	}
 
-	adminRoutes.GET("/movies", adminHandler.GetAllMovies)
-	adminRoutes.POST("/movies/add", adminHandler.AddMovies)
-	adminRoutes.GET("/movies/schedule", adminHandler.GetMovieSchedule)
-	adminRoutes.POST("/movies/cinemaschedule/add", adminHandler.AddCinemaSchedule)
-	adminRoutes.DELETE("/movies/delete/:id", adminHandler.DeleteMovies)
-	adminRoutes.PATCH("/movies/edit/:id", adminHandler.UpdateMovies)
-	adminRoutes.GET("/movies/:movieEditId/edit-details", adminHandler.GetMovieEditDetail)
+	moviesWrite := middlewares.RequirePermission(permissions, models.PermissionMoviesWrite)
+	schedulesWrite := middlewares.RequirePermission(permissions, models.PermissionSchedulesWrite)
+	auditoriumsWrite := middlewares.RequirePermission(permissions, models.PermissionAuditoriumsWrite)
+	ordersRefund := middlewares.RequirePermission(permissions, models.PermissionOrdersRefund)
+	usersManage := middlewares.RequirePermission(permissions, models.PermissionUsersManage)
+	promosManage := middlewares.RequirePermission(permissions, models.PermissionPromosManage)
+	cinemasWrite := middlewares.RequirePermission(permissions, models.PermissionCinemasWrite)
+	paymentsManage := middlewares.RequirePermission(permissions, models.PermissionPaymentsManage)
+
+	adminRoutes.GET("/movies", moviesWrite, adminHandler.GetAllMovies)
+	adminRoutes.POST("/movies/add", moviesWrite, adminHandler.AddMovies)
+	adminRoutes.GET("/movies/schedule", schedulesWrite, adminHandler.GetMovieSchedule)
+	adminRoutes.POST("/movies/cinemaschedule/add", schedulesWrite, adminHandler.AddCinemaSchedule)
+	adminRoutes.DELETE("/movies/delete/:id", moviesWrite, adminHandler.DeleteMovies)
+	adminRoutes.PATCH("/movies/edit/:id", moviesWrite, adminHandler.UpdateMovies)
+	adminRoutes.GET("/movies/:movieEditId/edit-details", moviesWrite, adminHandler.GetMovieEditDetail)
+	adminRoutes.GET("/genres", moviesWrite, catalogHandler.GetGenres)
+	adminRoutes.POST("/genres", moviesWrite, catalogHandler.AddGenre)
+	adminRoutes.PATCH("/genres/:id", moviesWrite, catalogHandler.UpdateGenre)
+	adminRoutes.DELETE("/genres/:id", moviesWrite, catalogHandler.DeleteGenre)
+	adminRoutes.POST("/genres/:id/merge", moviesWrite, catalogHandler.MergeGenres)
+	adminRoutes.GET("/casts", moviesWrite, catalogHandler.GetCasts)
+	adminRoutes.POST("/casts", moviesWrite, catalogHandler.AddCast)
+	adminRoutes.PATCH("/casts/:id", moviesWrite, catalogHandler.UpdateCast)
+	adminRoutes.DELETE("/casts/:id", moviesWrite, catalogHandler.DeleteCast)
+	adminRoutes.POST("/casts/:id/merge", moviesWrite, catalogHandler.MergeCasts)
+	adminRoutes.GET("/directors", moviesWrite, catalogHandler.GetDirectors)
+	adminRoutes.POST("/directors", moviesWrite, catalogHandler.AddDirector)
+	adminRoutes.PATCH("/directors/:id", moviesWrite, catalogHandler.UpdateDirector)
+	adminRoutes.DELETE("/directors/:id", moviesWrite, catalogHandler.DeleteDirector)
+	adminRoutes.POST("/directors/:id/merge", moviesWrite, catalogHandler.MergeDirectors)
+	adminRoutes.GET("/cinemas", cinemasWrite, adminHandler.GetCinemas)
+	adminRoutes.POST("/cinemas", cinemasWrite, adminHandler.AddCinema)
+	adminRoutes.PATCH("/cinemas/:id", cinemasWrite, adminHandler.UpdateCinema)
+	adminRoutes.DELETE("/cinemas/:id", cinemasWrite, adminHandler.DeleteCinema)
+	adminRoutes.GET("/locations", cinemasWrite, adminHandler.GetLocations)
+	adminRoutes.POST("/locations", cinemasWrite, adminHandler.AddLocation)
+	adminRoutes.PATCH("/locations/:id", cinemasWrite, adminHandler.UpdateLocation)
+	adminRoutes.DELETE("/locations/:id", cinemasWrite, adminHandler.DeleteLocation)
+	adminRoutes.GET("/payment-methods", paymentsManage, adminHandler.GetPaymentMethods)
+	adminRoutes.POST("/payment-methods", paymentsManage, adminHandler.AddPaymentMethod)
+	adminRoutes.PATCH("/payment-methods/:id", paymentsManage, adminHandler.UpdatePaymentMethod)
+	adminRoutes.DELETE("/payment-methods/:id", paymentsManage, adminHandler.DeletePaymentMethod)
+	adminRoutes.GET("/cinemas/:id/auditoriums", auditoriumsWrite, adminHandler.GetAuditoriums)
+	adminRoutes.POST("/cinemas/:id/auditoriums/add", auditoriumsWrite, adminHandler.AddAuditorium)
+	adminRoutes.POST("/orders/:id/cancel", ordersRefund, ordersHandler.AdminCancelOrder)
+	adminRoutes.GET("/roles", usersManage, adminHandler.GetRoles)
+	adminRoutes.GET("/users", usersManage, adminHandler.GetUsers)
+	adminRoutes.PATCH("/users/:id/role", usersManage, adminHandler.UpdateUserRole)
+	adminRoutes.POST("/users/:id/suspend", usersManage, adminHandler.SuspendUser)
+	adminRoutes.POST("/users/:id/reactivate", usersManage, adminHandler.ReactivateUser)
+	adminRoutes.POST("/users/:id/unlock", usersManage, adminHandler.UnlockUser)
+	adminRoutes.GET("/promo-codes", promosManage, promoHandler.GetPromoCodes)
+	adminRoutes.POST("/promo-codes", promosManage, promoHandler.CreatePromoCode)
+	adminRoutes.GET("/promo-codes/:id", promosManage, promoHandler.GetPromoCode)
+	adminRoutes.PUT("/promo-codes/:id", promosManage, promoHandler.UpdatePromoCode)
+	adminRoutes.DELETE("/promo-codes/:id", promosManage, promoHandler.DeletePromoCode)
 }
@@ -3,14 +3,34 @@ package routers
 import (
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/handlers"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/middlewares"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 )
 
-func AuthRouter(r *gin.Engine, jwtManager *utils.JWTManager, rdb *redis.Client, authHandler *handlers.AuthHandler) {
+func AuthRouter(r *gin.Engine, jwtManager *utils.JWTManager, revocations *repositories.TokenRevocationRepository, authHandler *handlers.AuthHandler, twoFactorHandler *handlers.TwoFactorHandler, oidcHandler *handlers.OIDCHandler) {
 	authRoutes := r.Group("/auth")
 	authRoutes.POST("/register", authHandler.Register)
 	authRoutes.POST("/login", authHandler.Login)
-	authRoutes.POST("/logout", middlewares.VerifyToken(jwtManager, rdb), middlewares.AuthMiddleware("user", "admin"), authHandler.Logout)
+	authRoutes.POST("/login/2fa", authHandler.LoginTwoFactor)
+	authRoutes.POST("/refresh", authHandler.Refresh)
+	authRoutes.POST("/verify-email", authHandler.VerifyEmail)
+	authRoutes.POST("/resend-verification", authHandler.ResendVerification)
+	authRoutes.POST("/forgot-password", authHandler.ForgotPassword)
+	authRoutes.POST("/reset-password", authHandler.ResetPassword)
+	authRoutes.GET("/oidc/providers", oidcHandler.GetProviders)
+	authRoutes.GET("/oidc/:provider/authorize", oidcHandler.Authorize)
+	authRoutes.POST("/oidc/:provider/callback", oidcHandler.Callback)
+
+	sessionRoutes := authRoutes.Group("")
+	sessionRoutes.Use(middlewares.VerifyToken(jwtManager, revocations))
+	sessionRoutes.POST("/logout", authHandler.Logout)
+	sessionRoutes.POST("/logout-all", authHandler.LogoutAll)
+	sessionRoutes.GET("/sessions", authHandler.GetSessions)
+	sessionRoutes.DELETE("/sessions/:id", authHandler.RevokeSession)
+	sessionRoutes.GET("/2fa", twoFactorHandler.GetStatus)
+	sessionRoutes.POST("/2fa/setup", twoFactorHandler.Setup)
+	sessionRoutes.POST("/2fa/enable", twoFactorHandler.Enable)
+	sessionRoutes.POST("/2fa/disable", twoFactorHandler.Disable)
+	sessionRoutes.POST("/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
 }
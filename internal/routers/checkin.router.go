@@ -0,0 +1,17 @@
+package routers
+
+import (
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/handlers"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/middlewares"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func CheckInRouter(r *gin.Engine, checkInHandler *handlers.CheckInHandler, jwtManager *utils.JWTManager, revocations *repositories.TokenRevocationRepository, permissions *repositories.PermissionRepository) {
+	checkInRoutes := r.Group("/checkin")
+	checkInRoutes.Use(middlewares.VerifyToken(jwtManager, revocations))
+	checkInRoutes.Use(middlewares.RequirePermission(permissions, models.PermissionCheckInScan))
+	checkInRoutes.POST("", checkInHandler.CheckIn)
+}
@@ -3,15 +3,22 @@ package routers
 import (
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/handlers"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/middlewares"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 )
 
-func OrdersRouter(r *gin.Engine, ordersHandler *handlers.OrdersHandler, jwtManager *utils.JWTManager, rdb *redis.Client) {
+func OrdersRouter(r *gin.Engine, ordersHandler *handlers.OrdersHandler, jwtManager *utils.JWTManager, revocations *repositories.TokenRevocationRepository, users *repositories.UserRepository) {
 	ordersRoutes := r.Group("/orders")
-	ordersRoutes.Use(middlewares.VerifyToken(jwtManager, rdb))
-	ordersRoutes.Use(middlewares.AuthMiddleware("user"))
-	ordersRoutes.POST("/", ordersHandler.CreateOrder)
+	// every role can order tickets, not only user
+	ordersRoutes.Use(middlewares.VerifyToken(jwtManager, revocations))
+	ordersRoutes.POST("/", middlewares.RequireVerifiedEmail(users), ordersHandler.CreateOrder)
+	ordersRoutes.POST("/quote", ordersHandler.QuoteOrder)
 	ordersRoutes.GET("/history", ordersHandler.GetOrdersHistory)
+	ordersRoutes.POST("/:id/cancel", ordersHandler.CancelOrder)
+	ordersRoutes.GET("/:id", ordersHandler.GetOrderDetail)
+	ordersRoutes.GET("/:id/qr", ordersHandler.GetOrderQRCode)
+	ordersRoutes.GET("/:id/ticket.pdf", ordersHandler.GetOrderTicketPDF)
+	ordersRoutes.POST("/holds", middlewares.RequireVerifiedEmail(users), ordersHandler.HoldSeats)
+	ordersRoutes.DELETE("/holds/:cinemas_schedule_id", ordersHandler.ReleaseSeats)
 }
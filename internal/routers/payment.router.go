@@ -0,0 +1,14 @@
+package routers
+
+import (
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func PaymentRouter(r *gin.Engine, paymentHandler *handlers.PaymentHandler, simulator bool) {
+	paymentRoutes := r.Group("/payments")
+	paymentRoutes.POST("/webhook/:provider", paymentHandler.PaymentWebhook)
+	if simulator {
+		paymentRoutes.POST("/fake/:reference", paymentHandler.SimulatePayment)
+	}
+}
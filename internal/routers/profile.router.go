@@ -3,17 +3,21 @@ package routers
 import (
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/handlers"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/middlewares"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 )
 
-func ProfileRouter(r *gin.Engine, profileHandler *handlers.ProfileHandler, jwtManager *utils.JWTManager, rdb *redis.Client) {
+func ProfileRouter(r *gin.Engine, profileHandler *handlers.ProfileHandler, loyaltyHandler *handlers.LoyaltyHandler, jwtManager *utils.JWTManager, revocations *repositories.TokenRevocationRepository) {
 	profileRoutes := r.Group("/profile")
-	profileRoutes.Use(middlewares.VerifyToken(jwtManager, rdb))
-	profileRoutes.Use(middlewares.AuthMiddleware("user"))
+	// every role can manage their own profile, not only user
+	profileRoutes.Use(middlewares.VerifyToken(jwtManager, revocations))
 
 	profileRoutes.GET("", profileHandler.GetProfile)
+	profileRoutes.DELETE("", profileHandler.DeleteAccount)
+	profileRoutes.GET("/export", profileHandler.ExportData)
+	profileRoutes.GET("/points", loyaltyHandler.GetPoints)
+	profileRoutes.GET("/points/history", loyaltyHandler.GetPointsHistory)
 	profileRoutes.PATCH("/edit", profileHandler.UpdateProfile)
 	profileRoutes.PATCH("/editpassword", profileHandler.UpdatePassword)
 }
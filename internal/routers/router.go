@@ -3,10 +3,14 @@ package routers
 import (
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/docs"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/configs"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/handlers"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/middlewares"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
 	"github.com/gin-gonic/gin"
@@ -20,38 +24,161 @@ func MainRouter(db *pgxpool.Pool, rdb *redis.Client) *gin.Engine {
 	r := gin.Default()
 	r.Use(middlewares.CORSmiddleware)
 
+	// X-Forwarded-For is only read from these proxies, the client IP counts failed logins
+	var trustedProxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES env variable:", err)
+	}
+
 	// JWT
 	jwtSecret := os.Getenv("JWTKEY")
 	if jwtSecret == "" {
 		log.Fatal("JWT Key env variable not set")
 	}
-	jwtManager := utils.NewJWTManager(jwtSecret)
+	accessTTL := time.Duration(configs.GetEnvInt("ACCESS_TOKEN_MINUTES", 15)) * time.Minute
+	refreshTTL := time.Duration(configs.GetEnvInt("REFRESH_TOKEN_DAYS", 30)) * 24 * time.Hour
+	jwtManager := utils.NewJWTManager(jwtSecret, accessTTL)
+
+	// token revocation, used by every authenticated route
+	revocationRepo := repositories.NewTokenRevocationRepository(db, rdb)
+	// role permissions, used by the admin and check-in routes
+	permissionRepo := repositories.NewPermissionRepository(db, rdb)
+	// failed login counters
+	loginAttemptRepo := repositories.NewLoginAttemptRepository(rdb, models.LoginThrottleConfig{
+		MaxAccountAttempts: configs.GetEnvInt("LOGIN_MAX_ATTEMPTS", 5),
+		MaxIPAttempts:      configs.GetEnvInt("LOGIN_MAX_IP_ATTEMPTS", 20),
+		BaseLockout:        time.Duration(max(configs.GetEnvInt("LOGIN_LOCKOUT_SECONDS", 60), 1)) * time.Second,
+		MaxLockout:         time.Duration(max(configs.GetEnvInt("LOGIN_MAX_LOCKOUT_MINUTES", 60), 1)) * time.Minute,
+		Window:             time.Duration(configs.GetEnvInt("LOGIN_ATTEMPT_WINDOW_MINUTES", 15)) * time.Minute,
+	})
 
 	// Movies repo & handlers
 	movieRepo := repositories.NewMovieRepository(db)
 	movieHandler := handlers.NewMoviesHandler(movieRepo, rdb)
 	// Profile repo & handlers
 	profileRepo := repositories.NewProfileRepository(db)
-	profileHandler := handlers.NewProfileHandler(profileRepo, rdb)
+	// Seat holds repo
+	seatHoldRepo := repositories.NewSeatHoldRepository(db, rdb)
+	seatHoldTTL := time.Duration(configs.GetEnvInt("SEAT_HOLD_MINUTES", 10)) * time.Minute
+	// Payment gateway, Midtrans charges the payment methods listed in MIDTRANS_PROVIDERS,
+	// the simulator handles every provider in development
+	paymentSecret := secretFromEnv("PAYMENT_WEBHOOK_SECRET", jwtSecret, "payment webhook")
+	paymentTTL := time.Duration(configs.GetEnvInt("PAYMENT_EXPIRY_MINUTES", 15)) * time.Minute
+	fakePayment := utils.NewFakePaymentProvider(paymentSecret, paymentTTL)
+	paymentGateway := utils.NewPaymentGateway()
+	paymentSimulator := os.Getenv("PAYMENT_SIMULATOR") == "true"
+	if serverKey := os.Getenv("MIDTRANS_SERVER_KEY"); serverKey != "" {
+		methodProviders := configs.GetEnvList("MIDTRANS_PROVIDERS")
+		if len(methodProviders) == 0 {
+			log.Fatal("MIDTRANS_PROVIDERS env variable not set, list the payment method providers charged with Midtrans")
+		}
+		midtrans := utils.NewMidtransProvider(serverKey, os.Getenv("MIDTRANS_PRODUCTION") == "true", paymentTTL)
+		paymentGateway.Register(midtrans.Name(), midtrans)
+		for _, methodProvider := range methodProviders {
+			paymentGateway.Register(methodProvider, midtrans)
+		}
+	} else if !paymentSimulator {
+		log.Fatal("MIDTRANS_SERVER_KEY env variable not set, set PAYMENT_SIMULATOR=true to simulate payments in development")
+	}
+	if paymentSimulator {
+		log.Println("PAYMENT_SIMULATOR enabled, every payment provider without an implementation is simulated")
+		paymentGateway.UseSimulator(fakePayment)
+	}
 	// Orders repo & handlers
 	ordersRepo := repositories.NewOrdersRepository(db)
-	ordersHandler := handlers.NewOrdersHandler(ordersRepo, rdb)
+	refundPolicy := models.RefundPolicy{
+		CutoffMinutes:     configs.GetEnvInt("CANCEL_CUTOFF_MINUTES", 60),
+		FullRefundMinutes: configs.GetEnvInt("FULL_REFUND_MINUTES", 1440),
+		PartialPercent:    float64(configs.GetEnvInt("PARTIAL_REFUND_PERCENT", 50)),
+	}
+	// Ticket codes
+	ticketSecret := secretFromEnv("TICKET_SECRET", jwtSecret, "ticket")
+	ticketSigner := utils.NewTicketSigner(ticketSecret)
+	// Loyalty points
+	loyaltyPolicy := models.LoyaltyPolicy{
+		PointValue:       float64(configs.GetEnvInt("LOYALTY_POINT_VALUE", 100)),
+		MaxRedeemPercent: float64(configs.GetEnvInt("LOYALTY_MAX_REDEEM_PERCENT", 50)),
+	}
+	loyaltyRepo := repositories.NewLoyaltyRepository(db)
+	loyaltyHandler := handlers.NewLoyaltyHandler(loyaltyRepo, loyaltyPolicy)
+	promoRepo := repositories.NewPromoRepository(db)
+	promoHandler := handlers.NewPromoHandler(promoRepo)
+	ordersHandler := handlers.NewOrdersHandler(ordersRepo, seatHoldRepo, paymentGateway, rdb, seatHoldTTL, refundPolicy, ticketSigner, loyaltyPolicy, loyaltyRepo, promoRepo)
+	// check-in repo & handlers
+	checkInRepo := repositories.NewCheckInRepository(db)
+	checkInWindow := models.CheckInWindow{
+		OpenBefore: time.Duration(configs.GetEnvInt("CHECKIN_OPEN_MINUTES", 60)) * time.Minute,
+		CloseAfter: time.Duration(configs.GetEnvInt("CHECKIN_CLOSE_MINUTES", 30)) * time.Minute,
+	}
+	checkInHandler := handlers.NewCheckInHandler(checkInRepo, ticketSigner, checkInWindow, rdb)
+	paymentHandler := handlers.NewPaymentHandler(ordersRepo, paymentGateway, fakePayment, rdb)
 	// Admin repo & handlers
-	adminRepo := repositories.NewAdminRepository(db)
-	adminHandler := handlers.NewAdminHandler(adminRepo, rdb)
+	cleaningBuffer := time.Duration(configs.GetEnvInt("SCHEDULE_CLEANING_MINUTES", 15)) * time.Minute
+	adminRepo := repositories.NewAdminRepository(db, cleaningBuffer)
+	adminHandler := handlers.NewAdminHandler(adminRepo, revocationRepo, permissionRepo, loginAttemptRepo, paymentGateway, rdb)
+	catalogRepo := repositories.NewCatalogRepository(db)
+	catalogHandler := handlers.NewCatalogHandler(catalogRepo, rdb)
+	// Mailer, emails with their tokens are only logged when MAIL_LOG_ONLY=true in development
+	var mailer utils.Mailer
+	if smtpHost := os.Getenv("SMTP_HOST"); smtpHost != "" {
+		mailer = utils.NewSMTPMailer(smtpHost, configs.GetEnv("SMTP_PORT", "587"), os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), os.Getenv("SMTP_FROM"))
+	} else if os.Getenv("MAIL_LOG_ONLY") == "true" {
+		log.Println("MAIL_LOG_ONLY enabled, emails are only logged")
+		mailer = utils.NewMemoryMailer()
+	} else {
+		log.Fatal("SMTP_HOST env variable not set, set MAIL_LOG_ONLY=true to log emails in development")
+	}
+	// Email action tokens
+	emailSecret := secretFromEnv("EMAIL_TOKEN_SECRET", jwtSecret, "email token")
+	actionTokens := utils.NewActionTokenSigner(emailSecret)
+	emailVerification := models.EmailVerificationConfig{
+		AppURL:         configs.GetEnv("APP_URL", "http://localhost:5173"),
+		TokenTTL:       time.Duration(configs.GetEnvInt("EMAIL_VERIFY_HOURS", 24)) * time.Hour,
+		ResendCooldown: time.Duration(configs.GetEnvInt("VERIFY_RESEND_SECONDS", 60)) * time.Second,
+	}
+	passwordReset := models.PasswordResetConfig{
+		TokenTTL: time.Duration(configs.GetEnvInt("PASSWORD_RESET_MINUTES", 30)) * time.Minute,
+		Cooldown: time.Duration(configs.GetEnvInt("PASSWORD_RESET_COOLDOWN_SECONDS", 60)) * time.Second,
+	}
 	// auth repo & handlers
 	authRepo := repositories.NewUserRepository(db)
-	authHandler := handlers.NewAuthHandler(authRepo, jwtManager, rdb)
+	sessionRepo := repositories.NewSessionRepository(db)
+	profileHandler := handlers.NewProfileHandler(profileRepo, ordersRepo, sessionRepo, revocationRepo, rdb)
+	// Two-factor secrets are encrypted at rest
+	totpKey := secretFromEnv("TOTP_ENCRYPTION_KEY", jwtSecret, "totp encryption")
+	totpSecrets, err := utils.NewSecretBox(totpKey)
+	if err != nil {
+		log.Fatal("Failed to init two-factor secret encryption:", err)
+	}
+	twoFactor := models.TwoFactorConfig{
+		Issuer:           configs.GetEnv("TWO_FACTOR_ISSUER", "Tickitz"),
+		ChallengeTTL:     time.Duration(configs.GetEnvInt("TWO_FACTOR_CHALLENGE_MINUTES", 5)) * time.Minute,
+		RequiredForAdmin: os.Getenv("TWO_FACTOR_REQUIRED_FOR_ADMIN") == "true",
+	}
+	twoFactorRepo := repositories.NewTwoFactorRepository(db)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorRepo, authRepo, sessionRepo, jwtManager, totpSecrets, twoFactor)
+	authHandler := handlers.NewAuthHandler(authRepo, sessionRepo, loginAttemptRepo, revocationRepo, jwtManager, refreshTTL, mailer, actionTokens, emailVerification, passwordReset, twoFactorHandler)
+	// OpenID Connect login
+	oidcRepo := repositories.NewOIDCRepository(db)
+	oidcStateTTL := time.Duration(configs.GetEnvInt("OIDC_STATE_MINUTES", 10)) * time.Minute
+	oidcHandler := handlers.NewOIDCHandler(oidcRepo, revocationRepo, authHandler, configs.GetOIDCProviders(emailVerification.AppURL), oidcStateTTL)
 	// seat repo & handlers
 	cinemaRepo := repositories.NewCinemaRepository(db)
-	cinemaHandler := handlers.NewCinemaHandler(cinemaRepo, rdb)
+	cinemaHandler := handlers.NewCinemaHandler(cinemaRepo, seatHoldRepo, rdb)
 
 	// Register router
 	MoviesRouter(r, movieHandler)
-	ProfileRouter(r, profileHandler, jwtManager, rdb)
-	OrdersRouter(r, ordersHandler, jwtManager, rdb)
-	AdminRouter(r, adminHandler, jwtManager, rdb)
-	AuthRouter(r, jwtManager, rdb, authHandler)
+	ProfileRouter(r, profileHandler, loyaltyHandler, jwtManager, revocationRepo)
+	OrdersRouter(r, ordersHandler, jwtManager, revocationRepo, authRepo)
+	CheckInRouter(r, checkInHandler, jwtManager, revocationRepo, permissionRepo)
+	PaymentRouter(r, paymentHandler, paymentSimulator)
+	AdminRouter(r, adminHandler, catalogHandler, ordersHandler, promoHandler, jwtManager, revocationRepo, permissionRepo, twoFactor.RequiredForAdmin)
+	AuthRouter(r, jwtManager, revocationRepo, authHandler, twoFactorHandler, oidcHandler)
 	CinemaRouter(r, cinemaHandler)
 
 	// register file upload
@@ -62,3 +189,16 @@ func MainRouter(db *pgxpool.Pool, rdb *redis.Client) *gin.Engine {
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	return r
 }
+
+// secret of the env variable, or a key derived from the JWT key with its own label when not set
+func secretFromEnv(key, jwtSecret, label string) string {
+	if secret := os.Getenv(key); secret != "" {
+		return secret
+	}
+	log.Printf("%s env variable not set, deriving it from the JWT Key", key)
+	secret, err := utils.DeriveSecret(jwtSecret, label)
+	if err != nil {
+		log.Fatal("Failed to derive ", key, ": ", err)
+	}
+	return secret
+}
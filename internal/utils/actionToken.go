@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidActionToken = errors.New("invalid or expired token")
+)
+
+// token purposes, a token signed for one purpose is rejected for another
+const (
+	ActionVerifyEmail    = "verify-email"
+	ActionLoginChallenge = "login-2fa"
+)
+
+// ActionTokenSigner signs short lived tokens sent by email, the token is subject and expiry signed with HMAC-SHA256
+type ActionTokenSigner struct {
+	secret []byte
+}
+
+func NewActionTokenSigner(secret string) *ActionTokenSigner {
+	return &ActionTokenSigner{secret: []byte(secret)}
+}
+
+func (s *ActionTokenSigner) sign(purpose, payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(purpose + "|" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Generate returns a token for the subject valid for ttl
+func (s *ActionTokenSigner) Generate(purpose, subject string, ttl time.Duration) string {
+	payload := fmt.Sprintf("%s|%d", subject, time.Now().Add(ttl).Unix())
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + s.sign(purpose, payload)
+}
+
+// Verify checks the signature and the expiry of a token and returns its subject
+func (s *ActionTokenSigner) Verify(purpose, token string) (string, error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return "", ErrInvalidActionToken
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidActionToken
+	}
+	payload := string(raw)
+	if !hmac.Equal([]byte(s.sign(purpose, payload)), []byte(signature)) {
+		return "", ErrInvalidActionToken
+	}
+
+	index := strings.LastIndex(payload, "|")
+	if index < 0 {
+		return "", ErrInvalidActionToken
+	}
+	expiresAt, err := strconv.ParseInt(payload[index+1:], 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return "", ErrInvalidActionToken
+	}
+
+	return payload[:index], nil
+}
@@ -0,0 +1,12 @@
+package utils
+
+import "github.com/gin-gonic/gin"
+
+// CinemaInScope reports whether the request may act on the cinema, users of a cinema scoped role are limited to their cinema
+func CinemaInScope(ctx *gin.Context, cinemaID int) bool {
+	scope, scoped := ctx.Get("cinema_id")
+	if !scoped {
+		return true
+	}
+	return scope.(int) == cinemaID
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/go-pdf/fpdf"
+)
+
+// RenderETicketPDF draws a printable A5 e-ticket with the screening details and the ticket QR code
+func RenderETicketPDF(order *models.OrderDetail) ([]byte, error) {
+	if order.QRCode == nil {
+		return nil, fmt.Errorf("order %d has no ticket code", order.ID)
+	}
+
+	qr, err := RenderQRCodePNG(*order.QRCode, 512)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf := fpdf.New("P", "mm", "A5", "")
+	pdf.SetTitle(fmt.Sprintf("Tickitz E-Ticket #%d", order.ID), true)
+	pdf.SetMargins(12, 12, 12)
+	pdf.SetAutoPageBreak(false, 12)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	// header
+	pdf.SetFillColor(95, 46, 234)
+	pdf.Rect(0, 0, 148, 24, "F")
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.SetXY(12, 8)
+	pdf.CellFormat(0, 8, "Tickitz E-Ticket", "", 1, "L", false, 0, "")
+
+	pdf.SetTextColor(20, 20, 20)
+	pdf.SetXY(12, 32)
+	pdf.SetFont("Helvetica", "B", 15)
+	pdf.MultiCell(0, 7, tr(order.Title), "", "L", false)
+
+	seats := make([]string, len(order.Seats))
+	for i, seat := range order.Seats {
+		seats[i] = fmt.Sprintf("%s (%s)", seat.SeatNumber, seat.SeatType)
+	}
+
+	auditorium := "-"
+	if order.Auditorium != nil {
+		auditorium = *order.Auditorium
+	}
+	ageRating := "-"
+	if order.AgeRating != nil {
+		ageRating = *order.AgeRating
+	}
+
+	details := [][2]string{
+		{"Order", fmt.Sprintf("#%d", order.ID)},
+		{"Cinema", order.Cinema},
+		{"Location", order.Location},
+		{"Auditorium", auditorium},
+		{"Date", order.Date},
+		{"Time", order.Time},
+		{"Age rating", ageRating},
+		{"Seats", strings.Join(seats, ", ")},
+		{"Total", fmt.Sprintf("Rp %.2f", order.TotalPrices)},
+	}
+
+	pdf.Ln(2)
+	for _, detail := range details {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.SetTextColor(110, 110, 110)
+		pdf.CellFormat(30, 7, detail[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.SetTextColor(20, 20, 20)
+		pdf.MultiCell(0, 7, tr(detail[1]), "", "L", false)
+	}
+
+	// ticket code
+	pdf.RegisterImageOptionsReader("qr", fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qr))
+	pdf.ImageOptions("qr", 44, 128, 60, 60, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	pdf.SetXY(12, 190)
+	pdf.SetFont("Helvetica", "", 8)
+	pdf.SetTextColor(110, 110, 110)
+	pdf.CellFormat(0, 5, "Show this QR code at the cinema gate. The ticket is valid for one entry.", "", 1, "C", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
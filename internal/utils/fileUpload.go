@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -24,3 +26,15 @@ func UploadFile(ctx *gin.Context, formField, uploadPath, prefix, folderPath stri
 
 	return fmt.Sprintf("/%s/%s", folderPath, filename), nil
 }
+
+// RemoveUploadedFile deletes a file saved by UploadFile, storedPath is the path returned by UploadFile
+func RemoveUploadedFile(uploadPath, storedPath string) error {
+	if storedPath == "" {
+		return nil
+	}
+	err := os.Remove(filepath.Join(uploadPath, filepath.Base(storedPath)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
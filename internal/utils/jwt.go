@@ -1,37 +1,61 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 
 	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// iat keeps milliseconds, a token issued right after a revocation in the same second stays valid
+func init() {
+	jwt.TimePrecision = time.Millisecond
+}
+
 type JWTManager struct {
-	secret []byte
+	secret    []byte
+	accessTTL time.Duration
 }
 
-func NewJWTManager(secret string) *JWTManager {
+func NewJWTManager(secret string, accessTTL time.Duration) *JWTManager {
 	return &JWTManager{
-		secret: []byte(secret),
+		secret:    []byte(secret),
+		accessTTL: accessTTL,
 	}
 }
 
 type Claims struct {
-	UserID int    `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID    int    `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID int    `json:"sid,omitempty"`
+	MFA       bool   `json:"mfa,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// Generate Token
-func (j *JWTManager) GenerateToken(user *models.User) (string, error) {
+// AccessTTL is the lifetime of an access token
+func (j *JWTManager) AccessTTL() time.Duration {
+	return j.accessTTL
+}
+
+// Generate Token, sessionID is the session of the refresh token, mfa when the session was started with a second factor
+func (j *JWTManager) GenerateToken(user *models.User, sessionID int, mfa bool) (string, error) {
+	jti, err := RandomHex(16)
+	if err != nil {
+		return "", err
+	}
+
 	claims := &Claims{
-		UserID: user.ID,
-		Email:  user.Email,
-		Role:   user.Role,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		SessionID: sessionID,
+		MFA:       mfa,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(60 * time.Minute)),
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.accessTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    "tickitz",
 		},
@@ -55,3 +79,9 @@ func (j *JWTManager) ValidateToken(tokenStr string) (*Claims, error) {
 	}
 	return claims, nil
 }
+
+// HashToken returns the sha256 hex of an opaque token, tokens are stored hashed
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
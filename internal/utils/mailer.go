@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+)
+
+// Mailer sends transactional emails, SMTPMailer in production and MemoryMailer in development and tests
+type Mailer interface {
+	Send(ctx context.Context, message models.EmailMessage) error
+}
+
+type SMTPMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	return &SMTPMailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, message models.EmailMessage) error {
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", m.from)
+	fmt.Fprintf(&body, "To: %s\r\n", message.To)
+	fmt.Fprintf(&body, "Subject: %s\r\n", message.Subject)
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n\r\n")
+	body.WriteString(message.Body)
+
+	return smtp.SendMail(net.JoinHostPort(m.host, m.port), auth, m.from, []string{message.To}, []byte(body.String()))
+}
+
+// emails kept by MemoryMailer, older ones are dropped
+const memoryMailerLimit = 100
+
+// MemoryMailer keeps the last sent emails in memory and logs them, for local development only
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []models.EmailMessage
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, message models.EmailMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, message)
+	if len(m.messages) > memoryMailerLimit {
+		m.messages = slices.Delete(m.messages, 0, len(m.messages)-memoryMailerLimit)
+	}
+	log.Printf("Email to %s: %s\n%s\n", message.To, message.Subject, message.Body)
+	return nil
+}
+
+// Messages returns the emails sent so far
+func (m *MemoryMailer) Messages() []models.EmailMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	messages := make([]models.EmailMessage, len(m.messages))
+	copy(messages, m.messages)
+	return messages
+}
@@ -0,0 +1,210 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+)
+
+const (
+	midtransSnapURL           = "https://app.midtrans.com/snap/v1/transactions"
+	midtransSandboxSnapURL    = "https://app.sandbox.midtrans.com/snap/v1/transactions"
+	midtransAPIURL            = "https://api.midtrans.com/v2"
+	midtransSandboxAPIURL     = "https://api.sandbox.midtrans.com/v2"
+	midtransRefundReason      = "Tickitz order cancelled"
+	midtransSuccessStatusCode = "200"
+)
+
+// MidtransProvider charges payments with Midtrans Snap, the customer pays on the Snap page and Midtrans
+// sends the result to the webhook
+type MidtransProvider struct {
+	serverKey  string
+	snapURL    string
+	apiURL     string
+	ttl        time.Duration
+	httpClient *http.Client
+}
+
+// NewMidtransProvider creates the provider, production false uses the Midtrans sandbox
+func NewMidtransProvider(serverKey string, production bool, ttl time.Duration) *MidtransProvider {
+	m := &MidtransProvider{
+		serverKey:  serverKey,
+		snapURL:    midtransSandboxSnapURL,
+		apiURL:     midtransSandboxAPIURL,
+		ttl:        ttl,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+	if production {
+		m.snapURL = midtransSnapURL
+		m.apiURL = midtransAPIURL
+	}
+	return m
+}
+
+func (m *MidtransProvider) Name() string {
+	return "midtrans"
+}
+
+// post sends a json request authenticated with the server key and decodes the response
+func (m *MidtransProvider) post(ctx context.Context, endpoint string, body, out any) (int, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.SetBasicAuth(m.serverKey, "")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp.StatusCode, fmt.Errorf("midtrans response with status %d : %w", resp.StatusCode, err)
+	}
+	return resp.StatusCode, nil
+}
+
+func (m *MidtransProvider) CreateCharge(ctx context.Context, charge models.PaymentCharge) (*models.PaymentSession, error) {
+	random, err := RandomHex(4)
+	if err != nil {
+		return nil, err
+	}
+
+	// the Midtrans order id is our payment reference, it has to be unique for every charge
+	reference := fmt.Sprintf("TKZ-%d-%s", charge.OrderID, random)
+	body := map[string]any{
+		"transaction_details": map[string]any{
+			"order_id":     reference,
+			"gross_amount": int64(math.Round(charge.Amount)),
+		},
+		"item_details": []map[string]any{{
+			"id":       strconv.Itoa(charge.OrderID),
+			"name":     charge.Description,
+			"price":    int64(math.Round(charge.Amount)),
+			"quantity": 1,
+		}},
+		"expiry": map[string]any{
+			"unit":     "minute",
+			"duration": int(m.ttl.Minutes()),
+		},
+	}
+
+	var res struct {
+		Token         string   `json:"token"`
+		RedirectURL   string   `json:"redirect_url"`
+		ErrorMessages []string `json:"error_messages"`
+	}
+	status, err := m.post(ctx, m.snapURL, body, &res)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusCreated || res.RedirectURL == "" {
+		return nil, fmt.Errorf("midtrans charge failed with status %d : %v", status, res.ErrorMessages)
+	}
+
+	expiresAt := time.Now().Add(m.ttl)
+	return &models.PaymentSession{
+		Provider:   m.Name(),
+		Reference:  reference,
+		PaymentURL: res.RedirectURL,
+		ExpiresAt:  &expiresAt,
+	}, nil
+}
+
+// ParseWebhook reads a Midtrans notification, it is signed in its signature_key field and not in a header
+func (m *MidtransProvider) ParseWebhook(payload []byte, signature string) (*models.PaymentEvent, error) {
+	var notification struct {
+		TransactionID     string `json:"transaction_id"`
+		TransactionStatus string `json:"transaction_status"`
+		FraudStatus       string `json:"fraud_status"`
+		OrderID           string `json:"order_id"`
+		StatusCode        string `json:"status_code"`
+		GrossAmount       string `json:"gross_amount"`
+		SignatureKey      string `json:"signature_key"`
+	}
+	if err := json.Unmarshal(payload, &notification); err != nil {
+		return nil, err
+	}
+
+	sum := sha512.Sum512([]byte(notification.OrderID + notification.StatusCode + notification.GrossAmount + m.serverKey))
+	if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(notification.SignatureKey)) != 1 {
+		return nil, ErrInvalidPaymentSignature
+	}
+	if notification.TransactionID == "" || notification.OrderID == "" {
+		return nil, errors.New("payment event transaction_id and order_id are required")
+	}
+
+	var status string
+	switch notification.TransactionStatus {
+	case "settlement":
+		status = models.PaymentStatusPaid
+	case "capture":
+		// card payments are only paid once the fraud check accepts them
+		if notification.FraudStatus != "accept" {
+			return nil, ErrPaymentEventIgnored
+		}
+		status = models.PaymentStatusPaid
+	case "deny", "cancel", "failure":
+		status = models.PaymentStatusFailed
+	case "expire":
+		status = models.PaymentStatusExpired
+	default:
+		// pending and the refund notifications of the refunds we sent ourselves
+		return nil, ErrPaymentEventIgnored
+	}
+
+	amount, err := strconv.ParseFloat(notification.GrossAmount, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gross_amount : %w", err)
+	}
+
+	// one transaction sends a notification for every status change
+	return &models.PaymentEvent{
+		ID:        notification.TransactionID + ":" + notification.TransactionStatus,
+		Reference: notification.OrderID,
+		Status:    status,
+		Amount:    amount,
+	}, nil
+}
+
+// Refund sends the idempotency key as the Midtrans refund_key, a retried refund is not refunded again
+func (m *MidtransProvider) Refund(ctx context.Context, reference string, amount float64, idempotencyKey string) error {
+	body := map[string]any{
+		"refund_key": idempotencyKey,
+		"amount":     int64(math.Round(amount)),
+		"reason":     midtransRefundReason,
+	}
+
+	var res struct {
+		StatusCode    string `json:"status_code"`
+		StatusMessage string `json:"status_message"`
+	}
+	endpoint := fmt.Sprintf("%s/%s/refund", m.apiURL, url.PathEscape(reference))
+	if _, err := m.post(ctx, endpoint, body, &res); err != nil {
+		return err
+	}
+	if res.StatusCode != midtransSuccessStatusCode {
+		return fmt.Errorf("midtrans refund failed with status %s : %s", res.StatusCode, res.StatusMessage)
+	}
+	return nil
+}
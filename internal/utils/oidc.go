@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+var (
+	ErrOIDCNoIDToken     = errors.New("the provider did not return an id token")
+	ErrOIDCNonceMismatch = errors.New("id token nonce does not match")
+)
+
+// OIDCClient signs users in with an OpenID Connect provider using the authorization code flow with PKCE
+type OIDCClient struct {
+	config     models.OIDCProviderConfig
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+func NewOIDCClient(config models.OIDCProviderConfig) *OIDCClient {
+	return &OIDCClient{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// discovery is made on first use, a provider down at startup does not stop the server
+func (c *OIDCClient) discover() (*oidc.Provider, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.provider != nil {
+		return c.provider, nil
+	}
+	provider, err := oidc.NewProvider(oidc.ClientContext(context.Background(), c.httpClient), c.config.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery of %s failed : %w", c.config.Name, err)
+	}
+	c.provider = provider
+	return provider, nil
+}
+
+func (c *OIDCClient) oauth2Config(provider *oidc.Provider) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     c.config.ClientID,
+		ClientSecret: c.config.ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  c.config.RedirectURL,
+		Scopes:       append([]string{oidc.ScopeOpenID}, c.config.Scopes...),
+	}
+}
+
+// AuthCodeURL returns the url of the provider login page, verifier is the PKCE code verifier kept until the callback
+func (c *OIDCClient) AuthCodeURL(state, nonce, verifier string) (string, error) {
+	provider, err := c.discover()
+	if err != nil {
+		return "", err
+	}
+	return c.oauth2Config(provider).AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Exchange trades the authorization code for the tokens and returns the identity of the verified ID token
+func (c *OIDCClient) Exchange(ctx context.Context, code, verifier, nonce string) (*models.OIDCIdentity, error) {
+	provider, err := c.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = oidc.ClientContext(ctx, c.httpClient)
+	token, err := c.oauth2Config(provider).Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, err
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, ErrOIDCNoIDToken
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: c.config.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	if idToken.Nonce != nonce {
+		return nil, ErrOIDCNonceMismatch
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified any    `json:"email_verified"`
+		GivenName     string `json:"given_name"`
+		FamilyName    string `json:"family_name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+
+	// some providers send email_verified as a string
+	verified := false
+	switch v := claims.EmailVerified.(type) {
+	case bool:
+		verified = v
+	case string:
+		verified = v == "true"
+	}
+
+	return &models.OIDCIdentity{
+		Provider:      c.config.Name,
+		Subject:       idToken.Subject,
+		Email:         strings.TrimSpace(claims.Email),
+		EmailVerified: verified,
+		FirstName:     claims.GivenName,
+		LastName:      claims.FamilyName,
+	}, nil
+}
+
+// GenerateOIDCVerifier returns a PKCE code verifier
+func GenerateOIDCVerifier() string {
+	return oauth2.GenerateVerifier()
+}
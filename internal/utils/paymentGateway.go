@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+)
+
+var (
+	ErrPaymentProviderNotFound = errors.New("payment provider not supported")
+	ErrInvalidPaymentSignature = errors.New("invalid payment signature")
+	// the event does not change the order, like a payment still waiting for the customer
+	ErrPaymentEventIgnored = errors.New("payment event ignored")
+)
+
+// PaymentProvider is implemented by every payment gateway, selected by payment_methods.provider
+type PaymentProvider interface {
+	Name() string
+	CreateCharge(ctx context.Context, charge models.PaymentCharge) (*models.PaymentSession, error)
+	ParseWebhook(payload []byte, signature string) (*models.PaymentEvent, error)
+	// Refund is called again with the same idempotency key when a refund is retried, the provider refunds once
+	Refund(ctx context.Context, reference string, amount float64, idempotencyKey string) error
+}
+
+type PaymentGateway struct {
+	providers map[string]PaymentProvider
+	simulator PaymentProvider
+}
+
+// NewPaymentGateway creates a gateway without providers, unknown providers are not found
+func NewPaymentGateway() *PaymentGateway {
+	return &PaymentGateway{
+		providers: map[string]PaymentProvider{},
+	}
+}
+
+func (g *PaymentGateway) Register(provider string, p PaymentProvider) {
+	g.providers[strings.ToLower(provider)] = p
+}
+
+// UseSimulator registers the simulator under its own name and lets it handle every provider without its own
+// implementation, for local development only
+func (g *PaymentGateway) UseSimulator(p PaymentProvider) {
+	g.Register(p.Name(), p)
+	g.simulator = p
+}
+
+func (g *PaymentGateway) Provider(provider string) (PaymentProvider, error) {
+	if p, ok := g.providers[strings.ToLower(provider)]; ok {
+		return p, nil
+	}
+	if g.simulator != nil {
+		return g.simulator, nil
+	}
+	return nil, ErrPaymentProviderNotFound
+}
+
+// HasProvider reports whether payments of the provider can be charged
+func (g *PaymentGateway) HasProvider(provider string) bool {
+	_, err := g.Provider(provider)
+	return err == nil
+}
+
+func SignPayload(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func VerifyPayloadSignature(secret, payload []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+func RandomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// FakePaymentProvider is an in-process provider for local development, payments are completed with the simulator endpoint
+type FakePaymentProvider struct {
+	secret []byte
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	refunds map[string]float64 // idempotency key -> refunded amount
+}
+
+func NewFakePaymentProvider(secret string, ttl time.Duration) *FakePaymentProvider {
+	return &FakePaymentProvider{
+		secret:  []byte(secret),
+		ttl:     ttl,
+		refunds: map[string]float64{},
+	}
+}
+
+func (f *FakePaymentProvider) Name() string {
+	return "fake"
+}
+
+func (f *FakePaymentProvider) CreateCharge(ctx context.Context, charge models.PaymentCharge) (*models.PaymentSession, error) {
+	random, err := RandomHex(8)
+	if err != nil {
+		return nil, err
+	}
+
+	reference := fmt.Sprintf("FAKE-%d-%s", charge.OrderID, random)
+	expiresAt := time.Now().Add(f.ttl)
+	return &models.PaymentSession{
+		Provider:   f.Name(),
+		Reference:  reference,
+		PaymentURL: fmt.Sprintf("/payments/fake/%s", reference),
+		ExpiresAt:  &expiresAt,
+	}, nil
+}
+
+// Sign signs a webhook payload the same way the provider does
+func (f *FakePaymentProvider) Sign(payload []byte) string {
+	return SignPayload(f.secret, payload)
+}
+
+func (f *FakePaymentProvider) ParseWebhook(payload []byte, signature string) (*models.PaymentEvent, error) {
+	if !VerifyPayloadSignature(f.secret, payload, signature) {
+		return nil, ErrInvalidPaymentSignature
+	}
+
+	var event models.PaymentEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	if event.ID == "" || event.Reference == "" {
+		return nil, errors.New("payment event id and reference are required")
+	}
+	return &event, nil
+}
+
+// Refund records the idempotency key, a retried refund with another amount is refused like a real provider does
+func (f *FakePaymentProvider) Refund(ctx context.Context, reference string, amount float64, idempotencyKey string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if refunded, ok := f.refunds[idempotencyKey]; ok && refunded != amount {
+		return fmt.Errorf("refund %s was already made with amount %.2f", idempotencyKey, refunded)
+	}
+	f.refunds[idempotencyKey] = amount
+	return nil
+}
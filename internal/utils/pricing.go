@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+)
+
+func roundPrice(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// check the rule applies to the screening day and start time, empty conditions always match
+func isPriceRuleMatch(rule models.PriceRule, weekday int, showTime string) bool {
+	if len(rule.DaysOfWeek) > 0 && !slices.Contains(rule.DaysOfWeek, weekday) {
+		return false
+	}
+	if rule.StartTime != nil && showTime < *rule.StartTime {
+		return false
+	}
+	if rule.EndTime != nil && showTime >= *rule.EndTime {
+		return false
+	}
+	return true
+}
+
+// CalculateOrderPrice prices every seat from the cinema base price, seat type surcharge and time rules, then adds the payment fee
+func CalculateOrderPrice(input *models.PricingInput) models.PriceBreakdown {
+	showTime := input.ScheduleTime
+	if len(showTime) > 5 {
+		showTime = showTime[:5]
+	}
+	weekday := int(input.ScheduleDate.Weekday())
+
+	var adjustments []models.PriceAdjustment
+	for _, rule := range input.Rules {
+		if isPriceRuleMatch(rule, weekday, showTime) {
+			adjustments = append(adjustments, models.PriceAdjustment{Name: rule.Name, Amount: rule.Adjustment})
+		}
+	}
+
+	breakdown := models.PriceBreakdown{Tickets: []models.TicketPrice{}}
+	for _, seat := range input.Seats {
+		ticket := models.TicketPrice{
+			SeatID:        seat.SeatID,
+			SeatNumber:    seat.SeatNumber,
+			SeatType:      seat.SeatType,
+			BasePrice:     input.BasePrice,
+			SeatSurcharge: seat.Surcharge,
+			Adjustments:   []models.PriceAdjustment{},
+		}
+
+		price := input.BasePrice + seat.Surcharge
+		for _, adjustment := range adjustments {
+			ticket.Adjustments = append(ticket.Adjustments, adjustment)
+			price += adjustment.Amount
+		}
+		ticket.Price = roundPrice(math.Max(price, 0))
+
+		breakdown.Tickets = append(breakdown.Tickets, ticket)
+		breakdown.Subtotal += ticket.Price
+	}
+
+	breakdown.Subtotal = roundPrice(breakdown.Subtotal)
+	breakdown.PaymentFee = roundPrice(input.PaymentFee)
+	breakdown.Total = roundPrice(breakdown.Subtotal + breakdown.PaymentFee)
+	return breakdown
+}
+
+// ApplyPromoCode checks the promo code applies to the order and takes its discount off the ticket subtotal.
+// Usage limits are counted by the repository.
+func ApplyPromoCode(breakdown *models.PriceBreakdown, promo *models.PromoCode, input *models.PricingInput, paymentMethodID int, now time.Time) error {
+	if !promo.IsActive {
+		return fmt.Errorf("promo code %s is not active", promo.Code)
+	}
+	if promo.ValidFrom != nil && now.Before(*promo.ValidFrom) {
+		return fmt.Errorf("promo code %s is not valid yet", promo.Code)
+	}
+	if promo.ValidUntil != nil && !now.Before(*promo.ValidUntil) {
+		return fmt.Errorf("promo code %s has expired", promo.Code)
+	}
+	if len(promo.MovieIDs) > 0 && !slices.Contains(promo.MovieIDs, input.MovieID) {
+		return fmt.Errorf("promo code %s is not valid for this movie", promo.Code)
+	}
+	if len(promo.CinemaIDs) > 0 && !slices.Contains(promo.CinemaIDs, input.CinemaID) {
+		return fmt.Errorf("promo code %s is not valid at this cinema", promo.Code)
+	}
+	if len(promo.PaymentMethodIDs) > 0 && !slices.Contains(promo.PaymentMethodIDs, paymentMethodID) {
+		return fmt.Errorf("promo code %s is not valid for this payment method", promo.Code)
+	}
+	if breakdown.Subtotal < promo.MinSpend {
+		return fmt.Errorf("promo code %s needs a minimum spend of %.2f", promo.Code, promo.MinSpend)
+	}
+
+	discount := promo.DiscountValue
+	if promo.DiscountType == models.PromoDiscountPercentage {
+		discount = breakdown.Subtotal * promo.DiscountValue / 100
+		if promo.MaxDiscount != nil {
+			discount = math.Min(discount, *promo.MaxDiscount)
+		}
+	}
+
+	breakdown.PromoCode = promo.Code
+	breakdown.PromoDiscount = roundPrice(math.Min(discount, breakdown.Subtotal))
+	breakdown.Total = roundPrice(breakdown.Subtotal - breakdown.PromoDiscount - breakdown.PointsDiscount + breakdown.PaymentFee)
+	return nil
+}
+
+// MaxRedeemablePoints returns how many points can pay for the tickets of the order, after the promo discount
+func MaxRedeemablePoints(breakdown *models.PriceBreakdown, policy models.LoyaltyPolicy) int {
+	if policy.PointValue <= 0 {
+		return 0
+	}
+	return int(math.Floor((breakdown.Subtotal - breakdown.PromoDiscount) * policy.MaxRedeemPercent / 100 / policy.PointValue))
+}
+
+// ApplyPointsRedemption takes the value of the redeemed points off the ticket subtotal, the payment fee is not discounted.
+// A promo code is applied first.
+func ApplyPointsRedemption(breakdown *models.PriceBreakdown, points int, policy models.LoyaltyPolicy) error {
+	if points <= 0 {
+		return nil
+	}
+	if maxPoints := MaxRedeemablePoints(breakdown, policy); points > maxPoints {
+		return fmt.Errorf("at most %d points can be redeemed on this order", maxPoints)
+	}
+
+	breakdown.PointsRedeemed = points
+	breakdown.PointsDiscount = roundPrice(float64(points) * policy.PointValue)
+	breakdown.Total = roundPrice(breakdown.Subtotal - breakdown.PromoDiscount - breakdown.PointsDiscount + breakdown.PaymentFee)
+	return nil
+}
@@ -0,0 +1,311 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// two regular seats of 50000 on a Saturday evening, paid with a fee of 2500
+func testPricingInput() *models.PricingInput {
+	return &models.PricingInput{
+		MovieID:      7,
+		CinemaID:     3,
+		BasePrice:    50000,
+		ScheduleDate: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC),
+		ScheduleTime: "19:30:00",
+		PaymentFee:   2500,
+		Seats: []models.PricingSeat{
+			{SeatID: 1, SeatNumber: "A1", SeatType: models.SeatTypeRegular},
+			{SeatID: 2, SeatNumber: "A2", SeatType: models.SeatTypeRegular},
+		},
+	}
+}
+
+func TestCalculateOrderPrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(input *models.PricingInput)
+		subtotal float64
+		total    float64
+	}{
+		{
+			name:     "base price and payment fee",
+			subtotal: 100000,
+			total:    102500,
+		},
+		{
+			name: "seat type surcharge",
+			change: func(input *models.PricingInput) {
+				input.Seats[1].SeatType = models.SeatTypeVIP
+				input.Seats[1].Surcharge = 25000
+			},
+			subtotal: 125000,
+			total:    127500,
+		},
+		{
+			name: "weekend rule matches the screening day",
+			change: func(input *models.PricingInput) {
+				input.Rules = []models.PriceRule{{Name: "Weekend", DaysOfWeek: []int{0, 6}, Adjustment: 10000}}
+			},
+			subtotal: 120000,
+			total:    122500,
+		},
+		{
+			name: "weekday rule does not match a saturday",
+			change: func(input *models.PricingInput) {
+				input.Rules = []models.PriceRule{{Name: "Weekday", DaysOfWeek: []int{1, 2, 3, 4, 5}, Adjustment: -10000}}
+			},
+			subtotal: 100000,
+			total:    102500,
+		},
+		{
+			name: "time window includes the start time",
+			change: func(input *models.PricingInput) {
+				input.Rules = []models.PriceRule{{Name: "Prime time", StartTime: ptr("19:30"), EndTime: ptr("22:00"), Adjustment: 5000}}
+			},
+			subtotal: 110000,
+			total:    112500,
+		},
+		{
+			name: "time window excludes the end time",
+			change: func(input *models.PricingInput) {
+				input.Rules = []models.PriceRule{{Name: "Matinee", StartTime: ptr("10:00"), EndTime: ptr("19:30"), Adjustment: -15000}}
+			},
+			subtotal: 100000,
+			total:    102500,
+		},
+		{
+			name: "a ticket never goes below zero",
+			change: func(input *models.PricingInput) {
+				input.Rules = []models.PriceRule{{Name: "Free screening", Adjustment: -80000}}
+			},
+			subtotal: 0,
+			total:    2500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := testPricingInput()
+			if tt.change != nil {
+				tt.change(input)
+			}
+
+			breakdown := CalculateOrderPrice(input)
+			if breakdown.Subtotal != tt.subtotal || breakdown.Total != tt.total {
+				t.Fatalf("subtotal %.2f total %.2f, want %.2f and %.2f", breakdown.Subtotal, breakdown.Total, tt.subtotal, tt.total)
+			}
+			if len(breakdown.Tickets) != len(input.Seats) {
+				t.Fatalf("%d tickets, want %d", len(breakdown.Tickets), len(input.Seats))
+			}
+		})
+	}
+}
+
+func TestApplyPromoCode(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	const paymentMethodID = 2
+
+	tests := []struct {
+		name     string
+		promo    models.PromoCode
+		inactive bool
+		discount float64
+		err      string
+	}{
+		{
+			name:     "percentage of the subtotal",
+			promo:    models.PromoCode{DiscountType: models.PromoDiscountPercentage, DiscountValue: 20},
+			discount: 20000,
+		},
+		{
+			name:     "percentage capped by max_discount",
+			promo:    models.PromoCode{DiscountType: models.PromoDiscountPercentage, DiscountValue: 20, MaxDiscount: ptr(15000.0)},
+			discount: 15000,
+		},
+		{
+			name:     "max_discount above the percentage",
+			promo:    models.PromoCode{DiscountType: models.PromoDiscountPercentage, DiscountValue: 10, MaxDiscount: ptr(15000.0)},
+			discount: 10000,
+		},
+		{
+			name:     "fixed amount",
+			promo:    models.PromoCode{DiscountType: models.PromoDiscountFixed, DiscountValue: 30000},
+			discount: 30000,
+		},
+		{
+			name:     "fixed amount above the subtotal",
+			promo:    models.PromoCode{DiscountType: models.PromoDiscountFixed, DiscountValue: 150000},
+			discount: 100000,
+		},
+		{
+			name:     "min spend reached",
+			promo:    models.PromoCode{DiscountType: models.PromoDiscountFixed, DiscountValue: 10000, MinSpend: 100000},
+			discount: 10000,
+		},
+		{
+			name:  "min spend not reached",
+			promo: models.PromoCode{DiscountType: models.PromoDiscountFixed, DiscountValue: 10000, MinSpend: 100000.01},
+			err:   "minimum spend",
+		},
+		{
+			name:     "inactive",
+			promo:    models.PromoCode{DiscountType: models.PromoDiscountFixed, DiscountValue: 10000},
+			inactive: true,
+			err:      "not active",
+		},
+		{
+			name:     "inside the validity window",
+			promo:    models.PromoCode{DiscountType: models.PromoDiscountFixed, DiscountValue: 10000, ValidFrom: ptr(now), ValidUntil: ptr(now.Add(time.Hour))},
+			discount: 10000,
+		},
+		{
+			name:  "before the validity window",
+			promo: models.PromoCode{DiscountType: models.PromoDiscountFixed, DiscountValue: 10000, ValidFrom: ptr(now.Add(time.Second))},
+			err:   "not valid yet",
+		},
+		{
+			name:  "valid_until is exclusive",
+			promo: models.PromoCode{DiscountType: models.PromoDiscountFixed, DiscountValue: 10000, ValidUntil: ptr(now)},
+			err:   "expired",
+		},
+		{
+			name:     "movie in the restriction",
+			promo:    models.PromoCode{DiscountType: models.PromoDiscountFixed, DiscountValue: 10000, MovieIDs: []int{5, 7}},
+			discount: 10000,
+		},
+		{
+			name:  "movie not in the restriction",
+			promo: models.PromoCode{DiscountType: models.PromoDiscountFixed, DiscountValue: 10000, MovieIDs: []int{5}},
+			err:   "not valid for this movie",
+		},
+		{
+			name:     "cinema in the restriction",
+			promo:    models.PromoCode{DiscountType: models.PromoDiscountFixed, DiscountValue: 10000, CinemaIDs: []int{3}},
+			discount: 10000,
+		},
+		{
+			name:  "cinema not in the restriction",
+			promo: models.PromoCode{DiscountType: models.PromoDiscountFixed, DiscountValue: 10000, CinemaIDs: []int{4}},
+			err:   "not valid at this cinema",
+		},
+		{
+			name:     "payment method in the restriction",
+			promo:    models.PromoCode{DiscountType: models.PromoDiscountFixed, DiscountValue: 10000, PaymentMethodIDs: []int{paymentMethodID}},
+			discount: 10000,
+		},
+		{
+			name:  "payment method not in the restriction",
+			promo: models.PromoCode{DiscountType: models.PromoDiscountFixed, DiscountValue: 10000, PaymentMethodIDs: []int{9}},
+			err:   "not valid for this payment method",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promo := tt.promo
+			promo.Code = "TEST"
+			promo.IsActive = !tt.inactive
+			input := testPricingInput()
+			breakdown := CalculateOrderPrice(input)
+
+			err := ApplyPromoCode(&breakdown, &promo, input, paymentMethodID, now)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error %v, want %q", err, tt.err)
+				}
+				if breakdown.PromoDiscount != 0 || breakdown.Total != 102500 {
+					t.Fatalf("refused promo changed the price: discount %.2f total %.2f", breakdown.PromoDiscount, breakdown.Total)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if breakdown.PromoDiscount != tt.discount {
+				t.Fatalf("discount %.2f, want %.2f", breakdown.PromoDiscount, tt.discount)
+			}
+			// the payment fee is never discounted
+			if want := 100000 - tt.discount + 2500; breakdown.Total != want {
+				t.Fatalf("total %.2f, want %.2f", breakdown.Total, want)
+			}
+		})
+	}
+}
+
+func TestMaxRedeemablePoints(t *testing.T) {
+	tests := []struct {
+		name          string
+		subtotal      float64
+		promoDiscount float64
+		policy        models.LoyaltyPolicy
+		points        int
+	}{
+		{name: "share of the subtotal", subtotal: 100000, policy: models.LoyaltyPolicy{PointValue: 100, MaxRedeemPercent: 50}, points: 500},
+		{name: "after the promo discount", subtotal: 100000, promoDiscount: 20000, policy: models.LoyaltyPolicy{PointValue: 100, MaxRedeemPercent: 50}, points: 400},
+		{name: "rounded down to whole points", subtotal: 45050, policy: models.LoyaltyPolicy{PointValue: 100, MaxRedeemPercent: 50}, points: 225},
+		{name: "whole subtotal", subtotal: 100000, policy: models.LoyaltyPolicy{PointValue: 100, MaxRedeemPercent: 100}, points: 1000},
+		{name: "points without value", subtotal: 100000, policy: models.LoyaltyPolicy{PointValue: 0, MaxRedeemPercent: 50}, points: 0},
+		{name: "nothing left after the promo", subtotal: 30000, promoDiscount: 30000, policy: models.LoyaltyPolicy{PointValue: 100, MaxRedeemPercent: 50}, points: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breakdown := models.PriceBreakdown{Subtotal: tt.subtotal, PromoDiscount: tt.promoDiscount}
+			if points := MaxRedeemablePoints(&breakdown, tt.policy); points != tt.points {
+				t.Fatalf("%d points, want %d", points, tt.points)
+			}
+		})
+	}
+}
+
+func TestPromoThenPoints(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	policy := models.LoyaltyPolicy{PointValue: 100, MaxRedeemPercent: 50}
+	promo := models.PromoCode{Code: "TEST", DiscountType: models.PromoDiscountPercentage, DiscountValue: 20, IsActive: true}
+
+	tests := []struct {
+		name   string
+		points int
+		total  float64
+		err    string
+	}{
+		{name: "points up to the share left after the promo", points: 400, total: 42500},
+		{name: "points above the share left after the promo", points: 401, err: "at most 400 points"},
+		{name: "no points", points: 0, total: 82500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := testPricingInput()
+			breakdown := CalculateOrderPrice(input)
+			if err := ApplyPromoCode(&breakdown, &promo, input, 2, now); err != nil {
+				t.Fatalf("apply promo: %v", err)
+			}
+
+			err := ApplyPointsRedemption(&breakdown, tt.points, policy)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if breakdown.PromoDiscount != 20000 || breakdown.PointsDiscount != float64(tt.points)*policy.PointValue {
+				t.Fatalf("promo discount %.2f points discount %.2f", breakdown.PromoDiscount, breakdown.PointsDiscount)
+			}
+			if breakdown.Total != tt.total {
+				t.Fatalf("total %.2f, want %.2f", breakdown.Total, tt.total)
+			}
+		})
+	}
+}
@@ -1,13 +1,114 @@
 package utils
 
 import (
-	"math/rand"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"strconv"
-	"time"
+	"strings"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+	"github.com/skip2/go-qrcode"
 )
 
-func GenerateQRCode() string {
-	rand.New(rand.NewSource(time.Now().UnixNano()))
-	number := rand.Intn(90000) + 10000
-	return "QR" + strconv.Itoa(number)
+var ErrInvalidTicketCode = errors.New("invalid ticket code")
+
+const ticketCodePrefix = "TKZ1"
+
+// TicketSigner creates ticket codes that can be verified without a database lookup,
+// the code is the order payload signed with HMAC-SHA256
+type TicketSigner struct {
+	secret []byte
+}
+
+func NewTicketSigner(secret string) *TicketSigner {
+	return &TicketSigner{secret: []byte(secret)}
+}
+
+func (s *TicketSigner) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Generate returns the ticket code of an order, payload is order id, screening and seats
+func (s *TicketSigner) Generate(orderID, cinemaScheduleID int, seatIDs []int) string {
+	seats := make([]string, len(seatIDs))
+	for i, seatID := range seatIDs {
+		seats[i] = strconv.Itoa(seatID)
+	}
+
+	payload := fmt.Sprintf("%d:%d:%s", orderID, cinemaScheduleID, strings.Join(seats, ","))
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return ticketCodePrefix + "." + encoded + "." + s.sign(payload)
+}
+
+// Verify checks the signature of a ticket code and returns its payload
+func (s *TicketSigner) Verify(code string) (*models.TicketClaims, error) {
+	parts := strings.Split(code, ".")
+	if len(parts) != 3 || parts[0] != ticketCodePrefix {
+		return nil, ErrInvalidTicketCode
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidTicketCode
+	}
+	payload := string(raw)
+	if !hmac.Equal([]byte(s.sign(payload)), []byte(parts[2])) {
+		return nil, ErrInvalidTicketCode
+	}
+
+	fields := strings.Split(payload, ":")
+	if len(fields) != 3 {
+		return nil, ErrInvalidTicketCode
+	}
+
+	var claims models.TicketClaims
+	if claims.OrderID, err = strconv.Atoi(fields[0]); err != nil {
+		return nil, ErrInvalidTicketCode
+	}
+	if claims.CinemasScheduleID, err = strconv.Atoi(fields[1]); err != nil {
+		return nil, ErrInvalidTicketCode
+	}
+	for _, seat := range strings.Split(fields[2], ",") {
+		seatID, err := strconv.Atoi(seat)
+		if err != nil {
+			return nil, ErrInvalidTicketCode
+		}
+		claims.SeatIDs = append(claims.SeatIDs, seatID)
+	}
+
+	return &claims, nil
+}
+
+func RenderQRCodePNG(content string, size int) ([]byte, error) {
+	return qrcode.Encode(content, qrcode.Medium, size)
+}
+
+// RenderQRCodeSVG draws every dark module of the QR code as a square, size is the width in pixels
+func RenderQRCodeSVG(content string, size int) ([]byte, error) {
+	qr, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+	bitmap := qr.Bitmap()
+	modules := len(bitmap)
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, modules, modules)
+	fmt.Fprintf(&svg, `<rect width="%d" height="%d" fill="#ffffff"/>`, modules, modules)
+	svg.WriteString(`<path fill="#000000" d="`)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&svg, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	svg.WriteString(`"/></svg>`)
+
+	return []byte(svg.String()), nil
 }
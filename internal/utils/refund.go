@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"errors"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+)
+
+var ErrCancellationClosed = errors.New("order can no longer be cancelled this close to the showtime")
+
+// CalculateRefund returns the refund of a paid order cancelled by the user, full refund far enough before the showtime, partial until the cutoff
+func CalculateRefund(policy models.RefundPolicy, total float64, untilShowtime time.Duration) (float64, error) {
+	if untilShowtime < time.Duration(policy.CutoffMinutes)*time.Minute {
+		return 0, ErrCancellationClosed
+	}
+	if untilShowtime >= time.Duration(policy.FullRefundMinutes)*time.Minute {
+		return roundPrice(total), nil
+	}
+	return roundPrice(total * policy.PartialPercent / 100), nil
+}
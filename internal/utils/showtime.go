@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"errors"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/models"
+)
+
+var ErrInvalidShowTime = errors.New("invalid time format, must be HH:MM")
+
+// ParseShowTime checks the start time of a screening and returns it as HH:MM, seconds are dropped
+func ParseShowTime(value string) (string, error) {
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.Format("15:04"), nil
+		}
+	}
+	return "", ErrInvalidShowTime
+}
+
+// NormalizeSchedules rewrites the start times of the schedules as HH:MM
+func NormalizeSchedules(schedules []models.Schedule) error {
+	for i := range schedules {
+		showTime, err := ParseShowTime(schedules[i].Time)
+		if err != nil {
+			return err
+		}
+		schedules[i].Time = showTime
+	}
+	return nil
+}
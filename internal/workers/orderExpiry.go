@@ -0,0 +1,84 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// orders expired in one run, the rest is picked up on the next tick
+const orderExpiryBatchSize = 500
+
+// used when the configured interval or payment window is not positive
+const (
+	defaultOrderExpiryInterval = time.Minute
+	defaultPaymentWindow       = 15 * time.Minute
+)
+
+// OrderExpiryWorker expires unpaid orders after the payment window and frees their seats
+type OrderExpiryWorker struct {
+	repo     *repositories.OrdersRepository
+	rdb      *redis.Client
+	window   time.Duration
+	interval time.Duration
+}
+
+func NewOrderExpiryWorker(repo *repositories.OrdersRepository, rdb *redis.Client, window, interval time.Duration) *OrderExpiryWorker {
+	if interval <= 0 {
+		log.Printf("Invalid order expiry interval %s, using default %s", interval, defaultOrderExpiryInterval)
+		interval = defaultOrderExpiryInterval
+	}
+	if window <= 0 {
+		log.Printf("Invalid payment window %s, using default %s", window, defaultPaymentWindow)
+		window = defaultPaymentWindow
+	}
+	return &OrderExpiryWorker{
+		repo:     repo,
+		rdb:      rdb,
+		window:   window,
+		interval: interval,
+	}
+}
+
+// Start runs the worker until the context is done
+func (w *OrderExpiryWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce expires every unpaid order older than the payment window
+func (w *OrderExpiryWorker) RunOnce(ctx context.Context) {
+	for {
+		orderIDs, err := w.repo.ExpireUnpaidOrders(ctx, w.window, orderExpiryBatchSize)
+		if err != nil {
+			log.Println("Expire unpaid orders error:", err)
+			return
+		}
+		if len(orderIDs) == 0 {
+			return
+		}
+
+		log.Printf("Expired %d unpaid orders: %v\n", len(orderIDs), orderIDs)
+		if err := utils.InvalidateCache(ctx, w.rdb, []string{"cinemas:", "users:"}); err != nil {
+			log.Println("Redis delete cache error:", err)
+		}
+
+		if len(orderIDs) < orderExpiryBatchSize {
+			return
+		}
+	}
+}
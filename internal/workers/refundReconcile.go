@@ -0,0 +1,78 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/repositories"
+	"github.com/FebryanHernanda/Tickitz-web-app-BE/internal/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// refunds finished in one run, the rest is picked up on the next tick
+const refundReconcileBatchSize = 100
+
+// time the cancellation request has to finish the order itself after the provider accepted the refund
+const refundReconcileGrace = time.Minute
+
+// used when the configured interval is not positive
+const defaultRefundReconcileInterval = time.Minute
+
+// RefundReconcileWorker refunds the orders whose provider refund was made but whose cancellation failed
+type RefundReconcileWorker struct {
+	repo     *repositories.OrdersRepository
+	rdb      *redis.Client
+	interval time.Duration
+}
+
+func NewRefundReconcileWorker(repo *repositories.OrdersRepository, rdb *redis.Client, interval time.Duration) *RefundReconcileWorker {
+	if interval <= 0 {
+		log.Printf("Invalid refund reconcile interval %s, using default %s", interval, defaultRefundReconcileInterval)
+		interval = defaultRefundReconcileInterval
+	}
+	return &RefundReconcileWorker{
+		repo:     repo,
+		rdb:      rdb,
+		interval: interval,
+	}
+}
+
+// Start runs the worker until the context is done
+func (w *RefundReconcileWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce finishes every refunding order the provider already refunded
+func (w *RefundReconcileWorker) RunOnce(ctx context.Context) {
+	for {
+		orderIDs, err := w.repo.FinishSentRefunds(ctx, refundReconcileGrace, refundReconcileBatchSize)
+		if err != nil {
+			log.Println("Finish sent refunds error:", err)
+			return
+		}
+		if len(orderIDs) == 0 {
+			return
+		}
+
+		log.Printf("Finished %d refunded orders: %v\n", len(orderIDs), orderIDs)
+		if err := utils.InvalidateCache(ctx, w.rdb, []string{"cinemas:", "users:"}); err != nil {
+			log.Println("Redis delete cache error:", err)
+		}
+
+		if len(orderIDs) < refundReconcileBatchSize {
+			return
+		}
+	}
+}